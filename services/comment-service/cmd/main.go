@@ -0,0 +1,26 @@
+package main
+
+import (
+	"commentservice/internal/app"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Comment Service app
+	apiApp := app.NewCommentServiceApp()
+
+	// 2. Start the app (starts HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Comment Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Comment Service...")
+	apiApp.Stop()
+}
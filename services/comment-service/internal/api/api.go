@@ -0,0 +1,273 @@
+package api
+
+import (
+	"commentservice/internal/core/commentmanager"
+	"commentservice/internal/core/feedserviceclient"
+	"commentservice/internal/infra/store"
+	"commentservice/model"
+	"commentservice/utils"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxBatchSize    = 100
+)
+
+type CommentManager interface {
+	CreateComment(userID, postID, parentID, content string) (*model.Comment, error)
+	UpdateComment(userID, commentID, content string) (*model.Comment, error)
+	DeleteComment(userID, commentID string) error
+	ListComments(viewerID, postID string, beforeTime *time.Time, beforeID string, limit int) ([]model.Comment, error)
+	ListReplies(viewerID, commentID string, afterTime *time.Time, afterID string, limit int) ([]model.Comment, error)
+	GetCounts(postIDs []string) (map[string]int64, error)
+}
+
+type CommentAPI struct {
+	CommentInterface CommentManager
+}
+
+func NewCommentAPI(commentInterface CommentManager) *CommentAPI {
+	return &CommentAPI{CommentInterface: commentInterface}
+}
+
+func (api *CommentAPI) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/posts/{post_id}/comments", api.handleCreateComment).Methods("POST")
+	r.HandleFunc("/posts/{post_id}/comments", api.handleListComments).Methods("GET")
+	r.HandleFunc("/comments/{comment_id}/replies", api.handleListReplies).Methods("GET")
+	r.HandleFunc("/comments/{comment_id}", api.handleUpdateComment).Methods("PATCH")
+	r.HandleFunc("/comments/{comment_id}", api.handleDeleteComment).Methods("DELETE")
+	// internal: feed-service lấy số comment cho 1 trang feed
+	r.HandleFunc("/comments/counts", api.handleGetCounts).Methods("GET")
+}
+
+func (api *CommentAPI) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Content  string `json:"content"`
+		ParentID string `json:"parent_id"`
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	content, ok := validContent(req.Content)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid content")
+		return
+	}
+
+	comment, err := api.CommentInterface.CreateComment(userID, mux.Vars(r)["post_id"], req.ParentID, content)
+	switch {
+	case errors.Is(err, feedserviceclient.ErrPostNotFound):
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+	case errors.Is(err, store.ErrCommentNotFound):
+		utils.WriteError(w, http.StatusNotFound, "parent comment not found")
+	case errors.Is(err, commentmanager.ErrParentMismatch):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, "failed to create comment: "+err.Error())
+	default:
+		utils.WriteJSON(w, http.StatusCreated, comment)
+	}
+}
+
+func (api *CommentAPI) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Content string `json:"content"`
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	content, ok := validContent(req.Content)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid content")
+		return
+	}
+
+	comment, err := api.CommentInterface.UpdateComment(userID, mux.Vars(r)["comment_id"], content)
+	if err != nil {
+		writeModifyError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, comment)
+}
+
+func (api *CommentAPI) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	if err := api.CommentInterface.DeleteComment(userID, mux.Vars(r)["comment_id"]); err != nil {
+		writeModifyError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "Comment soft deleted"})
+}
+
+// handleListComments - GET /posts/{post_id}/comments?before=&limit= (mới nhất trước)
+func (api *CommentAPI) handleListComments(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	cursorTime, cursorID, ok := parseCursor(r.URL.Query().Get("before"))
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+
+	comments, err := api.CommentInterface.ListComments(r.Header.Get("X-User-ID"), mux.Vars(r)["post_id"], cursorTime, cursorID, limit)
+	if errors.Is(err, feedserviceclient.ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to list comments: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, pageResponse(comments, limit))
+}
+
+// handleListReplies - GET /comments/{comment_id}/replies?after=&limit= (cũ nhất trước)
+func (api *CommentAPI) handleListReplies(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	cursorTime, cursorID, ok := parseCursor(r.URL.Query().Get("after"))
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+
+	replies, err := api.CommentInterface.ListReplies(r.Header.Get("X-User-ID"), mux.Vars(r)["comment_id"], cursorTime, cursorID, limit)
+	if errors.Is(err, feedserviceclient.ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+	if errors.Is(err, store.ErrCommentNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to list replies: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, pageResponse(replies, limit))
+}
+
+// handleGetCounts - GET /comments/counts?post_ids=a,b,c
+func (api *CommentAPI) handleGetCounts(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("post_ids")
+	if raw == "" {
+		utils.WriteError(w, http.StatusBadRequest, "post_ids is required")
+		return
+	}
+	postIDs := strings.Split(raw, ",")
+	if len(postIDs) > maxBatchSize {
+		utils.WriteError(w, http.StatusBadRequest, "too many post_ids (max "+strconv.Itoa(maxBatchSize)+")")
+		return
+	}
+
+	counts, err := api.CommentInterface.GetCounts(postIDs)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{"counts": counts})
+}
+
+func writeModifyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrCommentNotFound):
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+	case errors.Is(err, commentmanager.ErrNotCommentAuthor), errors.Is(err, commentmanager.ErrCannotDelete):
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func validContent(raw string) (string, bool) {
+	content := strings.TrimSpace(raw)
+	if content == "" || utf8.RuneCountInString(content) > model.MaxContentLength {
+		return "", false
+	}
+	return content, true
+}
+
+func parseLimit(r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageSize, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return min(n, maxPageSize), true
+}
+
+func pageResponse(comments []model.Comment, limit int) map[string]interface{} {
+	resp := map[string]interface{}{"comments": comments}
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		resp["next_cursor"] = encodeCursor(last.CreatedAt, last.CommentID)
+	}
+	return resp
+}
+
+// cursor = base64("<created_at RFC3339Nano>|<comment_id>"), rỗng = trang đầu
+func encodeCursor(t time.Time, commentID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano) + "|" + commentID))
+}
+
+func parseCursor(s string) (*time.Time, string, bool) {
+	if s == "" {
+		return nil, "", true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, "", false
+	}
+	ts, commentID, ok := strings.Cut(string(raw), "|")
+	if !ok || commentID == "" {
+		return nil, "", false
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, "", false
+	}
+	return &t, commentID, true
+}
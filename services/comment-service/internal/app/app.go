@@ -0,0 +1,78 @@
+package app
+
+import (
+	"commentservice/internal/api"
+	"commentservice/internal/core/commentcounter"
+	"commentservice/internal/core/commentevent"
+	"commentservice/internal/core/commentmanager"
+	"commentservice/internal/core/feedserviceclient"
+	"commentservice/internal/core/http-server/server"
+	"commentservice/internal/infra/redisclient"
+	"commentservice/internal/infra/store"
+	"log"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver *server.HttpServer
+	commentapi *api.CommentAPI
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DBNumber int
+}
+
+func NewCommentServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+func (a *App) Stop() {
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	dbcfg := &store.PostGresConfig{
+		Host:     "localhost", // IP
+		Port:     "5432",      // Port
+		User:     "taopq",     // user_name
+		Password: "123456a@",  // password
+		DBname:   "mydb",      // db
+	}
+
+	redisstorecfg := &RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	commentstore := store.NewCommentStore(dbcfg)
+
+	a.commentapi = api.NewCommentAPI(commentmanager.NewCommentManager(
+		commentstore,
+		commentcounter.NewCommentCounter(commentstore, rc, 24*time.Hour),
+		feedserviceclient.NewFeedServiceClient("http://localhost:9092"),
+		commentevent.NewPublisher(rc),
+	))
+	router := mux.NewRouter()
+	a.commentapi.RegisterRoutes(router)
+	a.httpserver = server.NewHttpServer("localhost:9094", router)
+}
@@ -0,0 +1,109 @@
+package commentcounter
+
+import (
+	"commentservice/internal/infra/redisclient"
+	"commentservice/model"
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CountStore interface {
+	CountByPosts(postIDs []string) (map[string]int64, error)
+}
+
+// incrScript chỉ INCRBY khi counter đang có trong cache, cache miss thì
+// lần đọc sau tự đếm lại từ Postgres.
+//
+//	KEYS[1] = post:{id}:comments, ARGV[1] = delta
+var incrScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('INCRBY', KEYS[1], ARGV[1])
+return 1
+`)
+
+// CommentCounter giữ số comment của mỗi post ở post:{id}:comments.
+// Counter có TTL nên nếu lệch sẽ tự sửa sau khi expire.
+type CommentCounter struct {
+	store       CountStore
+	redisclient *redisclient.RedisClient
+	ttl         time.Duration
+}
+
+func NewCommentCounter(store_ CountStore, redisclient_ *redisclient.RedisClient, ttl time.Duration) *CommentCounter {
+	return &CommentCounter{
+		store:       store_,
+		redisclient: redisclient_,
+		ttl:         ttl,
+	}
+}
+
+func (c *CommentCounter) Add(postID string, delta int64) {
+	err := incrScript.Run(context.Background(), c.redisclient.GetClient(), []string{model.CommentCountKey(postID)}, delta).Err()
+	if err != nil {
+		log.Printf("[CommentCounter] failed to update counter of post %s: %v", postID, err)
+	}
+}
+
+// GetCounts đọc counter của nhiều post bằng 1 lệnh MGET, post chưa có cache thì đếm từ Postgres
+func (c *CommentCounter) GetCounts(postIDs []string) (map[string]int64, error) {
+	ctx := context.Background()
+	result := make(map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(postIDs))
+	for i, postID := range postIDs {
+		keys[i] = model.CommentCountKey(postID)
+	}
+	values, err := c.redisclient.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("[CommentCounter] failed to read counters: %v", err)
+		values = make([]interface{}, len(postIDs))
+	}
+
+	var misses []string
+	for i, postID := range postIDs {
+		raw, ok := values[i].(string)
+		if !ok {
+			misses = append(misses, postID)
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			misses = append(misses, postID)
+			continue
+		}
+		result[postID] = max(n, 0)
+	}
+
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := c.store.CountByPosts(misses)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, postID := range misses {
+			// SETNX: không ghi đè counter vừa được tạo bởi request khác
+			pipe.SetNX(ctx, model.CommentCountKey(postID), loaded[postID], c.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[CommentCounter] failed to cache counters: %v", err)
+	}
+
+	for _, postID := range misses {
+		result[postID] = loaded[postID]
+	}
+	return result, nil
+}
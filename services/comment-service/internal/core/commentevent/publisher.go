@@ -0,0 +1,49 @@
+package commentevent
+
+import (
+	"commentservice/internal/infra/redisclient"
+	"commentservice/model"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamKey - Redis Stream chứa comment events
+const StreamKey = "comment:events"
+
+// maxStreamLen giới hạn (xấp xỉ) số event giữ lại trong stream
+const maxStreamLen = 100000
+
+type Publisher struct {
+	redisclient *redisclient.RedisClient
+}
+
+func NewPublisher(redisclient_ *redisclient.RedisClient) *Publisher {
+	return &Publisher{
+		redisclient: redisclient_,
+	}
+}
+
+// Publish append event vào stream, consumer group của từng service tự đọc
+func (p *Publisher) Publish(event model.CommentEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("[CommentEventPublisher] failed to marshal event: %w", err)
+	}
+
+	err = p.redisclient.GetClient().XAdd(context.Background(), &redis.XAddArgs{
+		Stream: StreamKey,
+		MaxLen: maxStreamLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":    event.Type,
+			"payload": payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("[CommentEventPublisher] failed to publish %s: %w", event.Type, err)
+	}
+	return nil
+}
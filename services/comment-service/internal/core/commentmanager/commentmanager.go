@@ -0,0 +1,202 @@
+package commentmanager
+
+import (
+	"commentservice/internal/core/commentevent"
+	"commentservice/internal/core/feedserviceclient"
+	"commentservice/internal/infra/store"
+	"commentservice/model"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotCommentAuthor - chỉ author mới được sửa comment
+	ErrNotCommentAuthor = errors.New("only the author can edit this comment")
+	// ErrCannotDelete - chỉ author của comment hoặc chủ post mới được xoá
+	ErrCannotDelete = errors.New("only the comment author or the post owner can delete this comment")
+	// ErrParentMismatch - reply vào comment của post khác
+	ErrParentMismatch = errors.New("parent comment does not belong to this post")
+)
+
+type CommentCounter interface {
+	Add(postID string, delta int64)
+	GetCounts(postIDs []string) (map[string]int64, error)
+}
+
+type CommentManager struct {
+	CommentStore      *store.CommentStore
+	counter           CommentCounter
+	feedserviceclient *feedserviceclient.FeedServiceClient
+	publisher         *commentevent.Publisher
+}
+
+func NewCommentManager(commentStore *store.CommentStore, counter_ CommentCounter,
+	feedserviceclient_ *feedserviceclient.FeedServiceClient, publisher_ *commentevent.Publisher) *CommentManager {
+	return &CommentManager{
+		CommentStore:      commentStore,
+		counter:           counter_,
+		feedserviceclient: feedserviceclient_,
+		publisher:         publisher_,
+	}
+}
+
+// CreateComment tạo comment gốc (parentID rỗng) hoặc reply.
+// Reply chỉ có 1 cấp: reply vào 1 reply sẽ được gắn vào comment gốc của nó.
+func (m *CommentManager) CreateComment(userID, postID, parentID, content string) (*model.Comment, error) {
+	post, err := m.feedserviceclient.GetPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := m.CommentStore.DBClient.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	comment := &model.Comment{
+		CommentID: uuid.New().String(),
+		PostID:    postID,
+		UserID:    userID,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+	event := model.CommentEvent{
+		Type:         model.CommentCreated,
+		CommentID:    comment.CommentID,
+		PostID:       postID,
+		PostAuthorID: post.Author.UserID,
+		UserID:       userID,
+		OccurredAt:   comment.CreatedAt,
+	}
+
+	if parentID != "" {
+		parent, err := m.CommentStore.LockComment(tx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			if parent, err = m.CommentStore.LockComment(tx, *parent.ParentID); err != nil {
+				return nil, err
+			}
+		}
+		if parent.PostID != postID {
+			return nil, ErrParentMismatch
+		}
+		comment.ParentID = &parent.CommentID
+		event.ParentID, event.ParentAuthorID = parent.CommentID, parent.UserID
+		if err := m.CommentStore.AddReplyCount(tx, parent.CommentID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.CommentStore.InsertComment(tx, comment); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit comment: %w", err)
+	}
+
+	m.counter.Add(postID, 1)
+	// comment đã lưu, publish lỗi chỉ mất notification
+	if err := m.publisher.Publish(event); err != nil {
+		log.Printf("[CommentManager] %v", err)
+	}
+	return comment, nil
+}
+
+// UpdateComment sửa content, chỉ author được sửa
+func (m *CommentManager) UpdateComment(userID, commentID, content string) (*model.Comment, error) {
+	tx, err := m.CommentStore.DBClient.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	comment, err := m.CommentStore.LockComment(tx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	updatedAt, err := m.CommentStore.UpdateContent(tx, commentID, content)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit update of comment %s: %w", commentID, err)
+	}
+
+	comment.Content = content
+	comment.UpdatedAt = &updatedAt
+	return comment, nil
+}
+
+// DeleteComment soft delete comment, author của comment hoặc chủ post được xoá
+func (m *CommentManager) DeleteComment(userID, commentID string) error {
+	comment, err := m.CommentStore.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		// quyền không đổi theo thời gian nên check ngoài transaction, tránh giữ lock khi gọi HTTP
+		post, err := m.feedserviceclient.GetPost(userID, comment.PostID)
+		if err != nil && !errors.Is(err, feedserviceclient.ErrPostNotFound) {
+			return err
+		}
+		if err != nil || post.Author.UserID != userID {
+			return ErrCannotDelete
+		}
+	}
+
+	tx, err := m.CommentStore.DBClient.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := m.CommentStore.SoftDeleteComment(tx, commentID); err != nil {
+		return err
+	}
+	if comment.ParentID != nil {
+		if err := m.CommentStore.AddReplyCount(tx, *comment.ParentID, -1); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delete of comment %s: %w", commentID, err)
+	}
+
+	m.counter.Add(comment.PostID, -1)
+	return nil
+}
+
+// ListComments - comment gốc của post, feedserviceclient.ErrPostNotFound nếu viewer không được xem post
+func (m *CommentManager) ListComments(viewerID, postID string, beforeTime *time.Time, beforeID string, limit int) ([]model.Comment, error) {
+	if _, err := m.feedserviceclient.GetPost(viewerID, postID); err != nil {
+		return nil, err
+	}
+	return m.CommentStore.ListComments(postID, beforeTime, beforeID, limit)
+}
+
+// ListReplies - reply của 1 comment gốc (vẫn đọc được khi comment gốc đã xoá),
+// cùng kiểm tra visibility của post như ListComments
+func (m *CommentManager) ListReplies(viewerID, commentID string, afterTime *time.Time, afterID string, limit int) ([]model.Comment, error) {
+	postID, err := m.CommentStore.GetPostIDOfComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.feedserviceclient.GetPost(viewerID, postID); err != nil {
+		return nil, err
+	}
+	return m.CommentStore.ListReplies(commentID, afterTime, afterID, limit)
+}
+
+func (m *CommentManager) GetCounts(postIDs []string) (map[string]int64, error) {
+	return m.counter.GetCounts(postIDs)
+}
@@ -0,0 +1,67 @@
+package feedserviceclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrPostNotFound - post không tồn tại, đã bị xoá hoặc viewer không được xem (visibility)
+var ErrPostNotFound = errors.New("post not found")
+
+type FeedServiceClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewFeedServiceClient(baseURL string) *FeedServiceClient {
+	return &FeedServiceClient{
+		BaseURL: baseURL,
+		Client:  &http.Client{},
+	}
+}
+
+type Post struct {
+	PostID string `json:"post_id"`
+	Author struct {
+		UserID string `json:"user_id"`
+	} `json:"author"`
+}
+
+// GetPost gọi GET /posts/{post_id} của feed-service thay mặt viewerID (user đang thao tác),
+// feed-service kiểm tra visibility theo viewer. ErrPostNotFound nếu 404
+func (c *FeedServiceClient) GetPost(viewerID, postID string) (Post, error) {
+	url := fmt.Sprintf("%s/posts/%s", c.BaseURL, postID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Post{}, fmt.Errorf("[FeedServiceClient] failed to build get request: %w", err)
+	}
+	req.Header.Set("X-User-ID", viewerID)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return Post{}, fmt.Errorf("[FeedServiceClient] failed to call feed service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Post{}, ErrPostNotFound
+	default:
+		return Post{}, fmt.Errorf("[FeedServiceClient] unexpected status code: %d", resp.StatusCode)
+	}
+
+	var post Post
+	if err := json.NewDecoder(resp.Body).Decode(&post); err != nil {
+		return Post{}, fmt.Errorf("[FeedServiceClient] failed to decode response: %w", err)
+	}
+	return post, nil
+}
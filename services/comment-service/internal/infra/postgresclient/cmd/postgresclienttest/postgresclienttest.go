@@ -0,0 +1,39 @@
+package main
+
+import (
+	dbclient "commentservice/internal/infra/postgresclient"
+	"commentservice/internal/infra/postgresclient/tables"
+	"fmt"
+	"log"
+)
+
+func main() {
+	client := dbclient.NewPostgresClient(
+		"localhost", // IP
+		"5432",      // Port
+		"taopq",     // user_name
+		"123456a@",  // password
+		"mydb",      // db
+	)
+	defer client.Close()
+
+	// Tạo bảng comments
+	commentsTable := tables.NewCommentsTable(client)
+
+	if !client.SearchTable(commentsTable.TableName) {
+		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", commentsTable.TableName)
+		commentsTable.CreateTable()
+	} else {
+		fmt.Printf("%s EXISTED\n", commentsTable.TableName)
+		commentsTable.CreateIndexes()
+	}
+
+	// Lấy tất cả comments
+	rows, err := commentsTable.GetAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Println(row)
+	}
+}
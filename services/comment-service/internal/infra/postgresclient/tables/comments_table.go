@@ -0,0 +1,38 @@
+package tables
+
+import dbclient "commentservice/internal/infra/postgresclient"
+
+// CommentsTable kế thừa BaseTable
+type CommentsTable struct {
+	dbclient.BaseTable
+}
+
+// NewCommentsTable khởi tạo table comments. Reply chỉ có 1 cấp:
+// parent_id luôn trỏ tới comment gốc (parent_id IS NULL) của cùng post.
+func NewCommentsTable(client *dbclient.PostgresClient) *CommentsTable {
+	return &CommentsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "comments",
+			Columns: map[string]string{
+				"comment_id":  "UUID PRIMARY KEY",
+				"post_id":     "UUID NOT NULL",
+				"parent_id":   "UUID",
+				"user_id":     "UUID NOT NULL",
+				"content":     "TEXT NOT NULL",
+				"reply_count": "INT NOT NULL DEFAULT 0", // số reply chưa xoá
+				"created_at":  "TIMESTAMP NOT NULL DEFAULT now()",
+				"updated_at":  "TIMESTAMP",
+				"is_deleted":  "BOOLEAN NOT NULL DEFAULT FALSE",
+				"deleted_at":  "TIMESTAMP",
+			},
+			Constraints: []string{
+				"FOREIGN KEY (parent_id) REFERENCES comments(comment_id) ON DELETE CASCADE",
+			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_comments_post_created ON comments(post_id, created_at DESC) WHERE parent_id IS NULL",
+				"CREATE INDEX IF NOT EXISTS idx_comments_parent_created ON comments(parent_id, created_at)",
+			},
+		},
+	}
+}
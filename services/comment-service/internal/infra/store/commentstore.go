@@ -0,0 +1,244 @@
+package store
+
+import (
+	"commentservice/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	dbclient "commentservice/internal/infra/postgresclient"
+
+	"github.com/lib/pq"
+)
+
+// ErrCommentNotFound - comment không tồn tại hoặc đã bị xoá
+var ErrCommentNotFound = errors.New("comment not found")
+
+type CommentStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+type PostGresConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBname   string
+}
+
+func NewCommentStore(postgrescfg *PostGresConfig) *CommentStore {
+	return &CommentStore{
+		DBClient: dbclient.NewPostgresClient(
+			postgrescfg.Host,
+			postgrescfg.Port,
+			postgrescfg.User,
+			postgrescfg.Password,
+			postgrescfg.DBname,
+		),
+	}
+}
+
+const commentColumns = `comment_id, post_id, parent_id, user_id, content, reply_count, created_at, updated_at, is_deleted`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComment đọc 1 row theo thứ tự commentColumns.
+// Comment đã xoá (vẫn hiện vì còn reply) thì không trả content.
+func scanComment(row rowScanner) (*model.Comment, error) {
+	c := &model.Comment{}
+	var parentID sql.NullString
+	var updatedAt sql.NullTime
+	if err := row.Scan(&c.CommentID, &c.PostID, &parentID, &c.UserID, &c.Content, &c.ReplyCount,
+		&c.CreatedAt, &updatedAt, &c.IsDeleted); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		c.ParentID = &parentID.String
+	}
+	if updatedAt.Valid {
+		c.UpdatedAt = &updatedAt.Time
+	}
+	if c.IsDeleted {
+		c.Content = ""
+	}
+	return c, nil
+}
+
+func scanComments(rows *sql.Rows) ([]model.Comment, error) {
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return comments, nil
+}
+
+// GetComment đọc 1 comment chưa xoá
+func (s *CommentStore) GetComment(commentID string) (*model.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE comment_id = $1 AND is_deleted = FALSE`
+
+	c, err := scanComment(s.DBClient.DB.QueryRow(query, commentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment %s: %w", commentID, err)
+	}
+	return c, nil
+}
+
+// GetPostIDOfComment - post chứa comment, kể cả comment đã xoá (reply của comment đã xoá vẫn đọc được)
+func (s *CommentStore) GetPostIDOfComment(commentID string) (string, error) {
+	var postID string
+	err := s.DBClient.DB.QueryRow(`SELECT post_id FROM comments WHERE comment_id = $1`, commentID).Scan(&postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrCommentNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get post of comment %s: %w", commentID, err)
+	}
+	return postID, nil
+}
+
+// LockComment đọc và khoá row comment chưa xoá (FOR UPDATE) trong transaction
+func (s *CommentStore) LockComment(tx *sql.Tx, commentID string) (*model.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE comment_id = $1 AND is_deleted = FALSE FOR UPDATE`
+
+	c, err := scanComment(tx.QueryRow(query, commentID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock comment %s: %w", commentID, err)
+	}
+	return c, nil
+}
+
+func (s *CommentStore) InsertComment(tx *sql.Tx, c *model.Comment) error {
+	query := `
+		INSERT INTO comments (comment_id, post_id, parent_id, user_id, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := tx.Exec(query, c.CommentID, c.PostID, c.ParentID, c.UserID, c.Content, c.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert comment: %w", err)
+	}
+	return nil
+}
+
+// AddReplyCount cộng delta vào reply_count của comment gốc
+func (s *CommentStore) AddReplyCount(tx *sql.Tx, parentID string, delta int) error {
+	query := `UPDATE comments SET reply_count = GREATEST(reply_count + $2, 0) WHERE comment_id = $1`
+	if _, err := tx.Exec(query, parentID, delta); err != nil {
+		return fmt.Errorf("failed to update reply count of %s: %w", parentID, err)
+	}
+	return nil
+}
+
+func (s *CommentStore) UpdateContent(tx *sql.Tx, commentID, content string) (time.Time, error) {
+	var updatedAt time.Time
+	query := `UPDATE comments SET content = $2, updated_at = now() WHERE comment_id = $1 RETURNING updated_at`
+	if err := tx.QueryRow(query, commentID, content).Scan(&updatedAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to update comment %s: %w", commentID, err)
+	}
+	return updatedAt, nil
+}
+
+// SoftDeleteComment đánh dấu is_deleted. Comment gốc còn reply vẫn được liệt kê
+// (không có content) để giữ thread.
+func (s *CommentStore) SoftDeleteComment(tx *sql.Tx, commentID string) error {
+	query := `UPDATE comments SET is_deleted = TRUE, deleted_at = now() WHERE comment_id = $1 AND is_deleted = FALSE`
+	res, err := tx.Exec(query, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment %s: %w", commentID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// ListComments comment gốc của post, mới nhất trước.
+// Keyset theo (created_at, comment_id) của phần tử cuối trang trước.
+func (s *CommentStore) ListComments(postID string, beforeTime *time.Time, beforeID string, limit int) ([]model.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE post_id = $1 AND parent_id IS NULL
+		  AND (is_deleted = FALSE OR reply_count > 0)
+		  AND ($2::timestamp IS NULL OR (created_at, comment_id::text) < ($2, $3))
+		ORDER BY created_at DESC, comment_id::text DESC
+		LIMIT $4`
+
+	var before interface{}
+	if beforeTime != nil {
+		before = *beforeTime
+	}
+	rows, err := s.DBClient.DB.Query(query, postID, before, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments of %s: %w", postID, err)
+	}
+	return scanComments(rows)
+}
+
+// ListReplies reply của 1 comment gốc, cũ nhất trước (đọc như hội thoại).
+// Keyset theo (created_at, comment_id) của phần tử cuối trang trước.
+func (s *CommentStore) ListReplies(parentID string, afterTime *time.Time, afterID string, limit int) ([]model.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE parent_id = $1 AND is_deleted = FALSE
+		  AND ($2::timestamp IS NULL OR (created_at, comment_id::text) > ($2, $3))
+		ORDER BY created_at ASC, comment_id::text ASC
+		LIMIT $4`
+
+	var after interface{}
+	if afterTime != nil {
+		after = *afterTime
+	}
+	rows, err := s.DBClient.DB.Query(query, parentID, after, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replies of %s: %w", parentID, err)
+	}
+	return scanComments(rows)
+}
+
+// CountByPosts đếm comment (cả reply) chưa xoá của nhiều post, dùng khi cache counter miss
+func (s *CommentStore) CountByPosts(postIDs []string) (map[string]int64, error) {
+	result := make(map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.DBClient.DB.Query(`
+		SELECT post_id, COUNT(*)
+		FROM comments
+		WHERE post_id = ANY($1) AND is_deleted = FALSE
+		GROUP BY post_id`, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var count int64
+		if err := rows.Scan(&postID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		result[postID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// MaxContentLength - số ký tự tối đa của 1 comment
+const MaxContentLength = 2000
+
+type Comment struct {
+	CommentID  string     `json:"comment_id"`
+	PostID     string     `json:"post_id"`
+	ParentID   *string    `json:"parent_id,omitempty"`
+	UserID     string     `json:"user_id"`
+	Content    string     `json:"content"`
+	ReplyCount int        `json:"reply_count"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	IsDeleted  bool       `json:"is_deleted"`
+}
+
+// CommentCountKey - counter số comment (cả reply) chưa xoá của 1 post
+func CommentCountKey(postID string) string {
+	return "post:" + postID + ":comments"
+}
+
+// ---- Events ----
+const CommentCreated = "CommentCreated"
+
+// CommentEvent được publish khi có comment/reply mới (notification-service consume).
+// ParentID/ParentAuthorID chỉ có với reply.
+type CommentEvent struct {
+	Type           string    `json:"type"`
+	CommentID      string    `json:"comment_id"`
+	PostID         string    `json:"post_id"`
+	PostAuthorID   string    `json:"post_author_id"`
+	ParentID       string    `json:"parent_id,omitempty"`
+	ParentAuthorID string    `json:"parent_author_id,omitempty"`
+	UserID         string    `json:"user_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,93 @@
+package main
+
+// CLI inspect/replay dead letters của fan-out group trên post events (hoặc stream/group bất kỳ qua -stream, -group).
+// Replay chỉ được group đó xử lý lại, các group khác cùng đọc stream bỏ qua message.
+//
+//	go run ./cmd/deadletters list -n 20
+//	go run ./cmd/deadletters replay <dead-letter-id> [<id> ...]
+//	go run ./cmd/deadletters replay-all
+
+import (
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/model"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: deadletters [flags] list|replay <id>...|replay-all\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:6379", "redis address")
+	password := flag.String("password", "", "redis password")
+	db := flag.Int("db", 0, "redis db number")
+	stream := flag.String("stream", model.PostEventStream, "source stream")
+	group := flag.String("group", "feed-service-fanout", "consumer group whose dead letters to list/replay")
+	count := flag.Int64("n", 100, "max dead letters to list/replay")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+	}
+
+	rc := redisclient.InitSingleton(*addr, *password, *db)
+	defer rc.Close()
+	dl := eventstream.NewDeadLetters(rc, *stream, *group)
+
+	switch flag.Arg(0) {
+	case "list":
+		msgs, err := dl.List(*count)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d dead letter(s) in %s\n", len(msgs), dl.Key())
+		for _, msg := range msgs {
+			fmt.Printf("\n== %s\n", msg.ID)
+			keys := make([]string, 0, len(msg.Values))
+			for k := range msg.Values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Printf("  %-14s %v\n", k, msg.Values[k])
+			}
+		}
+
+	case "replay":
+		if flag.NArg() < 2 {
+			usage()
+		}
+		for _, id := range flag.Args()[1:] {
+			replay(dl, id)
+		}
+
+	case "replay-all":
+		msgs, err := dl.List(*count)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, msg := range msgs {
+			replay(dl, msg.ID)
+		}
+
+	default:
+		usage()
+	}
+}
+
+func replay(dl *eventstream.DeadLetters, id string) {
+	newID, err := dl.Replay(id)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+	fmt.Printf("✅ replayed %s as %s\n", id, newID)
+}
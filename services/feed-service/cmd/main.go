@@ -0,0 +1,26 @@
+package main
+
+import (
+	"feedservice/internal/app"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Feed Service app
+	apiApp := app.NewFeedServiceApp()
+
+	// 2. Start the app (starts workers + HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Feed Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Feed Service...")
+	apiApp.Stop()
+}
@@ -0,0 +1,47 @@
+package main
+
+// media-edge local: serve URL do mediaurl.SignCDN tạo (Mode: mediaurl.ModeCDN trong app.go)
+//
+//	go run ./cmd/mediaedge
+//	curl -I "http://localhost:9097/media/{object_key}?exp=...&sig=..."
+
+import (
+	"context"
+	"feedservice/internal/infra/mediaurl"
+	"feedservice/internal/infra/s3client"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":9097", "listen address")
+	prefix := flag.String("prefix", "/media/", "path prefix of CDNBaseURL")
+	secret := flag.String("secret", "dev-media-edge-secret", "HMAC secret shared with feed-service (CDNSecret)")
+	s3Endpoint := flag.String("s3-endpoint", "http://localhost:9100", "s3/minio endpoint")
+	bucket := flag.String("bucket", "facebook-clone-media", "media bucket")
+	flag.Parse()
+
+	objectstore, err := s3client.NewS3Client(context.Background(), s3client.Config{
+		Endpoint:     *s3Endpoint,
+		Bucket:       *bucket,
+		Region:       "us-east-1",
+		AccessKey:    "minioadmin",
+		SecretKey:    "minioadmin",
+		UsePathStyle: true,
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mediaurl.NewEdgeHandler(objectstore, []byte(*secret), *prefix),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("🚀 Media edge listening on %s%s", *addr, *prefix)
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+// CLI chạy MediaJanitor 1 lần, mặc định dry run (chỉ liệt kê những gì sẽ bị xoá)
+//
+//	go run ./cmd/mediajanitor
+//	go run ./cmd/mediajanitor -dry-run=false -retention 48h
+
+import (
+	"context"
+	"feedservice/internal/core/mediajanitor"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", true, "only report what would be deleted")
+	retention := flag.Duration("retention", 24*time.Hour, "delete pending/failed media created before now - retention")
+	orphanGrace := flag.Duration("orphan-grace", 24*time.Hour, "delete unreferenced objects older than this")
+	batch := flag.Int("batch", 500, "media rows per batch")
+	timeout := flag.Duration("timeout", 30*time.Minute, "max run time")
+	dbHost := flag.String("db-host", "localhost", "postgres host")
+	s3Endpoint := flag.String("s3-endpoint", "http://localhost:9100", "s3/minio endpoint")
+	bucket := flag.String("bucket", "facebook-clone-media", "media bucket")
+	flag.Parse()
+
+	objectstore, err := s3client.NewS3Client(context.Background(), s3client.Config{
+		Endpoint:     *s3Endpoint,
+		Bucket:       *bucket,
+		Region:       "us-east-1",
+		AccessKey:    "minioadmin",
+		SecretKey:    "minioadmin",
+		UsePathStyle: true,
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	mediastore := store.NewMediaStore(&store.PostGresConfig{
+		Host:     *dbHost,
+		Port:     "5432",
+		User:     "taopq",
+		Password: "123456a@",
+		DBname:   "mydb",
+	}, objectstore, nil)
+
+	janitor := mediajanitor.NewMediaJanitor(mediastore, mediajanitor.JanitorConfig{
+		Retention:   *retention,
+		OrphanGrace: *orphanGrace,
+		BatchSize:   *batch,
+		Timeout:     *timeout,
+		DryRun:      *dryRun,
+	})
+	report, err := janitor.Run(context.Background())
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	verb := "deleted"
+	if *dryRun {
+		verb = "would delete"
+	}
+	fmt.Printf("✅ %s %d medias (%d objects), %d orphan objects (%d bytes)\n",
+		verb, report.Media, report.MediaObjects, report.Orphans, report.OrphanBytes)
+}
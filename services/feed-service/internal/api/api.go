@@ -2,42 +2,441 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"feedservice/internal/core/mediaverifier"
+	"feedservice/internal/core/postmanager"
+	"feedservice/internal/core/scrollingfeedmanager"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/infra/trending"
 	"feedservice/internal/model"
 	"feedservice/utils"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	defaultTrendingWindow = "24h"
+	defaultTrendingLimit  = 10
+	maxTrendingLimit      = 50
+)
+
 type FanoutManager interface {
 }
 
 type PostManager interface {
-	CreateMedia(userID string, mediatype string, mediafilename string) (model.Media, error)
-	CreatePost(userID string, content string, mediaIDs []string) (string, error)
+	CreateMedia(userID string, mediatype string, mediafilename string, contentType string) (model.MediaUpload, error)
+	CreateMultipartMedia(userID, mediatype, mediafilename, contentType string, size int64) (model.MediaUpload, error)
+	GetUploadParts(userID, mediaID string) (model.MultipartUpload, error)
+	CompleteUpload(userID, mediaID string, parts []model.UploadPart) error
+	AbortUpload(userID, mediaID string) error
+	CreatePost(userID string, content string, mediaIDs []string, visibility string) (string, error)
+	SharePost(userID, postID, content, visibility string) (string, error)
+	UpdatePost(userID string, postID string, content *string, visibility string) error
+	DeletePost(userID string, postID string) error
+	GetRevisions(viewerID, postID string) ([]store.PostRevision, error)
+	ListCloseFriends(userID string) ([]store.CloseFriend, error)
+	AddCloseFriend(userID, friendID string) error
+	RemoveCloseFriend(userID, friendID string) error
+}
+
+type ScrollingFeedManager interface {
+	ScrollingFeed(userID string, before string, limit int64) (scrollingfeedmanager.FeedResponse, error)
+	NewPostsCount(userID string, since string) (int64, error)
+	GetPost(viewerID, postID string) (scrollingfeedmanager.FeedItem, error)
+	GetUserPosts(viewerID, userID string, before string, limit int64) (scrollingfeedmanager.PostsResponse, error)
+	GetHashtagPosts(viewerID, tag string, before string, limit int64) (scrollingfeedmanager.PostsResponse, error)
+	TrendingHashtags(window time.Duration, limit int64) ([]trending.HashtagCount, error)
 }
 
 type FeedAPI struct {
 	FanoutInterface FanoutManager
 	PostInteface    PostManager
+	FeedInterface   ScrollingFeedManager
 }
 
-func NewFeedAPI(fanoutInterface FanoutManager, postInteface PostManager) *FeedAPI {
+func NewFeedAPI(fanoutInterface FanoutManager, postInteface PostManager, feedInterface ScrollingFeedManager) *FeedAPI {
 	return &FeedAPI{
 		FanoutInterface: fanoutInterface,
 		PostInteface:    postInteface,
+		FeedInterface:   feedInterface,
 	}
 }
 
 func (api *FeedAPI) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/media", api.handleCreateMedia).Methods("POST")
+	r.HandleFunc("/media/{media_id}/parts", api.handleGetUploadParts).Methods("GET")
+	r.HandleFunc("/media/{media_id}/complete", api.handleCompleteUpload).Methods("POST")
+	r.HandleFunc("/media/{media_id}/abort", api.handleAbortUpload).Methods("POST")
 	r.HandleFunc("/posts", api.handleCreatePost).Methods("POST")
+	r.HandleFunc("/posts/{post_id}", api.handleGetPost).Methods("GET")
+	r.HandleFunc("/posts/{post_id}", api.handleUpdatePost).Methods("PATCH")
+	r.HandleFunc("/posts/{post_id}", api.handleDeletePost).Methods("DELETE")
+	r.HandleFunc("/posts/{post_id}/revisions", api.handleGetRevisions).Methods("GET")
+	r.HandleFunc("/posts/{post_id}/share", api.handleSharePost).Methods("POST")
+	r.HandleFunc("/users/{user_id}/posts", api.handleGetUserPosts).Methods("GET")
+	r.HandleFunc("/me/posts", api.handleGetOwnPosts).Methods("GET")
+	r.HandleFunc("/me/close-friends", api.handleListCloseFriends).Methods("GET")
+	r.HandleFunc("/me/close-friends/{user_id}", api.handleAddCloseFriend).Methods("PUT")
+	r.HandleFunc("/me/close-friends/{user_id}", api.handleRemoveCloseFriend).Methods("DELETE")
+	r.HandleFunc("/hashtags/trending", api.handleGetTrendingHashtags).Methods("GET")
+	r.HandleFunc("/hashtags/{tag}/posts", api.handleGetHashtagPosts).Methods("GET")
+	r.HandleFunc("/feeds", api.handleGetFeed).Methods("GET")
+	r.HandleFunc("/feeds/new", api.handleCountNewPosts).Methods("GET")
+}
+
+// parseLimit đọc ?limit=, mặc định defaultPageSize, tối đa maxPageSize
+func parseLimit(r *http.Request) (int64, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageSize, true
+	}
+	limit, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, false
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return limit, true
+}
+
+// renditionHint chọn cỡ ảnh: ?rendition= nếu có, không thì ước lượng từ Client Hints
+// (Viewport-Width x DPR = số pixel thật cần hiển thị), mặc định medium
+func renditionHint(r *http.Request) (string, bool) {
+	if name := r.URL.Query().Get("rendition"); name != "" {
+		return name, scrollingfeedmanager.IsImageRendition(name)
+	}
+
+	width := headerFloat(r, "Sec-CH-Viewport-Width", "Viewport-Width")
+	if width <= 0 {
+		return model.RenditionMedium, true
+	}
+	if dpr := headerFloat(r, "Sec-CH-DPR", "DPR"); dpr > 0 {
+		width *= dpr
+	}
+	switch {
+	case width <= 320:
+		return model.RenditionThumb, true
+	case width <= 1080:
+		return model.RenditionMedium, true
+	default:
+		return model.RenditionFull, true
+	}
+}
+
+// headerFloat đọc header đầu tiên có giá trị số hợp lệ, 0 nếu không có
+func headerFloat(r *http.Request, names ...string) float64 {
+	for _, name := range names {
+		if v, err := strconv.ParseFloat(r.Header.Get(name), 64); err == nil && v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func (api *FeedAPI) handleGetFeed(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit, ok := parseLimit(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+
+	rendition, ok := renditionHint(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid rendition")
+		return
+	}
+
+	resp, err := api.FeedInterface.ScrollingFeed(userID, r.URL.Query().Get("before"), limit)
+	if errors.Is(err, feedcache.ErrInvalidCursor) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to load feed: "+err.Error())
+		return
+	}
+	resp.Feed = scrollingfeedmanager.SelectRendition(resp.Feed, rendition)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (api *FeedAPI) handleCountNewPosts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		utils.WriteError(w, http.StatusBadRequest, "since is required")
+		return
+	}
+
+	count, err := api.FeedInterface.NewPostsCount(userID, since)
+	if errors.Is(err, feedcache.ErrInvalidCursor) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to count new posts: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]int64{"count": count})
+}
+
+// postIDVar đọc {post_id}; id không phải UUID thì không thể là post nào nên trả 404 luôn
+// thay vì để Postgres báo lỗi cast thành 500
+func postIDVar(w http.ResponseWriter, r *http.Request) (string, bool) {
+	postID := mux.Vars(r)["post_id"]
+	if _, err := uuid.Parse(postID); err != nil {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return "", false
+	}
+	return postID, true
+}
+
+func (api *FeedAPI) handleGetPost(w http.ResponseWriter, r *http.Request) {
+	postID, ok := postIDVar(w, r)
+	if !ok {
+		return
+	}
+	rendition, ok := renditionHint(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid rendition")
+		return
+	}
+
+	post, err := api.FeedInterface.GetPost(r.Header.Get("X-User-ID"), postID)
+	if errors.Is(err, store.ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get post: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, scrollingfeedmanager.SelectRendition([]scrollingfeedmanager.FeedItem{post}, rendition)[0])
+}
+
+func (api *FeedAPI) handleGetRevisions(w http.ResponseWriter, r *http.Request) {
+	postID, ok := postIDVar(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := api.PostInteface.GetRevisions(r.Header.Get("X-User-ID"), postID)
+	if errors.Is(err, store.ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get revisions: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"post_id":   postID,
+		"revisions": revisions,
+	})
+}
+
+func (api *FeedAPI) handleGetUserPosts(w http.ResponseWriter, r *http.Request) {
+	api.writeUserPosts(w, r, mux.Vars(r)["user_id"])
+}
+
+func (api *FeedAPI) handleGetOwnPosts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+	api.writeUserPosts(w, r, userID)
+}
+
+func (api *FeedAPI) writeUserPosts(w http.ResponseWriter, r *http.Request, userID string) {
+	limit, ok := parseLimit(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	rendition, ok := renditionHint(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid rendition")
+		return
+	}
+
+	resp, err := api.FeedInterface.GetUserPosts(r.Header.Get("X-User-ID"), userID, r.URL.Query().Get("before"), limit)
+	if errors.Is(err, feedcache.ErrInvalidCursor) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get posts: "+err.Error())
+		return
+	}
+	resp.Posts = scrollingfeedmanager.SelectRendition(resp.Posts, rendition)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (api *FeedAPI) handleGetHashtagPosts(w http.ResponseWriter, r *http.Request) {
+	tag, ok := postmanager.NormalizeHashtag(mux.Vars(r)["tag"])
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid hashtag")
+		return
+	}
+	limit, ok := parseLimit(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	rendition, ok := renditionHint(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid rendition")
+		return
+	}
+
+	resp, err := api.FeedInterface.GetHashtagPosts(r.Header.Get("X-User-ID"), tag, r.URL.Query().Get("before"), limit)
+	if errors.Is(err, feedcache.ErrInvalidCursor) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get posts: "+err.Error())
+		return
+	}
+	resp.Posts = scrollingfeedmanager.SelectRendition(resp.Posts, rendition)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /hashtags/trending?window=1h|6h|24h&limit=
+func (api *FeedAPI) handleGetTrendingHashtags(w http.ResponseWriter, r *http.Request) {
+	label := r.URL.Query().Get("window")
+	if label == "" {
+		label = defaultTrendingWindow
+	}
+	window, err := time.ParseDuration(label)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid window")
+		return
+	}
+	limit := int64(defaultTrendingLimit)
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(n, maxTrendingLimit)
+	}
+
+	hashtags, err := api.FeedInterface.TrendingHashtags(window, limit)
+	if errors.Is(err, trending.ErrInvalidWindow) {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get trending hashtags: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"window":   label,
+		"hashtags": hashtags,
+	})
+}
+
+func (api *FeedAPI) handleUpdatePost(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Content    *string `json:"content"`    // bỏ trống = giữ nguyên
+		Visibility string  `json:"visibility"` // bỏ trống = giữ nguyên
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Content == nil && req.Visibility == "" {
+		utils.WriteError(w, http.StatusBadRequest, "content or visibility is required")
+		return
+	}
+	if req.Content != nil && *req.Content == "" {
+		utils.WriteError(w, http.StatusBadRequest, "content must not be empty")
+		return
+	}
+	if req.Visibility != "" && !model.IsValidVisibility(req.Visibility) {
+		utils.WriteError(w, http.StatusBadRequest, "visibility must be one of "+strings.Join(model.Visibilities, ", "))
+		return
+	}
+
+	postID, ok := postIDVar(w, r)
+	if !ok {
+		return
+	}
+	if !api.writeModifyError(w, api.PostInteface.UpdatePost(userID, postID, req.Content, req.Visibility), "update") {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"post_id": postID, "message": "Post updated"})
+}
+
+func (api *FeedAPI) handleDeletePost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	postID, ok := postIDVar(w, r)
+	if !ok {
+		return
+	}
+	if !api.writeModifyError(w, api.PostInteface.DeletePost(userID, postID), "delete") {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"post_id": postID, "message": "Post deleted"})
+}
+
+// writeModifyError map lỗi update/delete sang HTTP status, trả về true nếu không có lỗi
+func (api *FeedAPI) writeModifyError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, store.ErrPostNotFound):
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+	case errors.Is(err, postmanager.ErrNotPostAuthor):
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, "failed to "+action+" post: "+err.Error())
+	}
+	return false
 }
 
 func (api *FeedAPI) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 	type request struct {
-		Content  string   `json:"content"`
-		MediaIDs []string `json:"media_ids"`
+		Content    string   `json:"content"`
+		MediaIDs   []string `json:"media_ids"`
+		Visibility string   `json:"visibility"` // mặc định public
 	}
 
 	var req request
@@ -50,6 +449,13 @@ func (api *FeedAPI) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusBadRequest, "content is required")
 		return
 	}
+	if req.Visibility == "" {
+		req.Visibility = model.VisibilityPublic
+	}
+	if !model.IsValidVisibility(req.Visibility) {
+		utils.WriteError(w, http.StatusBadRequest, "visibility must be one of "+strings.Join(model.Visibilities, ", "))
+		return
+	}
 
 	// Get user ID from header (set by auth middleware / gateway)
 	userID := r.Header.Get("X-User-ID")
@@ -58,7 +464,15 @@ func (api *FeedAPI) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	postID, err := api.PostInteface.CreatePost(userID, req.Content, req.MediaIDs)
+	postID, err := api.PostInteface.CreatePost(userID, req.Content, req.MediaIDs, req.Visibility)
+	if errors.Is(err, mediaverifier.ErrMediaNotUploaded) {
+		utils.WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, mediaverifier.ErrMediaRejected) {
+		utils.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "failed to create post: "+err.Error())
 		return
@@ -71,14 +485,119 @@ func (api *FeedAPI) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusCreated, resp)
 }
 
+func (api *FeedAPI) handleSharePost(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Content    string `json:"content"`    // commentary, có thể rỗng
+		Visibility string `json:"visibility"` // mặc định public
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	// body tuỳ chọn: share không commentary thì không cần gửi body
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Visibility == "" {
+		req.Visibility = model.VisibilityPublic
+	}
+	if !model.IsValidVisibility(req.Visibility) {
+		utils.WriteError(w, http.StatusBadRequest, "visibility must be one of "+strings.Join(model.Visibilities, ", "))
+		return
+	}
+
+	originalID, ok := postIDVar(w, r)
+	if !ok {
+		return
+	}
+	postID, err := api.PostInteface.SharePost(userID, originalID, req.Content, req.Visibility)
+	if errors.Is(err, store.ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to share post: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, map[string]string{"post_id": postID, "message": "Post shared"})
+}
+
+func (api *FeedAPI) handleListCloseFriends(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	friends, err := api.PostInteface.ListCloseFriends(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to get close friends: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":       userID,
+		"close_friends": friends,
+	})
+}
+
+func (api *FeedAPI) handleAddCloseFriend(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	friendID := mux.Vars(r)["user_id"]
+	err := api.PostInteface.AddCloseFriend(userID, friendID)
+	if errors.Is(err, postmanager.ErrInvalidCloseFriend) {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, store.ErrFriendNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to add close friend: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"user_id": friendID, "message": "Added to close friends"})
+}
+
+func (api *FeedAPI) handleRemoveCloseFriend(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	friendID := mux.Vars(r)["user_id"]
+	if err := api.PostInteface.RemoveCloseFriend(userID, friendID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to remove close friend: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"user_id": friendID, "message": "Removed from close friends"})
+}
+
 func (api *FeedAPI) handleCreateMedia(w http.ResponseWriter, r *http.Request) {
 	type request struct {
-		MediaTypes []string `json:"media_type"`
-		FileNames  []string `json:"file_name"`
+		MediaTypes   []string `json:"media_type"`
+		FileNames    []string `json:"file_name"`
+		ContentTypes []string `json:"content_type"` // tuỳ chọn, có thì URL bị ràng buộc loại/kích thước
+		Multipart    []bool   `json:"multipart"`    // tuỳ chọn, true = multipart upload cho file lớn
+		Sizes        []int64  `json:"size"`         // bắt buộc với media multipart
 	}
 	type response struct {
-		MediaIDs   []string `json:"media_id"`
-		UploadURLs []string `json:"upload_url"`
+		MediaIDs      []string                 `json:"media_id"`
+		UploadURLs    []string                 `json:"upload_url"`
+		UploadHeaders []map[string]string      `json:"upload_headers"`
+		UploadForms   []*model.UploadForm      `json:"upload_form"`
+		Multipart     []*model.MultipartUpload `json:"multipart"`
 	}
 
 	var req request
@@ -91,6 +610,15 @@ func (api *FeedAPI) handleCreateMedia(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusBadRequest, "media_type and file_name must be non-empty arrays of equal length")
 		return
 	}
+	if len(req.ContentTypes) != 0 && len(req.ContentTypes) != len(req.MediaTypes) {
+		utils.WriteError(w, http.StatusBadRequest, "content_type must have the same length as media_type")
+		return
+	}
+	if (len(req.Multipart) != 0 && len(req.Multipart) != len(req.MediaTypes)) ||
+		(len(req.Sizes) != 0 && len(req.Sizes) != len(req.MediaTypes)) {
+		utils.WriteError(w, http.StatusBadRequest, "multipart and size must have the same length as media_type")
+		return
+	}
 
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
@@ -105,14 +633,107 @@ func (api *FeedAPI) handleCreateMedia(w http.ResponseWriter, r *http.Request) {
 
 	var resp response
 	for i := range req.MediaTypes {
-		media, err := api.PostInteface.CreateMedia(userID, req.MediaTypes[i], req.FileNames[i])
+		contentType := ""
+		if len(req.ContentTypes) != 0 {
+			contentType = req.ContentTypes[i]
+		}
+		var upload model.MediaUpload
+		var err error
+		if len(req.Multipart) != 0 && req.Multipart[i] {
+			var size int64
+			if len(req.Sizes) != 0 {
+				size = req.Sizes[i]
+			}
+			upload, err = api.PostInteface.CreateMultipartMedia(userID, req.MediaTypes[i], req.FileNames[i], contentType, size)
+		} else {
+			upload, err = api.PostInteface.CreateMedia(userID, req.MediaTypes[i], req.FileNames[i], contentType)
+		}
+		if errors.Is(err, postmanager.ErrInvalidContentType) || errors.Is(err, postmanager.ErrInvalidUpload) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			utils.WriteError(w, http.StatusInternalServerError, "failed to create media: "+err.Error())
 			return
 		}
-		resp.MediaIDs = append(resp.MediaIDs, media.MediaID)
-		resp.UploadURLs = append(resp.UploadURLs, media.Url.String) // presigned URL
+		resp.MediaIDs = append(resp.MediaIDs, upload.MediaID)
+		resp.UploadURLs = append(resp.UploadURLs, upload.UploadURL) // presigned URL
+		resp.UploadHeaders = append(resp.UploadHeaders, upload.UploadHeaders)
+		resp.UploadForms = append(resp.UploadForms, upload.UploadForm)
+		resp.Multipart = append(resp.Multipart, upload.Multipart)
 	}
 
 	utils.WriteJSON(w, http.StatusCreated, resp)
 }
+
+// writeUploadError map lỗi của multipart upload, trả về false nếu không có lỗi
+func (api *FeedAPI) writeUploadError(w http.ResponseWriter, err error, action string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, postmanager.ErrMediaNotFound):
+		utils.WriteError(w, http.StatusNotFound, "media not found")
+	case errors.Is(err, postmanager.ErrNotMultipartUpload):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, postmanager.ErrInvalidUpload):
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, mediaverifier.ErrMediaNotUploaded):
+		utils.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, mediaverifier.ErrMediaRejected):
+		utils.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, "failed to "+action+": "+err.Error())
+	}
+	return true
+}
+
+func (api *FeedAPI) handleGetUploadParts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "missing user id")
+		return
+	}
+
+	upload, err := api.PostInteface.GetUploadParts(userID, mux.Vars(r)["media_id"])
+	if api.writeUploadError(w, err, "list upload parts") {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, upload)
+}
+
+func (api *FeedAPI) handleCompleteUpload(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Parts []model.UploadPart `json:"parts"`
+	}
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "missing user id")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Parts) == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "parts is required")
+		return
+	}
+
+	mediaID := mux.Vars(r)["media_id"]
+	if api.writeUploadError(w, api.PostInteface.CompleteUpload(userID, mediaID, req.Parts), "complete upload") {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"media_id": mediaID, "status": model.MediaUploaded})
+}
+
+func (api *FeedAPI) handleAbortUpload(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "missing user id")
+		return
+	}
+
+	mediaID := mux.Vars(r)["media_id"]
+	if api.writeUploadError(w, api.PostInteface.AbortUpload(userID, mediaID), "abort upload") {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"media_id": mediaID, "status": model.MediaFailed})
+}
@@ -1 +1,265 @@
 package app
+
+import (
+	"context"
+	"expvar"
+	"feedservice/internal/api"
+	"feedservice/internal/core/backfillmanager"
+	"feedservice/internal/core/commentserviceclient"
+	"feedservice/internal/core/fanoutmanager"
+	"feedservice/internal/core/followserviceclient"
+	"feedservice/internal/core/http-server/server"
+	"feedservice/internal/core/mediajanitor"
+	"feedservice/internal/core/mediamanager"
+	"feedservice/internal/core/mediaprocessor"
+	"feedservice/internal/core/mediaverifier"
+	"feedservice/internal/core/outboxrelay"
+	"feedservice/internal/core/postmanager"
+	"feedservice/internal/core/postpurger"
+	"feedservice/internal/core/reactionserviceclient"
+	"feedservice/internal/core/scrollingfeedmanager"
+	"feedservice/internal/core/userserviceclient"
+	"feedservice/internal/core/visibility"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/mediaurl"
+	"feedservice/internal/infra/realtime"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/infra/trending"
+	"feedservice/internal/model"
+	"log"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver      *server.HttpServer
+	feedapi         *api.FeedAPI
+	postmanager     *postmanager.PostManager
+	fanoutmanager   *fanoutmanager.FanoutManager
+	outboxrelay     *outboxrelay.OutboxRelay
+	backfillmanager *backfillmanager.BackfillManager
+	feedmanager     *scrollingfeedmanager.SrollingFeedManager
+	postpurger      *postpurger.PostPurger
+	mediamanager    *mediamanager.MediaManager
+	mediajanitor    *mediajanitor.MediaJanitor
+}
+
+func NewFeedServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.fanoutmanager.Start(); err != nil {
+		log.Fatalf("❌ Failed to start fanout workers: %v", err)
+	}
+	if err := a.outboxrelay.Start(); err != nil {
+		log.Fatalf("❌ Failed to start outbox relay: %v", err)
+	}
+	if err := a.backfillmanager.Start(); err != nil {
+		log.Fatalf("❌ Failed to start backfill workers: %v", err)
+	}
+	if err := a.postpurger.Start(); err != nil {
+		log.Fatalf("❌ Failed to start post purger: %v", err)
+	}
+	if err := a.mediamanager.Start(); err != nil {
+		log.Fatalf("❌ Failed to start media workers: %v", err)
+	}
+	if err := a.mediajanitor.Start(); err != nil {
+		log.Fatalf("❌ Failed to start media janitor: %v", err)
+	}
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+// Stop: tắt HTTP server trước để không nhận post mới, sau đó dừng các worker
+func (a *App) Stop() {
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	if err := a.outboxrelay.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping outbox relay: %v", err)
+	}
+	if err := a.fanoutmanager.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping fanout workers: %v", err)
+	}
+	if err := a.backfillmanager.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping backfill workers: %v", err)
+	}
+	if err := a.postpurger.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping post purger: %v", err)
+	}
+	if err := a.mediamanager.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping media workers: %v", err)
+	}
+	if err := a.mediajanitor.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping media janitor: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	dbcfg := &store.PostGresConfig{
+		Host:     "localhost", // IP
+		Port:     "5432",      // Port
+		User:     "taopq",     // user_name
+		Password: "123456a@",  // password
+		DBname:   "mydb",      // db
+	}
+
+	objectstore, err := s3client.NewS3Client(context.Background(), s3client.Config{
+		Endpoint:     "http://localhost:9100",
+		Bucket:       "facebook-clone-media",
+		Region:       "us-east-1",
+		AccessKey:    "minioadmin",
+		SecretKey:    "minioadmin",
+		UsePathStyle: true,                 // MinIO
+		CreateBucket: true,                 // MinIO local, production tạo bucket bằng IaC
+		SSE:          s3client.SSEConfig{}, // MinIO local chưa cấu hình KMS
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to init S3 client: %v", err)
+	}
+
+	redisstorecfg := &fanoutmanager.RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	retry := eventstream.RetryPolicy{
+		MaxRetries:  5,
+		BaseBackoff: 2 * time.Second,
+		MaxBackoff:  time.Minute,
+	}
+
+	fc := feedcache.NewFeedCache(rc, feedcache.FeedConfig{
+		MaxLen:    800,
+		TTL:       14 * 24 * time.Hour,
+		ChunkSize: 500,
+	})
+
+	followclient := followserviceclient.NewFollowServiceClient("http://localhost:9002")
+	urlsigner := mediaurl.NewURLSigner(objectstore, rc, mediaurl.Config{
+		Mode:        mediaurl.ModePresign, // ModeCDN khi chạy sau media-edge/CDN
+		Bucket:      time.Hour,
+		MinValidity: 30 * time.Minute, // > itemCacheTTL của feed
+		CDNBaseURL:  "http://localhost:9097/media",
+		CDNSecret:   []byte("dev-media-edge-secret"), // = -secret của cmd/mediaedge
+	})
+	mediastore := store.NewMediaStore(dbcfg, objectstore, urlsigner)
+	poststore := store.NewPostStore(dbcfg)
+	postmediastore := store.NewPostMediaStore(dbcfg)
+	outboxstore := store.NewOutboxStore(dbcfg)
+	renditionstore := store.NewRenditionStore(dbcfg)
+	tagstore := store.NewTagStore(dbcfg)
+	closefriendstore := store.NewCloseFriendStore(dbcfg)
+	visibilitychecker := visibility.NewChecker(followclient, closefriendstore)
+	userclient := userserviceclient.NewUserServiceClient("http://localhost:9001")
+	trends := trending.NewHashtagTrends(rc, trending.TrendingConfig{
+		Bucket:   5 * time.Minute,
+		Windows:  []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour},
+		CacheTTL: time.Minute,
+	})
+	a.postmanager = postmanager.NewPostManager(
+		mediastore,
+		poststore,
+		postmediastore,
+		outboxstore,
+		tagstore,
+		closefriendstore,
+		visibilitychecker,
+		mediaverifier.NewMediaVerifier(mediastore, eventstream.NewProducer(rc, model.MediaEventStream, 100000), mediaverifier.VerifyConfig{
+			MaxSize: map[string]int64{
+				"image": 20 << 20,
+				"video": 1 << 30,
+			},
+			UploadWindow:    5 * time.Minute, // = hạn presigned PUT URL trong CreateMedia
+			MultipartWindow: 6 * time.Hour,   // < Retention của media janitor
+			HeadTimeout:     5 * time.Second,
+		}),
+		userclient,
+		trends,
+		rc,
+	)
+	a.postpurger = postpurger.NewPostPurger(poststore, postpurger.PurgeConfig{
+		Interval:  time.Hour,
+		Retention: 30 * 24 * time.Hour,
+		BatchSize: 500,
+	})
+	a.mediamanager = mediamanager.NewMediaManager(mediamanager.MediaConfig{
+		NumWorkers: 2,
+		Retry:      retry,
+	}, rc, mediaprocessor.NewMediaProcessor(mediastore, renditionstore, mediaprocessor.ProcessConfig{
+		ImageRenditions: []mediaprocessor.RenditionSpec{
+			{Name: model.RenditionThumb, MaxEdge: 320},
+			{Name: model.RenditionMedium, MaxEdge: 1080},
+			{Name: model.RenditionFull, MaxEdge: 2048},
+		},
+		PosterMaxEdge: 1080,
+		JPEGQuality:   85,
+		FFmpegPath:    "ffmpeg",
+		Timeout:       5 * time.Minute,
+	}))
+	a.mediajanitor = mediajanitor.NewMediaJanitor(mediastore, mediajanitor.JanitorConfig{
+		Interval:    time.Hour,
+		Retention:   24 * time.Hour, // presigned PUT chỉ có hạn 5 phút
+		OrphanGrace: 24 * time.Hour,
+		BatchSize:   500,
+		Timeout:     30 * time.Minute,
+		DryRun:      false,
+	})
+	a.feedmanager = scrollingfeedmanager.NewSrollingFeedManager(
+		mediastore,
+		renditionstore,
+		poststore,
+		postmediastore,
+		tagstore,
+		visibilitychecker,
+		trends,
+		userclient,
+		followclient,
+		reactionserviceclient.NewReactionServiceClient("http://localhost:9093"),
+		commentserviceclient.NewCommentServiceClient("http://localhost:9094"),
+		rc,
+		fc,
+	)
+	a.outboxrelay = outboxrelay.NewOutboxRelay(
+		outboxstore,
+		eventstream.NewProducer(rc, model.PostEventStream, 100000),
+		outboxrelay.RelayConfig{
+			PollInterval: 200 * time.Millisecond,
+			BatchSize:    100,
+			Retention:    7 * 24 * time.Hour,
+		},
+	)
+	a.fanoutmanager = fanoutmanager.NewFanoutManager(fanoutmanager.FanoutConfig{
+		NumWorkers:         4,
+		CelebrityThreshold: 10000,
+		Retry:              retry,
+	}, rc, fc, followclient, closefriendstore, realtime.NewPublisher(rc, realtime.Config{
+		LogMaxLen: 200,
+		LogTTL:    24 * time.Hour,
+		ChunkSize: 500,
+	}))
+	a.backfillmanager = backfillmanager.NewBackfillManager(backfillmanager.BackfillConfig{
+		NumWorkers:  2,
+		BacklogSize: 50,
+		Retry:       retry,
+	}, rc, fc)
+
+	a.feedapi = api.NewFeedAPI(a.fanoutmanager, a.postmanager, a.feedmanager)
+	router := mux.NewRouter()
+	a.feedapi.RegisterRoutes(router)
+	router.Handle("/debug/vars", expvar.Handler()) // metrics (media_janitor, ...), gateway không route ra ngoài
+	a.httpserver = server.NewHttpServer("localhost:9092", router)
+}
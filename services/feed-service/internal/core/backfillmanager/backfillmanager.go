@@ -0,0 +1,55 @@
+package backfillmanager
+
+import (
+	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+)
+
+const (
+	// FollowEventStream - stream do follow-service publish
+	FollowEventStream = "follow:events"
+	consumerGroup     = "feed-service-backfill"
+)
+
+type BackfillConfig struct {
+	NumWorkers  int
+	BacklogSize int64 // số post gần nhất của followee được merge vào feed khi follow
+	Retry       eventstream.RetryPolicy
+}
+
+// BackfillManager xử lý bất đồng bộ follow/unfollow để đồng bộ feed ZSET
+type BackfillManager struct {
+	backfillworkers []*workerpocessor.BackfillWorker
+}
+
+func NewBackfillManager(cfg BackfillConfig, redisclient_ *redisclient.RedisClient, feedcache_ *feedcache.FeedCache) *BackfillManager {
+	m := BackfillManager{}
+	for i := 0; i < cfg.NumWorkers; i++ {
+		consumer := eventstream.NewConsumer(redisclient_, FollowEventStream, consumerGroup, fmt.Sprintf("backfill-worker-%d", i), cfg.Retry)
+		m.backfillworkers = append(m.backfillworkers, workerpocessor.NewBackfillWorker(consumer, redisclient_, feedcache_, cfg.BacklogSize))
+	}
+	return &m
+}
+
+func (m *BackfillManager) Start() error {
+	for _, e := range m.backfillworkers {
+		err := e.Start()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *BackfillManager) Stop() error {
+	for _, e := range m.backfillworkers {
+		err := e.Stop()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package commentserviceclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CommentServiceClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewCommentServiceClient(baseURL string) *CommentServiceClient {
+	return &CommentServiceClient{
+		BaseURL: baseURL,
+		Client:  &http.Client{},
+	}
+}
+
+// GetCounts lấy số comment của 1 trang post (GET /comments/counts?post_ids=...)
+func (c *CommentServiceClient) GetCounts(postIDs []string) (map[string]int64, error) {
+	if len(postIDs) == 0 {
+		return map[string]int64{}, nil
+	}
+	endpoint := fmt.Sprintf("%s/comments/counts?post_ids=%s", c.BaseURL, url.QueryEscape(strings.Join(postIDs, ",")))
+
+	// feed vẫn trả được khi comment-service chậm, chỉ thiếu stats
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[CommentServiceClient] failed to build get request: %w", err)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[CommentServiceClient] failed to get counts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[CommentServiceClient] unexpected status code: %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Counts map[string]int64 `json:"counts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("[CommentServiceClient] failed to decode response: %w", err)
+	}
+	return res.Counts, nil
+}
@@ -2,12 +2,26 @@ package fanoutmanager
 
 import (
 	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/core/followserviceclient"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/realtime"
 	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/infra/store"
 	"feedservice/internal/model"
+	"fmt"
 )
 
+const consumerGroup = "feed-service-fanout"
+
+type FanoutConfig struct {
+	NumWorkers         int
+	CelebrityThreshold int // author có >= ngưỡng follower thì chuyển sang fan-out on read
+	Retry              eventstream.RetryPolicy
+}
+
 type FanoutManager struct {
-	fanoutworkers []workerpocessor.FanoutWorker
+	fanoutworkers []*workerpocessor.FanoutWorker
 }
 
 type RedisConfig struct {
@@ -17,9 +31,15 @@ type RedisConfig struct {
 	DBNumber int
 }
 
-func NewFanoutManager(numWorkers int, redisclient_ *redisclient.RedisClient, newposteventqueue_ *chan model.NewPostEvent) *FanoutManager {
+// NewFanoutManager tạo cfg.NumWorkers FanoutWorker cùng 1 consumer group trên stream post:events
+func NewFanoutManager(cfg FanoutConfig, redisclient_ *redisclient.RedisClient, feedcache_ *feedcache.FeedCache,
+	followserviceclient_ *followserviceclient.FollowServiceClient, closeFriendStore_ *store.CloseFriendStore, realtime_ *realtime.Publisher) *FanoutManager {
 	m := FanoutManager{}
-	m.fanoutworkers = make([]workerpocessor.FanoutWorker, numWorkers)
+	for i := 0; i < cfg.NumWorkers; i++ {
+		consumer := eventstream.NewConsumer(redisclient_, model.PostEventStream, consumerGroup, fmt.Sprintf("fanout-worker-%d", i), cfg.Retry)
+		m.fanoutworkers = append(m.fanoutworkers,
+			workerpocessor.NewFanoutWorker(consumer, redisclient_, feedcache_, followserviceclient_, closeFriendStore_, realtime_, cfg.CelebrityThreshold))
+	}
 	return &m
 }
 
@@ -33,6 +53,7 @@ func (m *FanoutManager) Start() error {
 	return nil
 }
 
+// Stop dừng toàn bộ worker sau khi batch đang xử lý được Ack
 func (m *FanoutManager) Stop() error {
 	for _, e := range m.fanoutworkers {
 		err := e.Stop()
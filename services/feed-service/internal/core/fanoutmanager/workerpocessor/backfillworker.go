@@ -0,0 +1,163 @@
+package workerpocessor
+
+import (
+	"context"
+	"encoding/json"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/model"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BackfillWorker consume follow events:
+//   - FollowCreated: merge N post gần nhất của followee vào feed của follower
+//   - FollowDeleted: xoá post của followee khỏi feed của follower
+type BackfillWorker struct {
+	BaseWorkerProcessor
+	redisclient *redisclient.RedisClient
+	feedcache   *feedcache.FeedCache
+	consumer    *eventstream.Consumer
+	backlogSize int64
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func NewBackfillWorker(consumer_ *eventstream.Consumer, redisclient_ *redisclient.RedisClient, feedcache_ *feedcache.FeedCache, backlogSize int64) *BackfillWorker {
+	s := &BackfillWorker{
+		redisclient: redisclient_,
+		feedcache:   feedcache_,
+		consumer:    consumer_,
+		backlogSize: backlogSize,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	s.Init(s)
+	return s
+}
+
+func (s *BackfillWorker) RunningTask() error {
+	defer close(s.done)
+	if err := s.consumer.EnsureGroup(); err != nil {
+		return err
+	}
+
+	reclaimTicker := time.NewTicker(time.Second)
+	defer reclaimTicker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-reclaimTicker.C:
+			messages, err := s.consumer.Reclaim(10)
+			if err != nil {
+				log.Printf("[BackfillWorker] %v", err)
+			}
+			s.process(messages)
+		default:
+		}
+
+		messages, err := s.consumer.Read(10, time.Second)
+		if err != nil {
+			log.Printf("[BackfillWorker] %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.process(messages)
+	}
+}
+
+func (s *BackfillWorker) process(messages []redis.XMessage) {
+	for _, msg := range messages {
+		if err := s.handle(msg); err != nil {
+			// không ack -> message nằm lại trong pending list, retry sau backoff
+			log.Printf("[BackfillWorker] failed to handle message %s: %v", msg.ID, err)
+			s.consumer.Fail(msg.ID, err)
+			continue
+		}
+		if err := s.consumer.Ack(msg.ID); err != nil {
+			log.Printf("[BackfillWorker] %v", err)
+		}
+	}
+}
+
+// Stop chờ message đang xử lý xong rồi mới return
+func (s *BackfillWorker) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+func (s *BackfillWorker) handle(msg redis.XMessage) error {
+	payload, ok := msg.Values["payload"].(string)
+	if !ok {
+		log.Printf("[BackfillWorker] drop malformed message %s", msg.ID)
+		return nil
+	}
+
+	var event model.FollowEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Printf("[BackfillWorker] drop undecodable message %s: %v", msg.ID, err)
+		return nil
+	}
+
+	switch event.Type {
+	case model.FollowCreated:
+		return s.backfill(event.FollowerID, event.FolloweeID)
+	case model.FollowDeleted:
+		return s.cleanup(event.FollowerID, event.FolloweeID)
+	default:
+		return nil
+	}
+}
+
+// backfill copy N post gần nhất từ user:{followee}:posts sang user:{follower}:feed
+// (feed đã expire thì bỏ qua, lần đọc sau rebuild từ Postgres đã gồm followee mới)
+func (s *BackfillWorker) backfill(followerID, followeeID string) error {
+	ctx := context.Background()
+	authorPostsKey := fmt.Sprintf("user:%s:posts", followeeID)
+
+	posts, err := s.redisclient.GetClient().ZRevRangeWithScores(ctx, authorPostsKey, 0, s.backlogSize-1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read posts of %s: %w", followeeID, err)
+	}
+	if len(posts) == 0 {
+		return nil
+	}
+
+	if err := s.feedcache.MergeIntoFeed(ctx, followerID, posts); err != nil {
+		return fmt.Errorf("failed to backfill feed of %s: %w", followerID, err)
+	}
+	log.Printf("[BackfillWorker] backfilled %d posts of %s into feed of %s", len(posts), followeeID, followerID)
+	return nil
+}
+
+// cleanup xoá toàn bộ post của followee khỏi feed của follower
+func (s *BackfillWorker) cleanup(followerID, followeeID string) error {
+	ctx := context.Background()
+	authorPostsKey := fmt.Sprintf("user:%s:posts", followeeID)
+	feedKey := feedcache.FeedKey(followerID)
+
+	postIDs, err := s.redisclient.GetClient().ZRange(ctx, authorPostsKey, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read posts of %s: %w", followeeID, err)
+	}
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(postIDs))
+	for i, id := range postIDs {
+		members[i] = id
+	}
+	if err := s.redisclient.GetClient().ZRem(ctx, feedKey, members...).Err(); err != nil {
+		return fmt.Errorf("failed to clean feed of %s: %w", followerID, err)
+	}
+	log.Printf("[BackfillWorker] removed posts of %s from feed of %s", followeeID, followerID)
+	return nil
+}
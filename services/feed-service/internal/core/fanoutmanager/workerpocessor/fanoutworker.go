@@ -2,8 +2,14 @@ package workerpocessor
 
 import (
 	"context"
+	"encoding/json"
 	"feedservice/internal/core/followserviceclient"
+	"feedservice/internal/core/visibility"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/feedcache"
+	"feedservice/internal/infra/realtime"
 	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/infra/store"
 	"feedservice/internal/model"
 	"fmt"
 	"log"
@@ -15,78 +21,296 @@ import (
 type FanoutWorker struct {
 	BaseWorkerProcessor
 	redisclient         *redisclient.RedisClient
+	feedcache           *feedcache.FeedCache
 	followserviceclient *followserviceclient.FollowServiceClient
-	newposteventqueue   *chan model.NewPostEvent
+	closeFriendStore    *store.CloseFriendStore
+	realtime            *realtime.Publisher
+	consumer            *eventstream.Consumer
+	celebrityThreshold  int
+	stop                chan struct{}
+	done                chan struct{}
 }
 
-func NewFanoutWorker(newposteventqueue_ *chan model.NewPostEvent, redisclient_ *redisclient.RedisClient,
-	followserviceclient_ *followserviceclient.FollowServiceClient) *FanoutWorker {
+func NewFanoutWorker(consumer_ *eventstream.Consumer, redisclient_ *redisclient.RedisClient, feedcache_ *feedcache.FeedCache,
+	followserviceclient_ *followserviceclient.FollowServiceClient, closeFriendStore_ *store.CloseFriendStore,
+	realtime_ *realtime.Publisher, celebrityThreshold int) *FanoutWorker {
 	s := &FanoutWorker{
 		redisclient:         redisclient_,
+		feedcache:           feedcache_,
 		followserviceclient: followserviceclient_,
-		newposteventqueue:   newposteventqueue_,
+		closeFriendStore:    closeFriendStore_,
+		realtime:            realtime_,
+		consumer:            consumer_,
+		celebrityThreshold:  celebrityThreshold,
+		stop:                make(chan struct{}),
+		done:                make(chan struct{}),
 	}
 	s.Init(s)
 	return s
 }
 
 func (s *FanoutWorker) RunningTask() error {
-	if s.newposteventqueue == nil {
-		return fmt.Errorf("newposteventqueue is nil")
+	defer close(s.done)
+	if s.consumer == nil {
+		return fmt.Errorf("post event consumer is nil")
 	}
+	if err := s.consumer.EnsureGroup(); err != nil {
+		return err
+	}
+
+	reclaimTicker := time.NewTicker(time.Second)
+	defer reclaimTicker.Stop()
 
 	for {
 		select {
-		case newPostEvent := <-*s.newposteventqueue:
-			ctx := context.Background()
-			score := float64(time.Now().Unix())
-
-			// 1️⃣ Cache mapping: post_id -> user_id
-			err := s.redisclient.GetClient().HSet(ctx,
-				"post_authors",
-				newPostEvent.PostID,
-				newPostEvent.UserID,
-			).Err()
+		case <-s.stop:
+			return nil
+		case <-reclaimTicker.C:
+			// retry các event fan-out lỗi (hoặc của worker đã chết) khi đến hạn backoff
+			messages, err := s.consumer.Reclaim(10)
 			if err != nil {
-				log.Printf("[FanoutWorker] failed to cache author for post %s: %v", newPostEvent.PostID, err)
-				continue
+				log.Printf("[FanoutWorker] %v", err)
 			}
+			s.process(messages)
+		default:
+		}
 
-			// 2️⃣ Add to author’s own posts
-			authorPostsKey := fmt.Sprintf("user:%s:posts", newPostEvent.UserID)
-			if err := s.redisclient.GetClient().ZAdd(ctx, authorPostsKey, redis.Z{
-				Score:  score,
-				Member: newPostEvent.PostID,
-			}).Err(); err != nil {
-				log.Printf("[FanoutWorker] failed to add post %s to author %s posts: %v",
-					newPostEvent.PostID, newPostEvent.UserID, err)
-				continue
-			}
+		messages, err := s.consumer.Read(10, time.Second)
+		if err != nil {
+			log.Printf("[FanoutWorker] %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.process(messages)
+	}
+}
 
-			// 3️⃣ Fetch followers from FollowService
-			followers, err := s.followserviceclient.GetFollowers(newPostEvent.UserID)
-			if err != nil {
-				log.Printf("[FanoutWorker] failed to fetch followers for user=%s: %v", newPostEvent.UserID, err)
-				continue
-			}
+// Stop chờ batch đang xử lý xong; event chưa đọc vẫn nằm trong stream
+func (s *FanoutWorker) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
 
-			// 4️⃣ Fanout to followers’ feeds
-			for _, followerID := range followers {
-				feedKey := fmt.Sprintf("user:%s:feed", followerID)
-				if err := s.redisclient.GetClient().ZAdd(ctx, feedKey, redis.Z{
-					Score:  score,
-					Member: newPostEvent.PostID,
-				}).Err(); err != nil {
-					log.Printf("[FanoutWorker] failed to add post %s to feed of user %s: %v",
-						newPostEvent.PostID, followerID, err)
-					continue
-				}
-				log.Printf("[FanoutWorker] added post %s to feed of user %s",
-					newPostEvent.PostID, followerID)
-			}
+// process chỉ Ack sau khi fan-out hoàn tất, lỗi thì để lại pending cho lần retry sau
+func (s *FanoutWorker) process(messages []redis.XMessage) {
+	for _, msg := range messages {
+		if err := s.handle(msg); err != nil {
+			log.Printf("[FanoutWorker] failed to handle message %s: %v", msg.ID, err)
+			s.consumer.Fail(msg.ID, err)
+			continue
+		}
+		if err := s.consumer.Ack(msg.ID); err != nil {
+			log.Printf("[FanoutWorker] %v", err)
+		}
+	}
+}
+
+func (s *FanoutWorker) handle(msg redis.XMessage) error {
+	eventType, _ := msg.Values["type"].(string)
+	payload, ok := msg.Values["payload"].(string)
+	if !ok {
+		return fmt.Errorf("malformed message: missing payload")
+	}
+
+	switch eventType {
+	case model.PostCreated:
+		var event model.NewPostEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		return s.fanout(event, true)
+	case model.PostVisibilityChanged:
+		var event model.PostVisibilityChangedEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		return s.changeVisibility(event)
+	case model.PostDeleted:
+		var event model.PostDeletedEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		return s.removePost(event)
+	case model.PostMentioned:
+		// notification-service xử lý, feed không đổi
+		return nil
+	default:
+		log.Printf("[FanoutWorker] skip unknown event type %q (message %s)", eventType, msg.ID)
+		return nil
+	}
+}
+
+// fanout push post vào feed của audience theo visibility:
+//   - only_me: không push, author xem qua trang post của mình
+//   - close_friends: chỉ follower có trong danh sách close friends, không vào user:{author}:posts
+//     (key đó được merge/backfill cho mọi follower)
+//   - public/followers: toàn bộ follower như trước
+//
+// notify = false khi push lại do đổi visibility, không báo realtime lần nữa
+func (s *FanoutWorker) fanout(newPostEvent model.NewPostEvent, notify bool) error {
+	ctx := context.Background()
+	// score theo thời điểm tạo post -> retry không làm thay đổi thứ tự feed
+	score := float64(newPostEvent.CreatedAt.Unix())
 
-		case <-time.After(1 * time.Second):
-			// idle wait
+	switch newPostEvent.Visibility {
+	case model.VisibilityOnlyMe:
+		log.Printf("[FanoutWorker] post %s is only visible to its author, skip fan-out", newPostEvent.PostID)
+		return nil
+	case model.VisibilityCloseFriends:
+		return s.fanoutToCloseFriends(ctx, newPostEvent, score, notify)
+	}
+
+	// 1️⃣ Cache mapping: post_id -> user_id
+	err := s.redisclient.GetClient().HSet(ctx,
+		"post_authors",
+		newPostEvent.PostID,
+		newPostEvent.UserID,
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to cache author for post %s: %w", newPostEvent.PostID, err)
+	}
+
+	// 2️⃣ Add to author’s own posts
+	authorPostsKey := fmt.Sprintf("user:%s:posts", newPostEvent.UserID)
+	if err := s.redisclient.GetClient().ZAdd(ctx, authorPostsKey, redis.Z{
+		Score:  score,
+		Member: newPostEvent.PostID,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to add post %s to author %s posts: %w",
+			newPostEvent.PostID, newPostEvent.UserID, err)
+	}
+
+	// 3️⃣ Author nhiều follower: không push, follower tự merge user:{author}:posts lúc đọc feed.
+	// Chỉ đếm follower để không phải tải cả danh sách của celebrity.
+	followerCount, err := s.followserviceclient.CountFollowers(newPostEvent.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to count followers for user=%s: %w", newPostEvent.UserID, err)
+	}
+	if s.celebrityThreshold > 0 && followerCount >= int64(s.celebrityThreshold) {
+		if err := s.redisclient.GetClient().SAdd(ctx, model.CelebritiesKey, newPostEvent.UserID).Err(); err != nil {
+			return fmt.Errorf("failed to mark %s as celebrity: %w", newPostEvent.UserID, err)
 		}
+		log.Printf("[FanoutWorker] author %s has %d followers, post %s served by fan-out on read",
+			newPostEvent.UserID, followerCount, newPostEvent.PostID)
+		return nil
+	}
+	if err := s.redisclient.GetClient().SRem(ctx, model.CelebritiesKey, newPostEvent.UserID).Err(); err != nil {
+		return fmt.Errorf("failed to unmark %s as celebrity: %w", newPostEvent.UserID, err)
+	}
+
+	// 4️⃣ Fetch followers from FollowService
+	followers, err := s.followserviceclient.GetFollowers(newPostEvent.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch followers for user=%s: %w", newPostEvent.UserID, err)
+	}
+
+	// 5️⃣ Fanout to followers’ feeds theo chunk pipeline (ZADD idempotent nên retry cả event là an toàn)
+	if err := s.feedcache.PushToFeeds(ctx, followers, redis.Z{
+		Score:  score,
+		Member: newPostEvent.PostID,
+	}); err != nil {
+		return fmt.Errorf("failed to fan out post %s: %w", newPostEvent.PostID, err)
+	}
+	log.Printf("[FanoutWorker] fanned out post %s to %d followers", newPostEvent.PostID, len(followers))
+
+	// 6️⃣ Báo follower đang online có post mới
+	if notify {
+		s.publishNewPost(ctx, followers, newPostEvent)
+	}
+	return nil
+}
+
+// fanoutToCloseFriends push post close_friends tới follower có trong danh sách close friends của author.
+// Danh sách thường nhỏ nên push thẳng kể cả author là celebrity.
+func (s *FanoutWorker) fanoutToCloseFriends(ctx context.Context, newPostEvent model.NewPostEvent, score float64, notify bool) error {
+	followers, err := s.followserviceclient.GetFollowers(newPostEvent.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch followers for user=%s: %w", newPostEvent.UserID, err)
+	}
+	friends, err := s.closeFriendStore.List(newPostEvent.UserID)
+	if err != nil {
+		return err
+	}
+	friendIDs := make([]string, len(friends))
+	for i, f := range friends {
+		friendIDs[i] = f.UserID
+	}
+	audience := visibility.Intersect(followers, friendIDs)
+
+	if err := s.feedcache.PushToFeeds(ctx, audience, redis.Z{
+		Score:  score,
+		Member: newPostEvent.PostID,
+	}); err != nil {
+		return fmt.Errorf("failed to fan out post %s: %w", newPostEvent.PostID, err)
+	}
+	log.Printf("[FanoutWorker] fanned out close friends post %s to %d followers", newPostEvent.PostID, len(audience))
+
+	if notify {
+		s.publishNewPost(ctx, audience, newPostEvent)
+	}
+	return nil
+}
+
+// publishNewPost báo follower đang online có post mới (lỗi không retry cả event, client vẫn thấy post khi reload feed)
+func (s *FanoutWorker) publishNewPost(ctx context.Context, userIDs []string, newPostEvent model.NewPostEvent) {
+	if err := s.realtime.Publish(ctx, userIDs, realtime.FeedNewPost, map[string]interface{}{
+		"post_id":    newPostEvent.PostID,
+		"author_id":  newPostEvent.UserID,
+		"created_at": newPostEvent.CreatedAt,
+	}); err != nil {
+		log.Printf("[FanoutWorker] %v", err)
+	}
+}
+
+// changeVisibility gỡ post khỏi user:{author}:posts và feed của mọi follower rồi push lại theo visibility mới.
+// Follower bị thu hẹp khỏi audience mà vẫn còn post id (vd event lỗi giữa chừng) thì hydrate vẫn ẩn post.
+func (s *FanoutWorker) changeVisibility(event model.PostVisibilityChangedEvent) error {
+	ctx := context.Background()
+
+	authorPostsKey := fmt.Sprintf("user:%s:posts", event.UserID)
+	if err := s.redisclient.GetClient().ZRem(ctx, authorPostsKey, event.PostID).Err(); err != nil {
+		return fmt.Errorf("failed to remove post %s of author %s: %w", event.PostID, event.UserID, err)
+	}
+	followers, err := s.followserviceclient.GetFollowers(event.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch followers for user=%s: %w", event.UserID, err)
+	}
+	if err := s.feedcache.RemoveFromFeeds(ctx, followers, event.PostID); err != nil {
+		return err
+	}
+
+	return s.fanout(model.NewPostEvent{
+		PostID:     event.PostID,
+		UserID:     event.UserID,
+		Visibility: event.Visibility,
+		CreatedAt:  event.CreatedAt,
+	}, false)
+}
+
+// removePost xoá post đã soft delete khỏi user:{author}:posts và feed của follower.
+// Follower đã unfollow trước đó vẫn có thể còn post id, hydrate sẽ bỏ qua post đã xoá.
+func (s *FanoutWorker) removePost(event model.PostDeletedEvent) error {
+	ctx := context.Background()
+
+	authorPostsKey := fmt.Sprintf("user:%s:posts", event.UserID)
+	_, err := s.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, authorPostsKey, event.PostID)
+		pipe.HDel(ctx, "post_authors", event.PostID)
+		pipe.Del(ctx, model.PostItemKey(event.PostID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove post %s of author %s: %w", event.PostID, event.UserID, err)
+	}
+
+	followers, err := s.followserviceclient.GetFollowers(event.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch followers for user=%s: %w", event.UserID, err)
+	}
+	if err := s.feedcache.RemoveFromFeeds(ctx, followers, event.PostID); err != nil {
+		return err
 	}
+	log.Printf("[FanoutWorker] removed deleted post %s from %d feeds", event.PostID, len(followers))
+	return nil
 }
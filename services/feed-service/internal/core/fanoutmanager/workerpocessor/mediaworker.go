@@ -0,0 +1,105 @@
+package workerpocessor
+
+import (
+	"encoding/json"
+	"feedservice/internal/core/mediaprocessor"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/model"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MediaWorker consume media:events, tạo rendition cho media vừa xác nhận upload
+type MediaWorker struct {
+	BaseWorkerProcessor
+	processor *mediaprocessor.MediaProcessor
+	consumer  *eventstream.Consumer
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewMediaWorker(consumer_ *eventstream.Consumer, processor_ *mediaprocessor.MediaProcessor) *MediaWorker {
+	s := &MediaWorker{
+		processor: processor_,
+		consumer:  consumer_,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.Init(s)
+	return s
+}
+
+func (s *MediaWorker) RunningTask() error {
+	defer close(s.done)
+	if err := s.consumer.EnsureGroup(); err != nil {
+		return err
+	}
+
+	reclaimTicker := time.NewTicker(time.Second)
+	defer reclaimTicker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-reclaimTicker.C:
+			messages, err := s.consumer.Reclaim(1)
+			if err != nil {
+				log.Printf("[MediaWorker] %v", err)
+			}
+			s.process(messages)
+		default:
+		}
+
+		// xử lý 1 media mỗi lần, ảnh/video lớn tốn nhiều memory/CPU
+		messages, err := s.consumer.Read(1, time.Second)
+		if err != nil {
+			log.Printf("[MediaWorker] %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.process(messages)
+	}
+}
+
+func (s *MediaWorker) process(messages []redis.XMessage) {
+	for _, msg := range messages {
+		if err := s.handle(msg); err != nil {
+			// không ack -> message nằm lại trong pending list, retry sau backoff
+			log.Printf("[MediaWorker] failed to handle message %s: %v", msg.ID, err)
+			s.consumer.Fail(msg.ID, err)
+			continue
+		}
+		if err := s.consumer.Ack(msg.ID); err != nil {
+			log.Printf("[MediaWorker] %v", err)
+		}
+	}
+}
+
+// Stop chờ media đang xử lý xong rồi mới return
+func (s *MediaWorker) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+func (s *MediaWorker) handle(msg redis.XMessage) error {
+	payload, ok := msg.Values["payload"].(string)
+	if !ok {
+		log.Printf("[MediaWorker] drop malformed message %s", msg.ID)
+		return nil
+	}
+
+	if msg.Values["type"] != model.MediaUploadVerified {
+		return nil
+	}
+
+	var event model.MediaUploadVerifiedEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Printf("[MediaWorker] drop undecodable message %s: %v", msg.ID, err)
+		return nil
+	}
+	return s.processor.Process(event.MediaID)
+}
@@ -51,3 +51,59 @@ func (c *FollowServiceClient) GetFollowers(userID string) ([]string, error) {
 
 	return followerIDs, nil
 }
+
+// CountFollowers - số follower của user, rẻ hơn GetFollowers khi chỉ cần so với ngưỡng
+func (c *FollowServiceClient) CountFollowers(userID string) (int64, error) {
+	url := fmt.Sprintf("%s/follows/%s/followers/count", c.BaseURL, userID)
+
+	resp, err := c.Client.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call follow service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("follow service returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Followers int64 `json:"followers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("decode response failed: %w", err)
+	}
+	return result.Followers, nil
+}
+
+func (c *FollowServiceClient) GetFollowees(userID string) ([]string, error) {
+	url := fmt.Sprintf("%s/follows/%s/followees", c.BaseURL, userID)
+
+	resp, err := c.Client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call follow service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("follow service returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		UserID    string `json:"user_id"`
+		Followees []struct {
+			FolloweeID string    `json:"followee_id"`
+			CreatedAt  time.Time `json:"created_at"`
+		} `json:"followees"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response failed: %w", err)
+	}
+
+	// Extract only followee IDs
+	followeeIDs := make([]string, 0, len(result.Followees))
+	for _, f := range result.Followees {
+		followeeIDs = append(followeeIDs, f.FolloweeID)
+	}
+
+	return followeeIDs, nil
+}
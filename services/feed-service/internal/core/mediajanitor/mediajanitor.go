@@ -0,0 +1,255 @@
+package mediajanitor
+
+import (
+	"context"
+	"expvar"
+	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"fmt"
+	"log"
+	"time"
+)
+
+// metrics xem qua GET /debug/vars (key media_janitor), dry run đếm vào các key dry_run_*
+var metrics = expvar.NewMap("media_janitor")
+
+type JanitorConfig struct {
+	Interval    time.Duration
+	Retention   time.Duration // media pending/failed tạo quá retention thì xoá cả row lẫn object
+	OrphanGrace time.Duration // object không có row chỉ xoá khi cũ hơn grace (rendition vừa upload chưa kịp ghi row)
+	BatchSize   int
+	Timeout     time.Duration // cho 1 lần chạy
+	DryRun      bool          // chỉ log và đếm, không xoá gì
+}
+
+// Report - kết quả 1 lần chạy, dry run thì là số lượng sẽ bị xoá
+type Report struct {
+	Media        int64 // row medias
+	MediaObjects int64 // object gốc + rendition của các media đó
+	Orphans      int64 // object không có row nào trỏ tới
+	OrphanBytes  int64
+}
+
+// MediaRecords - phần của store.MediaStore mà janitor dùng
+type MediaRecords interface {
+	ListAbandonedMedia(cutoff time.Time, afterID string, limit int) ([]store.AbandonedMedia, error)
+	DeleteAbandonedMedia(mediaIDs []string, cutoff time.Time) ([]string, error)
+	ReferencedObjectKeys(objectKeys []string) (map[string]bool, error)
+}
+
+// MediaJanitor định kỳ dọn media bị bỏ dở (pending không bao giờ gắn vào post, failed)
+// và object trong bucket không còn row medias/media_renditions nào trỏ tới
+type MediaJanitor struct {
+	workerpocessor.BaseWorkerProcessor
+	mediaStore  MediaRecords
+	objectStore s3client.ObjectStore
+	cfg         JanitorConfig
+	ctx         context.Context
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func NewMediaJanitor(mediaStore_ *store.MediaStore, cfg JanitorConfig) *MediaJanitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &MediaJanitor{
+		mediaStore:  mediaStore_,
+		objectStore: mediaStore_.S3client,
+		cfg:         cfg,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	j.Init(j)
+	return j
+}
+
+func (j *MediaJanitor) RunningTask() error {
+	defer close(j.done)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := j.Run(j.ctx); err != nil {
+				log.Printf("[MediaJanitor] %v", err)
+			}
+		}
+	}
+}
+
+// Stop huỷ lần dọn đang chạy (phần còn lại để lần sau) rồi chờ goroutine thoát
+func (j *MediaJanitor) Stop() error {
+	j.cancel()
+	<-j.done
+	return nil
+}
+
+// Run chạy 1 lần: dọn media bỏ dở trước (object của chúng thành orphan nếu xoá S3 lỗi), rồi orphan
+func (j *MediaJanitor) Run(ctx context.Context) (Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, j.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var report Report
+	err := j.sweepAbandoned(ctx, &report)
+	if err == nil {
+		err = j.sweepOrphans(ctx, &report)
+	}
+	j.record(report, start, err)
+
+	mode := "deleted"
+	if j.cfg.DryRun {
+		mode = "dry run, would delete"
+	}
+	log.Printf("[MediaJanitor] %s %d medias (%d objects), %d orphan objects (%d bytes) in %v",
+		mode, report.Media, report.MediaObjects, report.Orphans, report.OrphanBytes, time.Since(start))
+	return report, err
+}
+
+func (j *MediaJanitor) sweepAbandoned(ctx context.Context, report *Report) error {
+	cutoff := time.Now().Add(-j.cfg.Retention)
+	after := ""
+	for ctx.Err() == nil {
+		batch, err := j.mediaStore.ListAbandonedMedia(cutoff, after, j.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		after = batch[len(batch)-1].MediaID
+
+		if j.cfg.DryRun {
+			for _, media := range batch {
+				log.Printf("[MediaJanitor] dry run: media %s %v", media.MediaID, media.ObjectKeys)
+				report.Media++
+				report.MediaObjects += int64(len(media.ObjectKeys))
+			}
+		} else if err := j.deleteAbandoned(ctx, batch, cutoff, report); err != nil {
+			return err
+		}
+
+		if len(batch) < j.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// deleteAbandoned xoá row trước rồi mới xoá object: xoá S3 lỗi thì object còn lại thành
+// orphan và được sweepOrphans dọn, ngược lại sẽ có row trỏ tới object không tồn tại
+func (j *MediaJanitor) deleteAbandoned(ctx context.Context, batch []store.AbandonedMedia, cutoff time.Time, report *Report) error {
+	ids := make([]string, len(batch))
+	for i, media := range batch {
+		ids[i] = media.MediaID
+	}
+	deleted, err := j.mediaStore.DeleteAbandonedMedia(ids, cutoff)
+	if err != nil {
+		return err
+	}
+
+	isDeleted := make(map[string]bool, len(deleted))
+	for _, id := range deleted {
+		isDeleted[id] = true
+	}
+	var keys []string
+	for _, media := range batch {
+		if !isDeleted[media.MediaID] {
+			continue
+		}
+		keys = append(keys, media.ObjectKeys...)
+		// part của multipart upload dở dang không phải object, WalkObjects không thấy được
+		if media.UploadID != "" {
+			if err := j.objectStore.AbortMultipartUpload(ctx, media.ObjectKeys[0], media.UploadID); err != nil {
+				metrics.Add("upload_abort_errors", 1)
+				log.Printf("[MediaJanitor] %v", err)
+			}
+		}
+	}
+	report.Media += int64(len(deleted))
+
+	n, err := j.objectStore.DeleteObjects(ctx, keys)
+	report.MediaObjects += int64(n)
+	if err != nil {
+		metrics.Add("object_delete_errors", 1)
+		log.Printf("[MediaJanitor] %v", err)
+	}
+	return nil
+}
+
+func (j *MediaJanitor) sweepOrphans(ctx context.Context, report *Report) error {
+	cutoff := time.Now().Add(-j.cfg.OrphanGrace)
+	return j.objectStore.WalkObjects(ctx, func(page []s3client.ObjectSummary) error {
+		var candidates []string
+		sizes := make(map[string]int64, len(page))
+		for _, obj := range page {
+			if obj.LastModified.Before(cutoff) {
+				candidates = append(candidates, obj.Key)
+				sizes[obj.Key] = obj.Size
+			}
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		referenced, err := j.mediaStore.ReferencedObjectKeys(candidates)
+		if err != nil {
+			return err
+		}
+		var orphans []string
+		var bytes int64
+		for _, key := range candidates {
+			if !referenced[key] {
+				orphans = append(orphans, key)
+				bytes += sizes[key]
+			}
+		}
+		if len(orphans) == 0 {
+			return nil
+		}
+
+		if j.cfg.DryRun {
+			log.Printf("[MediaJanitor] dry run: orphan objects %v", orphans)
+			report.Orphans += int64(len(orphans))
+			report.OrphanBytes += bytes
+			return nil
+		}
+		n, err := j.objectStore.DeleteObjects(ctx, orphans)
+		report.Orphans += int64(n)
+		if n == len(orphans) { // xoá lỗi 1 phần thì không biết key nào, không cộng bytes
+			report.OrphanBytes += bytes
+		}
+		if err != nil {
+			metrics.Add("object_delete_errors", 1)
+			log.Printf("[MediaJanitor] %v", err)
+		}
+		return nil
+	})
+}
+
+func (j *MediaJanitor) record(report Report, start time.Time, err error) {
+	prefix := ""
+	if j.cfg.DryRun {
+		prefix = "dry_run_"
+	}
+	metrics.Add(prefix+"runs", 1)
+	if err != nil {
+		metrics.Add(prefix+"run_errors", 1)
+	}
+	metrics.Add(prefix+"media_deleted", report.Media)
+	metrics.Add(prefix+"media_objects_deleted", report.MediaObjects)
+	metrics.Add(prefix+"orphans_deleted", report.Orphans)
+	metrics.Add(prefix+"orphan_bytes_deleted", report.OrphanBytes)
+
+	lastRun := new(expvar.Int)
+	lastRun.Set(start.Unix())
+	metrics.Set(prefix+"last_run_unix", lastRun)
+	duration := new(expvar.String)
+	duration.Set(fmt.Sprint(time.Since(start).Round(time.Millisecond)))
+	metrics.Set(prefix+"last_run_duration", duration)
+}
@@ -0,0 +1,198 @@
+package mediajanitor
+
+import (
+	"context"
+	"errors"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type fakeMedia struct {
+	store.AbandonedMedia
+	createdAt time.Time
+}
+
+// fakeRecords - medias/media_renditions trong memory: abandoned là media pending/failed,
+// live là object key của media đã gắn vào post
+type fakeRecords struct {
+	abandoned []fakeMedia
+	live      map[string]bool
+	listCalls int
+}
+
+func (f *fakeRecords) ListAbandonedMedia(cutoff time.Time, afterID string, limit int) ([]store.AbandonedMedia, error) {
+	f.listCalls++
+	sort.Slice(f.abandoned, func(i, j int) bool { return f.abandoned[i].MediaID < f.abandoned[j].MediaID })
+	var out []store.AbandonedMedia
+	for _, m := range f.abandoned {
+		if m.MediaID > afterID && m.createdAt.Before(cutoff) && len(out) < limit {
+			out = append(out, m.AbandonedMedia)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRecords) DeleteAbandonedMedia(mediaIDs []string, cutoff time.Time) ([]string, error) {
+	remove := make(map[string]bool, len(mediaIDs))
+	for _, id := range mediaIDs {
+		remove[id] = true
+	}
+	var deleted []string
+	kept := f.abandoned[:0]
+	for _, m := range f.abandoned {
+		if remove[m.MediaID] && m.createdAt.Before(cutoff) {
+			deleted = append(deleted, m.MediaID)
+			continue
+		}
+		kept = append(kept, m)
+	}
+	f.abandoned = kept
+	return deleted, nil
+}
+
+func (f *fakeRecords) ReferencedObjectKeys(objectKeys []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	for _, key := range objectKeys {
+		if f.live[key] {
+			referenced[key] = true
+		}
+		for _, m := range f.abandoned {
+			for _, k := range m.ObjectKeys {
+				if k == key {
+					referenced[key] = true
+				}
+			}
+		}
+	}
+	return referenced, nil
+}
+
+func newTestJanitor(records *fakeRecords, objects *s3client.FakeObjectStore, dryRun bool) *MediaJanitor {
+	return &MediaJanitor{
+		mediaStore:  records,
+		objectStore: objects,
+		cfg: JanitorConfig{
+			Retention:   24 * time.Hour,
+			OrphanGrace: time.Hour,
+			BatchSize:   2,
+			Timeout:     time.Minute,
+			DryRun:      dryRun,
+		},
+	}
+}
+
+// uploadAt ghi object với LastModified = at
+func uploadAt(objects *s3client.FakeObjectStore, at time.Time, keys ...string) {
+	objects.SetClock(func() time.Time { return at })
+	for _, key := range keys {
+		objects.Upload(key, []byte(key), "image/png")
+	}
+	objects.SetClock(time.Now)
+}
+
+// setup: 3 media bỏ dở quá retention (1 multipart còn mở), 1 media pending mới tạo,
+// 1 media đã gắn post, 1 orphan cũ và 1 orphan mới upload
+func setup(t *testing.T) (*fakeRecords, *s3client.FakeObjectStore, string) {
+	t.Helper()
+	old := time.Now().Add(-48 * time.Hour)
+	objects := s3client.NewFakeObjectStore("media")
+	uploadAt(objects, old,
+		"u1/a_1.png", "u1/a_1.png.thumb.jpg",
+		"u1/b_2.png",
+		"u1/live.png", "u1/live.png.thumb.jpg",
+		"u1/orphan_old.jpg",
+	)
+	uploadAt(objects, time.Now(), "u1/d_4.png", "u1/orphan_new.jpg")
+
+	uploadID, err := objects.CreateMultipartUpload(context.Background(), "u1/c_3.mp4", "video/mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := objects.UploadPart(uploadID, 1, []byte("part")); err != nil {
+		t.Fatal(err)
+	}
+
+	records := &fakeRecords{
+		abandoned: []fakeMedia{
+			{store.AbandonedMedia{MediaID: "a", ObjectKeys: []string{"u1/a_1.png", "u1/a_1.png.thumb.jpg"}}, old},
+			{store.AbandonedMedia{MediaID: "b", ObjectKeys: []string{"u1/b_2.png"}}, old},
+			{store.AbandonedMedia{MediaID: "c", ObjectKeys: []string{"u1/c_3.mp4"}, UploadID: uploadID}, old},
+			{store.AbandonedMedia{MediaID: "d", ObjectKeys: []string{"u1/d_4.png"}}, time.Now()},
+		},
+		live: map[string]bool{"u1/live.png": true, "u1/live.png.thumb.jpg": true},
+	}
+	return records, objects, uploadID
+}
+
+func TestRun(t *testing.T) {
+	records, objects, uploadID := setup(t)
+	janitor := newTestJanitor(records, objects, false)
+
+	report, err := janitor.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Report{Media: 3, MediaObjects: 4, Orphans: 1, OrphanBytes: int64(len("u1/orphan_old.jpg"))}
+	if report != want {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+	wantKeys := []string{"u1/d_4.png", "u1/live.png", "u1/live.png.thumb.jpg", "u1/orphan_new.jpg"}
+	if got := objects.Keys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("remaining objects = %v, want %v", got, wantKeys)
+	}
+	if len(records.abandoned) != 1 || records.abandoned[0].MediaID != "d" {
+		t.Errorf("remaining media rows = %+v, want only d", records.abandoned)
+	}
+	if _, err := objects.ListParts(context.Background(), "u1/c_3.mp4", uploadID); !errors.Is(err, s3client.ErrUploadNotFound) {
+		t.Errorf("multipart upload of c should be aborted, ListParts err = %v", err)
+	}
+	// batch 2: trang đầy (a, b), trang thiếu (c) thì dừng
+	if records.listCalls != 2 {
+		t.Errorf("ListAbandonedMedia called %d times, want 2", records.listCalls)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	records, objects, uploadID := setup(t)
+	before := objects.Keys()
+	janitor := newTestJanitor(records, objects, true)
+
+	report, err := janitor.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// dry run không xoá row nên object của media bỏ dở vẫn được tính là referenced, không phải orphan
+	want := Report{Media: 3, MediaObjects: 4, Orphans: 1, OrphanBytes: int64(len("u1/orphan_old.jpg"))}
+	if report != want {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+	if got := objects.Keys(); !reflect.DeepEqual(got, before) {
+		t.Errorf("dry run deleted objects: %v, want %v", got, before)
+	}
+	if len(records.abandoned) != 4 {
+		t.Errorf("dry run deleted media rows, %d left", len(records.abandoned))
+	}
+	if _, err := objects.ListParts(context.Background(), "u1/c_3.mp4", uploadID); err != nil {
+		t.Errorf("dry run aborted multipart upload: %v", err)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	records, objects, _ := setup(t)
+	janitor := newTestJanitor(records, objects, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := janitor.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	if len(records.abandoned) != 4 {
+		t.Errorf("cancelled run deleted media rows, %d left", len(records.abandoned))
+	}
+}
@@ -0,0 +1,51 @@
+package mediamanager
+
+import (
+	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/core/mediaprocessor"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/model"
+	"fmt"
+)
+
+const consumerGroup = "feed-service-media"
+
+type MediaConfig struct {
+	NumWorkers int
+	Retry      eventstream.RetryPolicy
+}
+
+// MediaManager chạy các media worker xử lý media bất đồng bộ sau khi upload
+type MediaManager struct {
+	mediaworkers []*workerpocessor.MediaWorker
+}
+
+func NewMediaManager(cfg MediaConfig, redisclient_ *redisclient.RedisClient, processor *mediaprocessor.MediaProcessor) *MediaManager {
+	m := MediaManager{}
+	for i := 0; i < cfg.NumWorkers; i++ {
+		consumer := eventstream.NewConsumer(redisclient_, model.MediaEventStream, consumerGroup, fmt.Sprintf("media-worker-%d", i), cfg.Retry)
+		m.mediaworkers = append(m.mediaworkers, workerpocessor.NewMediaWorker(consumer, processor))
+	}
+	return &m
+}
+
+func (m *MediaManager) Start() error {
+	for _, e := range m.mediaworkers {
+		err := e.Start()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MediaManager) Stop() error {
+	for _, e := range m.mediaworkers {
+		err := e.Stop()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
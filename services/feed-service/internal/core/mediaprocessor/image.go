@@ -0,0 +1,184 @@
+package mediaprocessor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif" // đăng ký decoder cho image.Decode
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// encodedImage - 1 rendition đã encode lại (không còn EXIF/GPS vì encoder không ghi metadata)
+type encodedImage struct {
+	data        []byte
+	ext         string
+	contentType string
+	width       int
+	height      int
+}
+
+// maxImagePixels - ảnh khai báo nhiều pixel hơn bị từ chối trước khi decode: decode cấp phát
+// ~4 byte/pixel nên 1 file nhỏ khai báo 100000x100000 có thể làm feed-service hết RAM
+const maxImagePixels = 50_000_000
+
+// decodeImage decode ảnh gốc và xoay theo EXIF Orientation (metadata sẽ mất khi encode lại).
+// Kích thước được kiểm tra qua header (DecodeConfig) trước khi decode.
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnprocessable, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxImagePixels {
+		return nil, fmt.Errorf("%w: image is %dx%d pixels, limit is %d", ErrUnprocessable, cfg.Width, cfg.Height, maxImagePixels)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnprocessable, err)
+	}
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// fit thu nhỏ để cạnh dài nhất <= maxEdge, không phóng to ảnh nhỏ
+func fit(img image.Image, maxEdge int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if maxEdge <= 0 || (w <= maxEdge && h <= maxEdge) {
+		return img
+	}
+	nw, nh := maxEdge, h*maxEdge/w
+	if h > w {
+		nw, nh = w*maxEdge/h, maxEdge
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, max(nw, 1), max(nh, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// encode ra PNG nếu ảnh có trong suốt, còn lại JPEG
+func encode(img image.Image, quality int) (encodedImage, error) {
+	var buf bytes.Buffer
+	out := encodedImage{width: img.Bounds().Dx(), height: img.Bounds().Dy()}
+
+	if o, ok := img.(interface{ Opaque() bool }); ok && !o.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return encodedImage{}, fmt.Errorf("failed to encode png: %w", err)
+		}
+		out.ext, out.contentType = ".png", "image/png"
+	} else {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return encodedImage{}, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+		out.ext, out.contentType = ".jpg", "image/jpeg"
+	}
+	out.data = buf.Bytes()
+	return out, nil
+}
+
+// orient áp dụng EXIF Orientation (2..8) để ảnh hiển thị đúng chiều sau khi bỏ metadata
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // lật ngang
+				dx, dy = w-1-x, y
+			case 3: // xoay 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // lật dọc
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // xoay 90 theo chiều kim đồng hồ
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // xoay 270 theo chiều kim đồng hồ
+				dx, dy = y, w-1-x
+			}
+			i, j := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[j:j+4], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation đọc tag Orientation (0x0112) trong IFD0 của segment APP1 Exif, mặc định 1
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan / end of image
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if o := exifOrientation(data[i+4 : i+2+size]); o > 0 {
+				return o
+			}
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+func exifOrientation(seg []byte) int {
+	if len(seg) < 14 || string(seg[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := seg[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 0 || ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(order.Uint16(tiff[ifd:]))
+	for k := 0; k < n; k++ {
+		e := ifd + 2 + k*12
+		if e+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[e:]) == 0x0112 {
+			return int(order.Uint16(tiff[e+8:]))
+		}
+	}
+	return 0
+}
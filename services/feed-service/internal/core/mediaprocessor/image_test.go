@@ -0,0 +1,69 @@
+package mediaprocessor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// pngWithSize ghi 1 PNG nhỏ hợp lệ rồi sửa width/height trong chunk IHDR (và CRC) thành w x h,
+// file vẫn chỉ vài trăm byte nhưng header khai báo kích thước lớn
+func pngWithSize(t *testing.T, w, h uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// 8 byte signature, 4 byte length, "IHDR" rồi 13 byte data, 4 byte CRC
+	ihdr := data[12 : 12+4+13]
+	binary.BigEndian.PutUint32(ihdr[4:8], w)
+	binary.BigEndian.PutUint32(ihdr[8:12], h)
+	binary.BigEndian.PutUint32(data[12+4+13:], crc32.ChecksumIEEE(ihdr))
+	return data
+}
+
+func TestDecodeImageRejectsOversizedHeader(t *testing.T) {
+	data := pngWithSize(t, 100000, 100000)
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("crafted header should parse: %v", err)
+	}
+	if cfg.Width != 100000 || cfg.Height != 100000 {
+		t.Fatalf("crafted header = %dx%d", cfg.Width, cfg.Height)
+	}
+
+	_, err = decodeImage(data)
+	if !errors.Is(err, ErrUnprocessable) {
+		t.Fatalf("decodeImage() error = %v, want ErrUnprocessable", err)
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+	src.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := decodeImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("decodeImage() error = %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+		t.Fatalf("decoded size = %dx%d, want 40x30", b.Dx(), b.Dy())
+	}
+}
+
+func TestDecodeImageGarbage(t *testing.T) {
+	if _, err := decodeImage([]byte("not an image")); !errors.Is(err, ErrUnprocessable) {
+		t.Fatalf("decodeImage() error = %v, want ErrUnprocessable", err)
+	}
+}
@@ -0,0 +1,234 @@
+package mediaprocessor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrUnprocessable - file hỏng hoặc định dạng không hỗ trợ, retry không giúp được
+var ErrUnprocessable = errors.New("media cannot be processed")
+
+// RenditionSpec - 1 cỡ ảnh cần tạo, MaxEdge là cạnh dài tối đa (px)
+type RenditionSpec struct {
+	Name    string
+	MaxEdge int
+}
+
+type ProcessConfig struct {
+	ImageRenditions []RenditionSpec // cho ảnh, video chỉ có full + poster
+	PosterMaxEdge   int
+	JPEGQuality     int
+	FFmpegPath      string
+	WorkDir         string        // thư mục file tạm khi xử lý video, rỗng = os.TempDir()
+	Timeout         time.Duration // cho cả 1 media: tải, xử lý, upload
+}
+
+// MediaProcessor tạo rendition cho media đã upload: ảnh được xoay theo EXIF, thu nhỏ và
+// encode lại (mất toàn bộ metadata), video được remux bỏ metadata và lấy poster frame
+type MediaProcessor struct {
+	MediaStore     *store.MediaStore
+	RenditionStore *store.RenditionStore
+	cfg            ProcessConfig
+}
+
+func NewMediaProcessor(mediaStore *store.MediaStore, renditionStore *store.RenditionStore, cfg ProcessConfig) *MediaProcessor {
+	return &MediaProcessor{
+		MediaStore:     mediaStore,
+		RenditionStore: renditionStore,
+		cfg:            cfg,
+	}
+}
+
+// Process tạo và ghi rendition của 1 media. Media đã bị xoá hoặc không ở trạng thái
+// uploaded thì bỏ qua; file không xử lý được thì media chuyển failed và không trả lỗi.
+func (p *MediaProcessor) Process(mediaID string) error {
+	medias, err := p.MediaStore.GetMediaByIDs([]string{mediaID})
+	if err != nil {
+		return err
+	}
+	if len(medias) == 0 || medias[0].Status != model.MediaUploaded {
+		return nil
+	}
+	media := medias[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+
+	var renditions []model.Rendition
+	switch media.MediaType {
+	case "image":
+		renditions, err = p.processImage(ctx, media)
+	case "video":
+		renditions, err = p.processVideo(ctx, media)
+	default:
+		err = fmt.Errorf("%w: unknown media type %s", ErrUnprocessable, media.MediaType)
+	}
+	if errors.Is(err, ErrUnprocessable) {
+		log.Printf("[MediaProcessor] media %s is unprocessable: %v", mediaID, err)
+		return p.MediaStore.MarkMediaFailed(mediaID, model.MediaUploaded)
+	}
+	if err != nil {
+		return fmt.Errorf("[MediaProcessor] media %s: %w", mediaID, err)
+	}
+
+	if err := p.RenditionStore.ReplaceRenditions(mediaID, renditions); err != nil {
+		return err
+	}
+	log.Printf("[MediaProcessor] created %d renditions for media %s", len(renditions), mediaID)
+	return nil
+}
+
+func (p *MediaProcessor) processImage(ctx context.Context, media model.Media) ([]model.Rendition, error) {
+	body, err := p.openOriginal(ctx, media.Objectkeys3.String)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original: %w", err)
+	}
+
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	renditions := make([]model.Rendition, 0, len(p.cfg.ImageRenditions))
+	for _, spec := range p.cfg.ImageRenditions {
+		enc, err := encode(fit(img, spec.MaxEdge), p.cfg.JPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+		rd, err := p.uploadImage(ctx, media, spec.Name, enc)
+		if err != nil {
+			return nil, err
+		}
+		renditions = append(renditions, rd)
+	}
+	return renditions, nil
+}
+
+func (p *MediaProcessor) processVideo(ctx context.Context, media model.Media) ([]model.Rendition, error) {
+	ext, ok := videoContainers[media.ContentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported video type %q", ErrUnprocessable, media.ContentType)
+	}
+
+	dir, err := os.MkdirTemp(p.cfg.WorkDir, "media-"+media.MediaID+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "source"+ext)
+	if err := p.download(ctx, media.Objectkeys3.String, source); err != nil {
+		return nil, err
+	}
+
+	// full: video đã bỏ metadata
+	full := filepath.Join(dir, model.RenditionFull+ext)
+	if err := p.stripVideo(ctx, source, full); err != nil {
+		return nil, err
+	}
+	size, err := fileSize(full)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(full)
+	if err != nil {
+		return nil, err
+	}
+	fullKey := model.RenditionKey(media.UserID, media.MediaID, model.RenditionFull, ext)
+	err = p.MediaStore.S3client.PutObject(ctx, fullKey, f, media.ContentType)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// poster: frame đại diện, xử lý như ảnh
+	posterFile := filepath.Join(dir, "poster.png")
+	if err := p.extractPoster(ctx, source, posterFile); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(posterFile)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := encode(fit(img, p.cfg.PosterMaxEdge), p.cfg.JPEGQuality)
+	if err != nil {
+		return nil, err
+	}
+	poster, err := p.uploadImage(ctx, media, model.RenditionPoster, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return []model.Rendition{
+		{
+			MediaID:     media.MediaID,
+			Name:        model.RenditionFull,
+			Objectkeys3: fullKey,
+			ContentType: media.ContentType,
+			SizeBytes:   size,
+		},
+		poster,
+	}, nil
+}
+
+func (p *MediaProcessor) uploadImage(ctx context.Context, media model.Media, name string, enc encodedImage) (model.Rendition, error) {
+	key := model.RenditionKey(media.UserID, media.MediaID, name, enc.ext)
+	if err := p.MediaStore.S3client.PutObject(ctx, key, bytes.NewReader(enc.data), enc.contentType); err != nil {
+		return model.Rendition{}, err
+	}
+	return model.Rendition{
+		MediaID:     media.MediaID,
+		Name:        name,
+		Objectkeys3: key,
+		ContentType: enc.contentType,
+		Width:       enc.width,
+		Height:      enc.height,
+		SizeBytes:   int64(len(enc.data)),
+	}, nil
+}
+
+// download ghi object ra file để ffmpeg đọc (video có thể lớn, không đọc vào memory)
+func (p *MediaProcessor) download(ctx context.Context, objectKey, path string) error {
+	body, err := p.openOriginal(ctx, objectKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to download %s: %w", objectKey, err)
+	}
+	return f.Close()
+}
+
+func (p *MediaProcessor) openOriginal(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	body, err := p.MediaStore.S3client.GetObject(ctx, objectKey)
+	if errors.Is(err, s3client.ErrObjectNotFound) {
+		return nil, fmt.Errorf("%w: original object %s is gone", ErrUnprocessable, objectKey)
+	}
+	return body, err
+}
@@ -0,0 +1,58 @@
+package mediaprocessor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// videoContainers - Content-Type -> phần mở rộng giữ nguyên container khi remux
+var videoContainers = map[string]string{
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+}
+
+// stripVideo remux (không transcode) chỉ giữ stream video/audio, bỏ metadata/chapter/data track (GPS)
+func (p *MediaProcessor) stripVideo(ctx context.Context, input, output string) error {
+	args := []string{"-y", "-v", "error", "-i", input,
+		"-map", "0:v:0", "-map", "0:a?",
+		"-map_metadata", "-1", "-map_chapters", "-1",
+		"-c", "copy"}
+	if ext := filepath.Ext(output); ext == ".mp4" || ext == ".mov" {
+		args = append(args, "-movflags", "+faststart") // phát được trước khi tải hết
+	}
+	return p.ffmpeg(ctx, append(args, output)...)
+}
+
+// extractPoster lấy 1 frame đại diện trong các frame đầu làm poster (PNG để encode lại như ảnh)
+func (p *MediaProcessor) extractPoster(ctx context.Context, input, output string) error {
+	return p.ffmpeg(ctx, "-y", "-v", "error", "-i", input, "-vf", "thumbnail", "-frames:v", "1", output)
+}
+
+func (p *MediaProcessor) ffmpeg(ctx context.Context, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.cfg.FFmpegPath, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// ffmpeg chạy xong nhưng báo lỗi = file hỏng/không hỗ trợ, retry cũng vô ích
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return fmt.Errorf("%w: ffmpeg: %s", ErrUnprocessable, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,136 @@
+package mediaverifier
+
+import (
+	"context"
+	"errors"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	// ErrMediaNotUploaded - object chưa có trên S3, client upload xong rồi tạo post lại
+	ErrMediaNotUploaded = errors.New("media has not been uploaded yet")
+	// ErrMediaRejected - object sai loại/kích thước hoặc quá hạn upload, media bị đánh dấu failed
+	ErrMediaRejected = errors.New("media upload is invalid")
+)
+
+type VerifyConfig struct {
+	MaxSize      map[string]int64 // giới hạn bytes theo media_type
+	UploadWindow time.Duration    // = hạn presigned PUT URL, quá hạn mà chưa có object thì failed
+	// MultipartWindow thay cho UploadWindow với multipart upload (file lớn, client resume được)
+	MultipartWindow time.Duration
+	HeadTimeout     time.Duration
+}
+
+// MediaRecords - phần của store.MediaStore mà verifier dùng
+type MediaRecords interface {
+	GetMediaByIDs(mediaIDs []string) ([]model.Media, error)
+	MarkMediaUploaded(mediaID string, contentType string, size int64) (bool, error)
+	MarkMediaFailed(mediaID string, fromStatus string) error
+}
+
+type EventPublisher interface {
+	Publish(eventType string, event interface{}) (string, error)
+}
+
+// MediaVerifier xác nhận client đã thật sự PUT object lên presigned URL trước khi media được gắn vào post
+type MediaVerifier struct {
+	MediaStore  MediaRecords
+	objectStore s3client.ObjectStore
+	producer    EventPublisher // media:events
+	cfg         VerifyConfig
+}
+
+func NewMediaVerifier(mediaStore *store.MediaStore, producer_ *eventstream.Producer, cfg VerifyConfig) *MediaVerifier {
+	return &MediaVerifier{
+		MediaStore:  mediaStore,
+		objectStore: mediaStore.S3client,
+		producer:    producer_,
+		cfg:         cfg,
+	}
+}
+
+// MaxSize - giới hạn bytes của media_type, 0 = không giới hạn
+func (v *MediaVerifier) MaxSize(mediaType string) int64 {
+	return v.cfg.MaxSize[mediaType]
+}
+
+// Verify kiểm tra từng media: uploaded thì bỏ qua, pending thì HeadObject rồi chuyển
+// uploaded/failed. Lỗi đầu tiên được trả về, bọc ErrMediaNotUploaded hoặc ErrMediaRejected.
+func (v *MediaVerifier) Verify(mediaIDs []string) error {
+	medias, err := v.MediaStore.GetMediaByIDs(mediaIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, media := range medias {
+		switch media.Status {
+		case model.MediaUploaded:
+			continue
+		case model.MediaFailed:
+			return fmt.Errorf("%w: %s", ErrMediaRejected, media.MediaID)
+		}
+		if err := v.verifyPending(media); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *MediaVerifier) verifyPending(media model.Media) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.cfg.HeadTimeout)
+	defer cancel()
+
+	info, err := v.objectStore.HeadObject(ctx, media.Objectkeys3.String)
+	if errors.Is(err, s3client.ErrObjectNotFound) {
+		window := v.cfg.UploadWindow
+		if media.UploadID != "" {
+			window = v.cfg.MultipartWindow
+		}
+		if time.Since(media.CreatedAt) <= window {
+			// URL còn hạn, có thể client đang upload
+			return fmt.Errorf("%w: %s", ErrMediaNotUploaded, media.MediaID)
+		}
+		return v.reject(media, "not uploaded before presigned URL expired")
+	}
+	if err != nil {
+		return fmt.Errorf("[MediaVerifier] %w", err)
+	}
+
+	if !model.IsAllowedContentType(media.MediaType, info.ContentType) {
+		return v.reject(media, fmt.Sprintf("content type %q does not match %s", info.ContentType, media.MediaType))
+	}
+	if info.Size <= 0 {
+		return v.reject(media, "empty object")
+	}
+	if maxSize := v.cfg.MaxSize[media.MediaType]; maxSize > 0 && info.Size > maxSize {
+		return v.reject(media, fmt.Sprintf("%d bytes exceeds %d", info.Size, maxSize))
+	}
+
+	changed, err := v.MediaStore.MarkMediaUploaded(media.MediaID, info.ContentType, info.Size)
+	if err != nil || !changed {
+		return err
+	}
+
+	// media worker tạo rendition; publish lỗi thì media chỉ chưa có rendition, không chặn post
+	if _, err := v.producer.Publish(model.MediaUploadVerified, model.MediaUploadVerifiedEvent{
+		MediaID:    media.MediaID,
+		UploadedAt: time.Now(),
+	}); err != nil {
+		log.Printf("[MediaVerifier] %v", err)
+	}
+	return nil
+}
+
+func (v *MediaVerifier) reject(media model.Media, reason string) error {
+	log.Printf("[MediaVerifier] rejected media %s: %s", media.MediaID, reason)
+	if err := v.MediaStore.MarkMediaFailed(media.MediaID, model.MediaPending); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrMediaRejected, media.MediaID, reason)
+}
@@ -0,0 +1,238 @@
+package mediaverifier
+
+import (
+	"database/sql"
+	"errors"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/model"
+	"testing"
+	"time"
+)
+
+// fakeRecords - bảng medias trong memory, MarkMediaUploaded/MarkMediaFailed chỉ đổi khi status khớp như SQL thật
+type fakeRecords struct {
+	medias map[string]*model.Media
+}
+
+func (f *fakeRecords) GetMediaByIDs(mediaIDs []string) ([]model.Media, error) {
+	var out []model.Media
+	for _, id := range mediaIDs {
+		if m, ok := f.medias[id]; ok {
+			out = append(out, *m)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRecords) MarkMediaUploaded(mediaID string, contentType string, size int64) (bool, error) {
+	m, ok := f.medias[mediaID]
+	if !ok || m.Status != model.MediaPending {
+		return false, nil
+	}
+	m.Status, m.ContentType, m.SizeBytes = model.MediaUploaded, contentType, size
+	return true, nil
+}
+
+func (f *fakeRecords) MarkMediaFailed(mediaID string, fromStatus string) error {
+	if m, ok := f.medias[mediaID]; ok && m.Status == fromStatus {
+		m.Status = model.MediaFailed
+	}
+	return nil
+}
+
+type fakePublisher struct {
+	events []interface{}
+}
+
+func (p *fakePublisher) Publish(eventType string, event interface{}) (string, error) {
+	p.events = append(p.events, event)
+	return "0-1", nil
+}
+
+func newTestVerifier(objects *s3client.FakeObjectStore, medias ...model.Media) (*MediaVerifier, *fakeRecords, *fakePublisher) {
+	records := &fakeRecords{medias: make(map[string]*model.Media)}
+	for i := range medias {
+		records.medias[medias[i].MediaID] = &medias[i]
+	}
+	publisher := &fakePublisher{}
+	return &MediaVerifier{
+		MediaStore:  records,
+		objectStore: objects,
+		producer:    publisher,
+		cfg: VerifyConfig{
+			MaxSize:         map[string]int64{"image": 1 << 10},
+			UploadWindow:    5 * time.Minute,
+			MultipartWindow: 6 * time.Hour,
+			HeadTimeout:     time.Second,
+		},
+	}, records, publisher
+}
+
+func pendingMedia(id string, age time.Duration) model.Media {
+	return model.Media{
+		MediaID:     id,
+		UserID:      "u1",
+		MediaType:   "image",
+		Objectkeys3: sql.NullString{String: "u1/" + id + "_a.png", Valid: true},
+		Status:      model.MediaPending,
+		CreatedAt:   time.Now().Add(-age),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		media       model.Media
+		upload      []byte // nil = client chưa upload
+		contentType string
+		wantErr     error
+		wantStatus  string
+		wantEvents  int
+	}{
+		{
+			name:        "uploaded object is accepted",
+			media:       pendingMedia("m1", time.Minute),
+			upload:      []byte("png"),
+			contentType: "image/png",
+			wantStatus:  model.MediaUploaded,
+			wantEvents:  1,
+		},
+		{
+			name:        "content type parameters are ignored",
+			media:       pendingMedia("m1", time.Minute),
+			upload:      []byte("png"),
+			contentType: "image/png; charset=binary",
+			wantStatus:  model.MediaUploaded,
+			wantEvents:  1,
+		},
+		{
+			name:       "missing object within upload window",
+			media:      pendingMedia("m1", time.Minute),
+			wantErr:    ErrMediaNotUploaded,
+			wantStatus: model.MediaPending,
+		},
+		{
+			name:       "missing object after upload window",
+			media:      pendingMedia("m1", 10*time.Minute),
+			wantErr:    ErrMediaRejected,
+			wantStatus: model.MediaFailed,
+		},
+		{
+			name: "multipart upload uses the longer window",
+			media: func() model.Media {
+				m := pendingMedia("m1", time.Hour)
+				m.UploadID = "fake-upload-1"
+				return m
+			}(),
+			wantErr:    ErrMediaNotUploaded,
+			wantStatus: model.MediaPending,
+		},
+		{
+			name:        "wrong content type",
+			media:       pendingMedia("m1", time.Minute),
+			upload:      []byte("mp4"),
+			contentType: "video/mp4",
+			wantErr:     ErrMediaRejected,
+			wantStatus:  model.MediaFailed,
+		},
+		{
+			name:        "empty object",
+			media:       pendingMedia("m1", time.Minute),
+			upload:      []byte{},
+			contentType: "image/png",
+			wantErr:     ErrMediaRejected,
+			wantStatus:  model.MediaFailed,
+		},
+		{
+			name:        "object over max size",
+			media:       pendingMedia("m1", time.Minute),
+			upload:      make([]byte, 1<<10+1),
+			contentType: "image/png",
+			wantErr:     ErrMediaRejected,
+			wantStatus:  model.MediaFailed,
+		},
+		{
+			name: "failed media stays rejected",
+			media: func() model.Media {
+				m := pendingMedia("m1", time.Minute)
+				m.Status = model.MediaFailed
+				return m
+			}(),
+			upload:      []byte("png"),
+			contentType: "image/png",
+			wantErr:     ErrMediaRejected,
+			wantStatus:  model.MediaFailed,
+		},
+		{
+			name: "uploaded media is not checked again",
+			media: func() model.Media {
+				m := pendingMedia("m1", time.Minute)
+				m.Status = model.MediaUploaded
+				return m
+			}(),
+			wantStatus: model.MediaUploaded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := s3client.NewFakeObjectStore("media")
+			if tt.upload != nil {
+				objects.Upload(tt.media.Objectkeys3.String, tt.upload, tt.contentType)
+			}
+			verifier, records, publisher := newTestVerifier(objects, tt.media)
+
+			err := verifier.Verify([]string{tt.media.MediaID})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := records.medias[tt.media.MediaID].Status; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+			if len(publisher.events) != tt.wantEvents {
+				t.Errorf("published %d events, want %d", len(publisher.events), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestVerifyRecordsObjectInfo(t *testing.T) {
+	objects := s3client.NewFakeObjectStore("media")
+	media := pendingMedia("m1", time.Minute)
+	objects.Upload(media.Objectkeys3.String, []byte("12345"), "image/webp")
+	verifier, records, publisher := newTestVerifier(objects, media)
+
+	if err := verifier.Verify([]string{"m1"}); err != nil {
+		t.Fatal(err)
+	}
+	got := records.medias["m1"]
+	if got.ContentType != "image/webp" || got.SizeBytes != 5 {
+		t.Errorf("recorded %q %d bytes, want image/webp 5 bytes", got.ContentType, got.SizeBytes)
+	}
+	event, ok := publisher.events[0].(model.MediaUploadVerifiedEvent)
+	if !ok || event.MediaID != "m1" {
+		t.Errorf("event = %#v", publisher.events[0])
+	}
+
+	// lần verify thứ 2 không publish lại
+	if err := verifier.Verify([]string{"m1"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("published %d events, want 1", len(publisher.events))
+	}
+}
+
+func TestVerifyStopsAtFirstError(t *testing.T) {
+	objects := s3client.NewFakeObjectStore("media")
+	first, second := pendingMedia("m1", time.Minute), pendingMedia("m2", time.Minute)
+	objects.Upload(second.Objectkeys3.String, []byte("png"), "image/png")
+	verifier, records, _ := newTestVerifier(objects, first, second)
+
+	if err := verifier.Verify([]string{"m1", "m2"}); !errors.Is(err, ErrMediaNotUploaded) {
+		t.Fatalf("Verify() error = %v, want ErrMediaNotUploaded", err)
+	}
+	if got := records.medias["m2"].Status; got != model.MediaPending {
+		t.Errorf("m2 status = %q, want pending", got)
+	}
+}
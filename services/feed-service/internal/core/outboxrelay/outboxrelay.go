@@ -0,0 +1,89 @@
+package outboxrelay
+
+import (
+	"encoding/json"
+	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/infra/eventstream"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"log"
+	"time"
+)
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retention    time.Duration // giữ row đã delivered bao lâu trước khi xoá
+}
+
+// OutboxRelay đọc bảng outbox và publish sang queue fan-out (Redis Stream),
+// row chỉ được đánh dấu delivered khi publish thành công.
+type OutboxRelay struct {
+	workerpocessor.BaseWorkerProcessor
+	outboxStore *store.OutboxStore
+	producer    *eventstream.Producer
+	cfg         RelayConfig
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func NewOutboxRelay(outboxStore_ *store.OutboxStore, producer_ *eventstream.Producer, cfg RelayConfig) *OutboxRelay {
+	s := &OutboxRelay{
+		outboxStore: outboxStore_,
+		producer:    producer_,
+		cfg:         cfg,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	s.Init(s)
+	return s
+}
+
+func (s *OutboxRelay) RunningTask() error {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-purgeTicker.C:
+			n, err := s.outboxStore.PurgeDelivered(s.cfg.Retention)
+			if err != nil {
+				log.Printf("[OutboxRelay] %v", err)
+			} else if n > 0 {
+				log.Printf("[OutboxRelay] purged %d delivered events", n)
+			}
+		case <-ticker.C:
+			s.relay()
+		}
+	}
+}
+
+// Stop chờ batch đang relay xong
+func (s *OutboxRelay) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// relay publish liên tục đến khi hết event hoặc gặp lỗi
+func (s *OutboxRelay) relay() {
+	for {
+		n, err := s.outboxStore.RelayPending(s.cfg.BatchSize, func(event model.OutboxEvent) error {
+			_, err := s.producer.Publish(event.EventType, json.RawMessage(event.Payload))
+			return err
+		})
+		if err != nil {
+			log.Printf("[OutboxRelay] relayed %d events before error: %v", n, err)
+			return
+		}
+		if n < s.cfg.BatchSize {
+			return
+		}
+	}
+}
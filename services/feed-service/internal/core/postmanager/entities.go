@@ -0,0 +1,107 @@
+package postmanager
+
+import (
+	"strings"
+	"unicode"
+)
+
+// giới hạn mỗi post, phần vượt quá bị bỏ qua (không báo lỗi)
+const (
+	maxHashtagsPerPost = 30
+	maxMentionsPerPost = 20
+	maxHashtagLen      = 100 // rune, = cột post_hashtags.hashtag
+	maxUsernameLen     = 50  // = users.username của user-service
+)
+
+// ParseHashtags trả về các #hashtag trong content theo thứ tự xuất hiện, đã NormalizeHashtag và bỏ trùng.
+// "#" phải đứng đầu hoặc sau ký tự không thuộc từ: "a#b" không phải hashtag.
+func ParseHashtags(content string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, e := range scanEntities(content, '#', isWordRune) {
+		tag, ok := NormalizeHashtag(e.Text)
+		if !ok || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == maxHashtagsPerPost {
+			break
+		}
+	}
+	return tags
+}
+
+// NormalizeHashtag bỏ "#" đầu và lowercase; hashtag hợp lệ gồm chữ (kể cả tiếng Việt có dấu),
+// số và "_", có ít nhất 1 chữ ("#2024" không phải hashtag)
+func NormalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	if tag == "" || len([]rune(tag)) > maxHashtagLen {
+		return "", false
+	}
+	hasLetter := false
+	for _, r := range tag {
+		if !isWordRune(r) {
+			return "", false
+		}
+		hasLetter = hasLetter || unicode.IsLetter(r)
+	}
+	return tag, hasLetter
+}
+
+// ParseMentions trả về các username được @mention (giữ nguyên hoa thường), bỏ trùng.
+// "@" sau chữ/số (vd email a@b.com) không phải mention; "." cuối câu không thuộc username.
+func ParseMentions(content string) []string {
+	var usernames []string
+	seen := map[string]bool{}
+	for _, e := range scanEntities(content, '@', isUsernameRune) {
+		username := strings.TrimRight(e.Text, ".")
+		if username == "" || len(username) > maxUsernameLen || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerPost {
+			break
+		}
+	}
+	return usernames
+}
+
+// entity - 1 chuỗi marker + (isChar)+ trong content, Start/End là vị trí rune
+// của marker và ngay sau ký tự cuối (client dùng để highlight)
+type entity struct {
+	Text       string // không gồm marker
+	Start, End int
+}
+
+// scanEntities tìm các chuỗi marker + (isChar)+ mà marker không dính vào từ phía trước.
+// Chuỗi dính liền chữ không thuộc isChar phía sau (vd "@bình") bị bỏ qua thay vì cắt ngang.
+func scanEntities(content string, marker rune, isChar func(rune) bool) []entity {
+	var found []entity
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != marker || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && isChar(runes[j]) {
+			j++
+		}
+		if j > i+1 && (j == len(runes) || !isWordRune(runes[j])) {
+			found = append(found, entity{Text: string(runes[i+1 : j]), Start: i, End: j})
+		}
+		i = j - 1
+	}
+	return found
+}
+
+// isWordRune - chữ, dấu kết hợp (tiếng Việt dạng NFD), số, "_"
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// isUsernameRune - ký tự username cho phép: ASCII chữ/số, "_", "."
+func isUsernameRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.')
+}
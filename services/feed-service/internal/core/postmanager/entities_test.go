@@ -0,0 +1,133 @@
+package postmanager
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHashtags(t *testing.T) {
+	many := make([]string, maxHashtagsPerPost+5)
+	for i := range many {
+		many[i] = fmt.Sprintf("#tag%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"empty", "", nil},
+		{"single", "hello #golang", []string{"golang"}},
+		{"start of content", "#golang rocks", []string{"golang"}},
+		{"lowercased", "#GoLang", []string{"golang"}},
+		{"duplicates ignore case", "#go #Go #GO #rust #go", []string{"go", "rust"}},
+		{"trailing punctuation", "đi chơi #cuoituan! hay #dulich, #biển.", []string{"cuoituan", "dulich", "biển"}},
+		{"inside parentheses", "(#golang)", []string{"golang"}},
+		{"vietnamese diacritics", "ăn #phởHàNội", []string{"phởhànội"}},
+		{"vietnamese combining marks", "ăn #pho\u031b\u0309", []string{"pho\u031b\u0309"}},
+		{"underscore and digits", "#go_1_22 #web3", []string{"go_1_22", "web3"}},
+		{"digits only", "năm #2024", nil},
+		{"glued to word", "a#b c#d", nil},
+		{"url fragment", "https://example.com/page#section", nil},
+		{"double marker", "##golang", []string{"golang"}},
+		{"marker only", "# #", nil},
+		{"max length", "#" + strings.Repeat("a", maxHashtagLen), []string{strings.Repeat("a", maxHashtagLen)}},
+		{"over max length", "#" + strings.Repeat("a", maxHashtagLen+1) + " #ok", []string{"ok"}},
+		{"over max length counts runes", "#" + strings.Repeat("ệ", maxHashtagLen), []string{strings.Repeat("ệ", maxHashtagLen)}},
+		{"capped per post", strings.Join(many, " "), func() []string {
+			want := make([]string, maxHashtagsPerPost)
+			for i := range want {
+				want[i] = fmt.Sprintf("tag%d", i)
+			}
+			return want
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseHashtags(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHashtags(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	many := make([]string, maxMentionsPerPost+5)
+	for i := range many {
+		many[i] = fmt.Sprintf("@user%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"empty", "", nil},
+		{"single", "cc @alice", []string{"alice"}},
+		{"keeps case", "@Alice", []string{"Alice"}},
+		{"duplicates", "@bob @alice @bob", []string{"bob", "alice"}},
+		{"case sensitive duplicates", "@bob @Bob", []string{"bob", "Bob"}},
+		{"email", "mail me at alice@example.com", nil},
+		{"trailing dot", "cảm ơn @bob.", []string{"bob"}},
+		{"trailing dots", "@bob...", []string{"bob"}},
+		{"dot inside username", "@bob.smith ok", []string{"bob.smith"}},
+		{"trailing punctuation", "@alice, @bob! (@carol) @dave?", []string{"alice", "bob", "carol", "dave"}},
+		{"vietnamese word after marker", "@bình ơi", nil},
+		{"vietnamese text around", "chào @binh_nguyen nhé", []string{"binh_nguyen"}},
+		{"marker only", "@ @. @", nil},
+		{"double marker", "@@alice", []string{"alice"}},
+		{"max length", "@" + strings.Repeat("a", maxUsernameLen), []string{strings.Repeat("a", maxUsernameLen)}},
+		{"over max length", "@" + strings.Repeat("a", maxUsernameLen+1) + " @ok", []string{"ok"}},
+		{"capped per post", strings.Join(many, " "), func() []string {
+			want := make([]string, maxMentionsPerPost)
+			for i := range want {
+				want[i] = fmt.Sprintf("user%d", i)
+			}
+			return want
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMentions(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMentions(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanEntities(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		marker  rune
+		isChar  func(rune) bool
+		want    []entity
+	}{
+		{"none", "no entities", '#', isWordRune, nil},
+		{"offsets", "#a b #cd", '#', isWordRune, []entity{{"a", 0, 2}, {"cd", 5, 8}}},
+		// offset tính theo rune, không theo byte
+		{"offsets count runes", "phở #ngon", '#', isWordRune, []entity{{"ngon", 4, 9}}},
+		{"stops at punctuation", "#tag!", '#', isWordRune, []entity{{"tag", 0, 4}}},
+		{"keeps duplicates", "#a #a", '#', isWordRune, []entity{{"a", 0, 2}, {"a", 3, 5}}},
+		{"raw text keeps trailing dot", "@bob.", '@', isUsernameRune, []entity{{"bob.", 0, 5}}},
+		{"glued to previous word", "x@bob", '@', isUsernameRune, nil},
+		{"glued to next word", "@bình", '@', isUsernameRune, nil},
+		{"marker at end", "hi @", '@', isUsernameRune, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanEntities(tt.content, tt.marker, tt.isChar)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanEntities(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+			runes := []rune(tt.content)
+			for _, e := range got {
+				if string(runes[e.Start]) != string(tt.marker) || string(runes[e.Start+1:e.End]) != e.Text {
+					t.Errorf("offsets [%d, %d) do not match %q", e.Start, e.End, e.Text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package postmanager
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"feedservice/internal/core/mediaverifier"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/model"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minPartSize   = 8 << 20 // S3 yêu cầu >= 5MB (trừ part cuối), 8MB cho mạng di động
+	maxParts      = 10000   // giới hạn của S3
+	partURLExpiry = time.Hour
+	s3CallTimeout = 30 * time.Second
+)
+
+var (
+	// ErrMediaNotFound - media không tồn tại hoặc không thuộc user
+	ErrMediaNotFound = errors.New("media not found")
+	// ErrNotMultipartUpload - media không có multipart upload đang mở (đã complete/abort, hoặc upload thường)
+	ErrNotMultipartUpload = errors.New("media has no open multipart upload")
+	// ErrInvalidUpload - size khai báo sai/vượt giới hạn, hoặc danh sách part khi complete không khớp S3
+	ErrInvalidUpload = errors.New("invalid multipart upload")
+)
+
+// partLayout chia size thành các part đều nhau, part size làm tròn lên MB
+func partLayout(size int64) (partSize int64, partCount int32) {
+	partSize = max(minPartSize, (size+maxParts-1)/maxParts)
+	partSize = (partSize + 1<<20 - 1) &^ (1<<20 - 1)
+	return partSize, int32((size + partSize - 1) / partSize)
+}
+
+// CreateMultipartMedia tạo media pending cùng multipart upload trên S3 và URL cho mọi part.
+// size là kích thước file client khai báo, phải nằm trong giới hạn của media_type.
+func (p *PostManager) CreateMultipartMedia(userID, mediatype, mediafilename, contentType string, size int64) (model.MediaUpload, error) {
+	if contentType != "" && !model.IsAllowedContentType(mediatype, contentType) {
+		return model.MediaUpload{}, fmt.Errorf("%w: %q for %s", ErrInvalidContentType, contentType, mediatype)
+	}
+	if _, ok := model.MediaContentTypes[mediatype]; !ok {
+		return model.MediaUpload{}, fmt.Errorf("%w: unknown media type %q", ErrInvalidUpload, mediatype)
+	}
+	if maxSize := p.mediaverifier.MaxSize(mediatype); size <= 0 || (maxSize > 0 && size > maxSize) {
+		return model.MediaUpload{}, fmt.Errorf("%w: size %d must be in (0, %d]", ErrInvalidUpload, size, maxSize)
+	}
+
+	mediaID := uuid.New().String()
+	objectKey := fmt.Sprintf("%s/%s_%s", userID, mediaID, mediafilename)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3CallTimeout)
+	defer cancel()
+	uploadID, err := p.MediaStore.S3client.CreateMultipartUpload(ctx, objectKey, contentType)
+	if err != nil {
+		return model.MediaUpload{}, err
+	}
+	if err := p.MediaStore.CreateMultipartMediaRecord(mediaID, userID, mediatype, objectKey, uploadID, size); err != nil {
+		if abortErr := p.MediaStore.S3client.AbortMultipartUpload(ctx, objectKey, uploadID); abortErr != nil {
+			log.Printf("[PostManager] %v", abortErr)
+		}
+		return model.MediaUpload{}, err
+	}
+
+	media := model.Media{MediaID: mediaID, Objectkeys3: sql.NullString{String: objectKey, Valid: true}, UploadID: uploadID, DeclaredSize: size}
+	upload, err := p.presignParts(media, nil)
+	if err != nil {
+		return model.MediaUpload{}, err
+	}
+	return model.MediaUpload{MediaID: mediaID, Multipart: &upload}, nil
+}
+
+// GetUploadParts cho client resume: các part S3 đã nhận và URL mới cho các part còn thiếu
+func (p *PostManager) GetUploadParts(userID, mediaID string) (model.MultipartUpload, error) {
+	media, err := p.openUpload(userID, mediaID)
+	if err != nil {
+		return model.MultipartUpload{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3CallTimeout)
+	defer cancel()
+	uploaded, err := p.MediaStore.S3client.ListParts(ctx, media.Objectkeys3.String, media.UploadID)
+	if errors.Is(err, s3client.ErrUploadNotFound) {
+		return model.MultipartUpload{}, ErrNotMultipartUpload
+	}
+	if err != nil {
+		return model.MultipartUpload{}, err
+	}
+	return p.presignParts(media, uploaded)
+}
+
+// CompleteUpload ghép các part client gửi lên (part_number + ETag) thành object rồi xác nhận
+// media ngay như lúc tạo post. Tổng size vượt giới hạn thì huỷ upload và media chuyển failed.
+func (p *PostManager) CompleteUpload(userID, mediaID string, parts []model.UploadPart) error {
+	media, err := p.openUpload(userID, mediaID)
+	if err != nil {
+		return err
+	}
+	objectKey := media.Objectkeys3.String
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3CallTimeout)
+	defer cancel()
+	listed, err := p.MediaStore.S3client.ListParts(ctx, objectKey, media.UploadID)
+	if errors.Is(err, s3client.ErrUploadNotFound) {
+		return ErrNotMultipartUpload
+	}
+	if err != nil {
+		return err
+	}
+
+	completed, total, err := matchParts(parts, listed, media.DeclaredSize)
+	if err != nil {
+		return err
+	}
+	if maxSize := p.mediaverifier.MaxSize(media.MediaType); maxSize > 0 && total > maxSize {
+		if err := p.abort(ctx, media); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s (%d bytes exceeds %d)", mediaverifier.ErrMediaRejected, mediaID, total, maxSize)
+	}
+
+	if err := p.MediaStore.S3client.CompleteMultipartUpload(ctx, objectKey, media.UploadID, completed); err != nil {
+		if errors.Is(err, s3client.ErrUploadNotFound) {
+			return ErrNotMultipartUpload
+		}
+		return err
+	}
+	return p.mediaverifier.Verify([]string{mediaID})
+}
+
+// AbortUpload huỷ multipart upload (S3 xoá các part đã nhận), media chuyển failed
+func (p *PostManager) AbortUpload(userID, mediaID string) error {
+	media, err := p.openUpload(userID, mediaID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s3CallTimeout)
+	defer cancel()
+	return p.abort(ctx, media)
+}
+
+func (p *PostManager) abort(ctx context.Context, media model.Media) error {
+	if err := p.MediaStore.S3client.AbortMultipartUpload(ctx, media.Objectkeys3.String, media.UploadID); err != nil {
+		return err
+	}
+	return p.MediaStore.MarkMediaFailed(media.MediaID, model.MediaPending)
+}
+
+// openUpload đọc media của user còn pending và là multipart upload
+func (p *PostManager) openUpload(userID, mediaID string) (model.Media, error) {
+	medias, err := p.MediaStore.GetMediaByIDs([]string{mediaID})
+	if err != nil {
+		return model.Media{}, err
+	}
+	if len(medias) == 0 || medias[0].UserID != userID {
+		return model.Media{}, ErrMediaNotFound
+	}
+	if medias[0].UploadID == "" || medias[0].Status != model.MediaPending {
+		return model.Media{}, ErrNotMultipartUpload
+	}
+	return medias[0], nil
+}
+
+// presignParts ký URL cho các part chưa có trong uploaded
+func (p *PostManager) presignParts(media model.Media, uploaded []s3client.UploadedPart) (model.MultipartUpload, error) {
+	partSize, partCount := partLayout(media.DeclaredSize)
+	upload := model.MultipartUpload{
+		MediaID:   media.MediaID,
+		PartSize:  partSize,
+		PartCount: partCount,
+		ExpiresAt: time.Now().Add(partURLExpiry),
+		Parts:     []model.UploadPart{},
+	}
+
+	done := make(map[int32]bool, len(uploaded))
+	for _, part := range uploaded {
+		done[part.PartNumber] = true
+		upload.Uploaded = append(upload.Uploaded, model.UploadPart{PartNumber: part.PartNumber, ETag: part.ETag, Size: part.Size})
+	}
+	for n := int32(1); n <= partCount; n++ {
+		if done[n] {
+			continue
+		}
+		url, err := p.MediaStore.S3client.PresignUploadPart(media.Objectkeys3.String, media.UploadID, n, partURLExpiry)
+		if err != nil {
+			return model.MultipartUpload{}, err
+		}
+		upload.Parts = append(upload.Parts, model.UploadPart{PartNumber: n, URL: url})
+	}
+	return upload, nil
+}
+
+// matchParts kiểm tra danh sách part client gửi: tăng dần, ETag khớp part S3 đã nhận,
+// đủ mọi part theo size khai báo; trả về part để complete và tổng size thật
+func matchParts(parts []model.UploadPart, listed []s3client.UploadedPart, declaredSize int64) ([]s3client.UploadedPart, int64, error) {
+	_, partCount := partLayout(declaredSize)
+	if len(parts) != int(partCount) {
+		return nil, 0, fmt.Errorf("%w: expected %d parts, got %d", ErrInvalidUpload, partCount, len(parts))
+	}
+
+	byNumber := make(map[int32]s3client.UploadedPart, len(listed))
+	for _, part := range listed {
+		byNumber[part.PartNumber] = part
+	}
+
+	completed := make([]s3client.UploadedPart, 0, len(parts))
+	var total int64
+	for i, part := range parts {
+		if part.PartNumber != int32(i+1) {
+			return nil, 0, fmt.Errorf("%w: parts must be numbered 1..%d in order", ErrInvalidUpload, partCount)
+		}
+		s3part, ok := byNumber[part.PartNumber]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: part %d has not been uploaded", ErrInvalidUpload, part.PartNumber)
+		}
+		if strings.Trim(part.ETag, `"`) != strings.Trim(s3part.ETag, `"`) {
+			return nil, 0, fmt.Errorf("%w: etag of part %d does not match", ErrInvalidUpload, part.PartNumber)
+		}
+		completed = append(completed, s3part)
+		total += s3part.Size
+	}
+	return completed, total, nil
+}
@@ -0,0 +1,180 @@
+package postmanager
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"feedservice/internal/infra/s3client"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"io"
+	"testing"
+)
+
+func TestPartLayout(t *testing.T) {
+	tests := []struct {
+		size      int64
+		partSize  int64
+		partCount int32
+	}{
+		{size: 1, partSize: minPartSize, partCount: 1},
+		{size: minPartSize, partSize: minPartSize, partCount: 1},
+		{size: minPartSize + 1, partSize: minPartSize, partCount: 2},
+		{size: 20 << 20, partSize: minPartSize, partCount: 3},
+		// 10000 part 8MB không đủ, part size tăng và làm tròn lên MB
+		{size: maxParts*minPartSize + 1, partSize: minPartSize + 1<<20, partCount: 8889},
+		{size: 1 << 40, partSize: 105 << 20, partCount: 9987},
+	}
+	for _, tt := range tests {
+		partSize, partCount := partLayout(tt.size)
+		if partSize != tt.partSize || partCount != tt.partCount {
+			t.Errorf("partLayout(%d) = %d, %d; want %d, %d", tt.size, partSize, partCount, tt.partSize, tt.partCount)
+		}
+		if partCount > maxParts || int64(partCount)*partSize < tt.size {
+			t.Errorf("partLayout(%d) = %d x %d does not cover size", tt.size, partCount, partSize)
+		}
+	}
+}
+
+// newMultipartMedia mở multipart upload trên fake như CreateMultipartMedia (bỏ phần ghi row)
+func newMultipartMedia(t *testing.T, objects *s3client.FakeObjectStore, size int64) model.Media {
+	t.Helper()
+	objectKey := "u1/m1_video.mp4"
+	uploadID, err := objects.CreateMultipartUpload(context.Background(), objectKey, "video/mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.Media{
+		MediaID:      "m1",
+		UserID:       "u1",
+		MediaType:    "video",
+		Objectkeys3:  sql.NullString{String: objectKey, Valid: true},
+		Status:       model.MediaPending,
+		UploadID:     uploadID,
+		DeclaredSize: size,
+	}
+}
+
+func TestPresignPartsResume(t *testing.T) {
+	objects := s3client.NewFakeObjectStore("media")
+	p := &PostManager{MediaStore: &store.MediaStore{S3client: objects}}
+	media := newMultipartMedia(t, objects, 20<<20)
+
+	upload, err := p.presignParts(media, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.PartCount != 3 || len(upload.Parts) != 3 || len(upload.Uploaded) != 0 {
+		t.Fatalf("new upload = %d parts, %d urls, %d uploaded", upload.PartCount, len(upload.Parts), len(upload.Uploaded))
+	}
+
+	// client upload part 2 rồi mất mạng, resume lấy lại danh sách part
+	etag, err := objects.UploadPart(media.UploadID, 2, []byte("part-2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed, err := objects.ListParts(context.Background(), media.Objectkeys3.String, media.UploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload, err = p.presignParts(media, listed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upload.Uploaded) != 1 || upload.Uploaded[0].PartNumber != 2 || upload.Uploaded[0].ETag != etag {
+		t.Errorf("uploaded = %+v, want part 2 with etag %s", upload.Uploaded, etag)
+	}
+	if len(upload.Parts) != 2 || upload.Parts[0].PartNumber != 1 || upload.Parts[1].PartNumber != 3 {
+		t.Errorf("parts to upload = %+v, want 1 and 3", upload.Parts)
+	}
+	for _, part := range upload.Parts {
+		if part.URL == "" {
+			t.Errorf("part %d has no url", part.PartNumber)
+		}
+	}
+}
+
+func TestMatchPartsAndComplete(t *testing.T) {
+	objects := s3client.NewFakeObjectStore("media")
+	media := newMultipartMedia(t, objects, 20<<20)
+	ctx := context.Background()
+
+	var parts []model.UploadPart
+	for n, data := range []string{"aa", "bbb", "c"} {
+		etag, err := objects.UploadPart(media.UploadID, int32(n+1), []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// client có thể gửi ETag không có dấu nháy
+		if n == 1 {
+			etag = etag[1 : len(etag)-1]
+		}
+		parts = append(parts, model.UploadPart{PartNumber: int32(n + 1), ETag: etag})
+	}
+	listed, err := objects.ListParts(ctx, media.Objectkeys3.String, media.UploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed, total, err := matchParts(parts, listed, media.DeclaredSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 6 || len(completed) != 3 {
+		t.Fatalf("matchParts = %d parts, %d bytes; want 3 parts, 6 bytes", len(completed), total)
+	}
+	if err := objects.CompleteMultipartUpload(ctx, media.Objectkeys3.String, media.UploadID, completed); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := objects.GetObject(ctx, media.Objectkeys3.String)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(body)
+	if !bytes.Equal(data, []byte("aabbbc")) {
+		t.Errorf("object = %q, want %q", data, "aabbbc")
+	}
+	info, err := objects.HeadObject(ctx, media.Objectkeys3.String)
+	if err != nil || info.ContentType != "video/mp4" {
+		t.Errorf("HeadObject = %+v, %v", info, err)
+	}
+	if _, err := objects.ListParts(ctx, media.Objectkeys3.String, media.UploadID); !errors.Is(err, s3client.ErrUploadNotFound) {
+		t.Errorf("upload should be closed after complete, ListParts err = %v", err)
+	}
+}
+
+func TestMatchPartsRejects(t *testing.T) {
+	objects := s3client.NewFakeObjectStore("media")
+	media := newMultipartMedia(t, objects, 20<<20)
+	etags := map[int32]string{}
+	for n := int32(1); n <= 2; n++ {
+		etag, err := objects.UploadPart(media.UploadID, n, []byte("part"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		etags[n] = etag
+	}
+	listed, err := objects.ListParts(context.Background(), media.Objectkeys3.String, media.UploadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		parts []model.UploadPart
+	}{
+		{"too few parts", []model.UploadPart{{PartNumber: 1, ETag: etags[1]}, {PartNumber: 2, ETag: etags[2]}}},
+		{"out of order", []model.UploadPart{{PartNumber: 2, ETag: etags[2]}, {PartNumber: 1, ETag: etags[1]}, {PartNumber: 3, ETag: "x"}}},
+		{"part not uploaded", []model.UploadPart{{PartNumber: 1, ETag: etags[1]}, {PartNumber: 2, ETag: etags[2]}, {PartNumber: 3, ETag: "x"}}},
+		{"etag mismatch", []model.UploadPart{{PartNumber: 1, ETag: etags[2]}, {PartNumber: 2, ETag: etags[2]}, {PartNumber: 3, ETag: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := matchParts(tt.parts, listed, media.DeclaredSize); !errors.Is(err, ErrInvalidUpload) {
+				t.Errorf("matchParts() error = %v, want ErrInvalidUpload", err)
+			}
+		})
+	}
+}
@@ -1,65 +1,175 @@
 package postmanager
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"feedservice/internal/core/mediaverifier"
+	"feedservice/internal/core/userserviceclient"
+	"feedservice/internal/core/visibility"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/infra/s3client"
 	"feedservice/internal/infra/store"
+	"feedservice/internal/infra/trending"
 	"feedservice/internal/model"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrNotPostAuthor - chỉ author mới được sửa/xoá post
+	ErrNotPostAuthor = errors.New("only the author can modify this post")
+	// ErrInvalidContentType - content_type khai báo lúc tạo media không hợp với media_type
+	ErrInvalidContentType = errors.New("content type not allowed for media type")
+	// ErrInvalidCloseFriend - không thể tự thêm mình vào danh sách close friends
+	ErrInvalidCloseFriend = errors.New("cannot add yourself as a close friend")
+)
+
 type PostManager struct {
 	MediaStore        *store.MediaStore
 	PostStore         *store.PostStore
 	PostMediaStore    *store.PostMediaStore
-	newposteventqueue *chan model.NewPostEvent
+	OutboxStore       *store.OutboxStore
+	TagStore          *store.TagStore
+	CloseFriendStore  *store.CloseFriendStore
+	visibility        *visibility.Checker
+	mediaverifier     *mediaverifier.MediaVerifier
+	userserviceclient *userserviceclient.UserService
+	trends            *trending.HashtagTrends
+	redisclient       *redisclient.RedisClient
 }
 
-func NewPostManager(mediaStore *store.MediaStore, newposteventqueue_ *chan model.NewPostEvent) *PostManager {
+func NewPostManager(mediaStore *store.MediaStore, postStore *store.PostStore, postMediaStore *store.PostMediaStore,
+	outboxStore *store.OutboxStore, tagStore *store.TagStore, closeFriendStore *store.CloseFriendStore,
+	visibility_ *visibility.Checker, mediaverifier_ *mediaverifier.MediaVerifier,
+	userserviceclient_ *userserviceclient.UserService, trends_ *trending.HashtagTrends, redisclient_ *redisclient.RedisClient) *PostManager {
 	return &PostManager{
 		MediaStore:        mediaStore,
-		newposteventqueue: newposteventqueue_,
+		PostStore:         postStore,
+		PostMediaStore:    postMediaStore,
+		OutboxStore:       outboxStore,
+		TagStore:          tagStore,
+		CloseFriendStore:  closeFriendStore,
+		visibility:        visibility_,
+		mediaverifier:     mediaverifier_,
+		userserviceclient: userserviceclient_,
+		trends:            trends_,
+		redisclient:       redisclient_,
+	}
+}
+
+// CreatePost ghi post, post_media, hashtag/mention và outbox event trong 1 transaction.
+// Media phải đã được upload thật (MediaVerifier), nếu không trả về lỗi của mediaverifier.
+// Event được OutboxRelay publish sang queue fan-out sau khi commit, fan-out chỉ push tới audience của visibility.
+func (p *PostManager) CreatePost(userID string, content string, mediaIDs []string, visibility string) (string, error) {
+	return p.createPost(userID, content, mediaIDs, visibility, "")
+}
+
+// SharePost tạo bài share của postID với commentary tuỳ chọn (content), fan-out như post thường.
+// Share lại 1 bài share thì trỏ thẳng về post gốc. store.ErrPostNotFound nếu post gốc không còn
+// hoặc userID không được xem; viewer của bài share không được xem post gốc thì thấy tombstone.
+func (p *PostManager) SharePost(userID, postID, content, visibility string) (string, error) {
+	original, err := p.PostStore.GetPostByID(postID)
+	if err != nil {
+		return "", err
 	}
+	if original.SharedPostID != "" {
+		if original, err = p.PostStore.GetPostByID(original.SharedPostID); err != nil {
+			return "", err
+		}
+	}
+	if !p.canView(userID, original) {
+		return "", store.ErrPostNotFound
+	}
+	return p.createPost(userID, content, nil, visibility, original.ID)
 }
 
-func (p *PostManager) CreatePost(userID string, content string, mediaIDs []string) (string, error) {
+func (p *PostManager) createPost(userID, content string, mediaIDs []string, visibility, sharedPostID string) (string, error) {
 	// 1. Validate mediaIDs belong to this user
 	if err := p.MediaStore.ValidateUserMedia(userID, mediaIDs); err != nil {
 		return "", err
 	}
 
-	// 2. Insert post record into posts table
+	// 2. Object đã có trên S3, đúng loại và kích thước
+	if err := p.mediaverifier.Verify(mediaIDs); err != nil {
+		return "", err
+	}
+
+	mentions := p.resolveMentions(ParseMentions(content))
+	notifiable := p.mentionAudience(userID, visibility, mentions)
+
 	postID := uuid.New().String()
-	err := p.PostStore.InsertPost(postID, userID, content)
+	// Postgres lưu tới microsecond: cắt trước để giá trị trong DB (rebuild, cursor, trends.Remove khi sửa/xoá)
+	// giống hệt giá trị dùng cho score fan-out và trending lúc tạo
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+	payload, err := json.Marshal(model.NewPostEvent{
+		PostID:     postID,
+		UserID:     userID,
+		Visibility: visibility,
+		CreatedAt:  createdAt,
+	})
 	if err != nil {
+		return "", fmt.Errorf("failed to marshal post event: %w", err)
+	}
+
+	tx, err := p.PostStore.DBClient.DB.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 3. Insert post record into posts table
+	if err := p.PostStore.InsertPost(tx, postID, userID, content, visibility, sharedPostID, createdAt); err != nil {
 		return "", err
 	}
 
-	// 3. Link media to post in post_media table
+	// 4. Link media to post in post_media table
 	if len(mediaIDs) > 0 {
-		if err := p.PostMediaStore.LinkMediaToPost(postID, mediaIDs); err != nil {
+		locked, err := p.MediaStore.LockUploadedMedia(tx, mediaIDs)
+		if err != nil {
 			return "", err
 		}
-
-		// 4. Update media status to 'uploaded'
-		if err := p.MediaStore.UpdateMediaStatus(mediaIDs, "uploaded"); err != nil {
+		if locked != len(mediaIDs) {
+			return "", fmt.Errorf("%w: media changed while creating post", mediaverifier.ErrMediaRejected)
+		}
+		if err := p.PostMediaStore.LinkMediaToPost(tx, postID, mediaIDs); err != nil {
 			return "", err
 		}
 	}
 
-	if p.newposteventqueue != nil {
-		*p.newposteventqueue <- model.NewPostEvent{
-			PostID: postID,
-			UserID: userID,
-		}
+	// 5. Hashtag, mention (+ outbox PostMentioned cho notification)
+	tags, _, err := p.saveTags(tx, postID, userID, content, createdAt, mentions, notifiable)
+	if err != nil {
+		return "", err
 	}
 
-	// 5. Return new post ID
+	// 6. Outbox event cho fan-out
+	if err := p.OutboxStore.InsertEvent(tx, postID, model.PostCreated, payload); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit post %s: %w", postID, err)
+	}
+	if visibility == model.VisibilityPublic {
+		p.updateTrends(createdAt, tags, nil)
+	}
+
+	// 7. Return new post ID
 	return postID, nil
 }
 
-func (p *PostManager) CreateMedia(userID string, mediatype string, mediafilename string) (model.Media, error) {
+// CreateMedia tạo media pending và presigned URL để client upload (hạn 5 phút).
+// contentType rỗng thì chỉ cấp PUT không ràng buộc (client cũ), kiểm tra dồn về lúc tạo post.
+func (p *PostManager) CreateMedia(userID string, mediatype string, mediafilename string, contentType string) (model.MediaUpload, error) {
+	if contentType != "" && !model.IsAllowedContentType(mediatype, contentType) {
+		return model.MediaUpload{}, fmt.Errorf("%w: %q for %s", ErrInvalidContentType, contentType, mediatype)
+	}
+
 	// Step 1: Generate new media_id
 	mediaID := uuid.New().String()
 
@@ -67,23 +177,233 @@ func (p *PostManager) CreateMedia(userID string, mediatype string, mediafilename
 	objectKey := fmt.Sprintf("%s/%s_%s", userID, mediaID, mediafilename)
 
 	// Step 3: Generate pre-signed upload URL (valid 5 min)
-	uploadURL := p.MediaStore.S3client.GeneratePreSignedURL(objectKey, 5*time.Minute)
+	cond := s3client.UploadConditions{
+		ContentType: contentType,
+		MaxSize:     p.mediaverifier.MaxSize(mediatype),
+	}
+	put, err := p.MediaStore.S3client.PresignPut(objectKey, cond, 5*time.Minute)
+	if err != nil {
+		return model.MediaUpload{}, err
+	}
+	upload := model.MediaUpload{
+		MediaID:       mediaID,
+		UploadURL:     put.URL,
+		UploadHeaders: put.Headers,
+	}
+	if contentType != "" {
+		form, err := p.MediaStore.S3client.PresignPost(objectKey, cond, 5*time.Minute)
+		if err != nil {
+			return model.MediaUpload{}, err
+		}
+		upload.UploadForm = &model.UploadForm{URL: form.URL, Fields: form.Fields}
+	}
 
 	// Step 4: Insert into DB with status=pending
-	media, err := p.MediaStore.CreateMediaRecord(
+	_, err = p.MediaStore.CreateMediaRecord(
 		mediaID,
 		userID,
 		mediatype,
-		"pending",
+		model.MediaPending,
 		objectKey, // use this key to gen GET url S3 media in later.
 	)
 	if err != nil {
-		return model.Media{}, fmt.Errorf("failed to insert media record: %w", err)
+		return model.MediaUpload{}, fmt.Errorf("failed to insert media record: %w", err)
+	}
+
+	// Step 5: Return presigned upload targets
+	return upload, nil
+}
+
+// UpdatePost sửa content (content cũ lưu vào post_revisions) và/hoặc visibility của post, chỉ author được phép.
+// content nil = giữ nguyên, visibility rỗng = giữ nguyên.
+// Hashtag/mention theo content mới, chỉ người được mention lần đầu và được xem post mới nhận notification.
+// Đổi visibility sinh event PostVisibilityChanged để fan-out push lại post theo audience mới.
+func (p *PostManager) UpdatePost(userID string, postID string, content *string, visibility string) error {
+	// đọc trước (không khoá) để biết audience của mention, tránh gọi follow-service trong transaction
+	current, err := p.PostStore.GetPostByID(postID)
+	if err != nil {
+		return err
+	}
+	if current.UserID != userID {
+		return ErrNotPostAuthor
+	}
+	if visibility == "" {
+		visibility = current.Visibility
+	}
+	var mentions []store.Mention
+	var notifiable map[string]bool
+	if content != nil {
+		mentions = p.resolveMentions(ParseMentions(*content))
+		notifiable = p.mentionAudience(userID, visibility, mentions)
+	}
+
+	tx, err := p.PostStore.DBClient.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	post, err := p.lockOwnPost(tx, userID, postID)
+	if err != nil {
+		return err
 	}
 
-	// Step 5: Return Media with presigned URL
-	media.Url.String = uploadURL
-	media.Url.Valid = true
+	// trending chỉ đếm post public: đổi public <-> không public thì cộng/trừ toàn bộ hashtag
+	wasPublic, isPublic := post.Visibility == model.VisibilityPublic, visibility == model.VisibilityPublic
+	var oldTags []string
+	if wasPublic && !isPublic {
+		if oldTags, err = p.TagStore.GetHashtags(tx, postID); err != nil {
+			return err
+		}
+	}
+
+	var added, removed []string
+	if content != nil {
+		if err := p.PostStore.UpdatePostContent(tx, post, *content); err != nil {
+			return err
+		}
+		added, removed, err = p.saveTags(tx, postID, userID, *content, post.CreatedAt, mentions, notifiable)
+		if err != nil {
+			return err
+		}
+	}
+
+	if visibility != post.Visibility {
+		if err := p.PostStore.UpdateVisibility(tx, postID, visibility); err != nil {
+			return err
+		}
+		payload, err := json.Marshal(model.PostVisibilityChangedEvent{
+			PostID:     postID,
+			UserID:     userID,
+			Visibility: visibility,
+			CreatedAt:  post.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal post event: %w", err)
+		}
+		if err := p.OutboxStore.InsertEvent(tx, postID, model.PostVisibilityChanged, payload); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case wasPublic && isPublic:
+	case isPublic:
+		removed = nil
+		if added, err = p.TagStore.GetHashtags(tx, postID); err != nil {
+			return err
+		}
+	case wasPublic:
+		added, removed = nil, oldTags
+	default:
+		added, removed = nil, nil
+	}
 
-	return media, nil
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit update of post %s: %w", postID, err)
+	}
+
+	p.invalidateItem(postID)
+	p.updateTrends(post.CreatedAt, added, removed)
+	return nil
+}
+
+// DeletePost soft delete post, chỉ author được phép.
+// Event PostDeleted đi qua outbox để fan-out worker xoá post khỏi feed ZSET bất đồng bộ.
+func (p *PostManager) DeletePost(userID string, postID string) error {
+	tx, err := p.PostStore.DBClient.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	post, err := p.lockOwnPost(tx, userID, postID)
+	if err != nil {
+		return err
+	}
+	if err := p.PostStore.SoftDeletePost(tx, postID); err != nil {
+		return err
+	}
+	// bỏ hashtag để post không còn trong GET /hashtags/{tag}/posts và trừ khỏi trending
+	_, removed, err := p.TagStore.ReplaceHashtags(tx, postID, nil, post.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(model.PostDeletedEvent{
+		PostID:    postID,
+		UserID:    userID,
+		DeletedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal post event: %w", err)
+	}
+	if err := p.OutboxStore.InsertEvent(tx, postID, model.PostDeleted, payload); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delete of post %s: %w", postID, err)
+	}
+
+	p.invalidateItem(postID)
+	if post.Visibility == model.VisibilityPublic {
+		p.updateTrends(post.CreatedAt, nil, removed)
+	}
+	return nil
+}
+
+// GetRevisions trả về các phiên bản cũ của post chưa bị xoá,
+// store.ErrPostNotFound nếu viewer không được xem post (không để lộ post bị ẩn tồn tại)
+func (p *PostManager) GetRevisions(viewerID, postID string) ([]store.PostRevision, error) {
+	post, err := p.PostStore.GetPostByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.canView(viewerID, post) {
+		return nil, store.ErrPostNotFound
+	}
+	return p.PostStore.GetRevisions(postID)
+}
+
+// ListCloseFriends - danh sách close friends của userID
+func (p *PostManager) ListCloseFriends(userID string) ([]store.CloseFriend, error) {
+	return p.CloseFriendStore.List(userID)
+}
+
+// AddCloseFriend thêm friendID vào danh sách close friends của userID.
+// Post close_friends đã đăng trước đó không được push thêm vào feed của friend (vẫn xem được qua trang profile).
+func (p *PostManager) AddCloseFriend(userID, friendID string) error {
+	if userID == friendID {
+		return ErrInvalidCloseFriend
+	}
+	return p.CloseFriendStore.Add(userID, friendID)
+}
+
+// RemoveCloseFriend bỏ friendID khỏi danh sách, post close_friends còn trong feed của họ bị hydrate ẩn đi
+func (p *PostManager) RemoveCloseFriend(userID, friendID string) error {
+	return p.CloseFriendStore.Remove(userID, friendID)
+}
+
+func (p *PostManager) canView(viewerID string, post *store.Post) bool {
+	target := visibility.Target{AuthorID: post.UserID, Visibility: post.Visibility}
+	return p.visibility.CanView(viewerID, []visibility.Target{target})[0]
+}
+
+// invalidateItem xoá FeedItem đã cache để lần đọc sau hydrate lại
+func (p *PostManager) invalidateItem(postID string) {
+	if err := p.redisclient.GetClient().Del(context.Background(), model.PostItemKey(postID)).Err(); err != nil {
+		log.Printf("[PostManager] failed to invalidate cached item of post %s: %v", postID, err)
+	}
+}
+
+// lockOwnPost khoá row post trong tx và kiểm tra userID là author
+func (p *PostManager) lockOwnPost(tx *sql.Tx, userID string, postID string) (*store.Post, error) {
+	post, err := p.PostStore.LockPost(tx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, ErrNotPostAuthor
+	}
+	return post, nil
 }
@@ -0,0 +1,117 @@
+package postmanager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"feedservice/internal/core/userserviceclient"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// số request song song tối đa tới user-service khi resolve @username
+const mentionLookupConcurrency = 4
+
+// resolveMentions đổi username sang user_id, giữ thứ tự trong content. Username không tồn tại bị bỏ qua;
+// user-service lỗi thì mention đó cũng bị bỏ (chỉ log) để không chặn việc đăng/sửa post.
+func (p *PostManager) resolveMentions(usernames []string) []store.Mention {
+	userIDs := make([]string, len(usernames))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, mentionLookupConcurrency)
+	for i, username := range usernames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, username string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			userID, err := p.userserviceclient.GetUserIDByUsername(username)
+			if err != nil && !errors.Is(err, userserviceclient.ErrUserNotFound) {
+				log.Printf("[PostManager] failed to resolve @%s: %v", username, err)
+			}
+			userIDs[i] = userID
+		}(i, username)
+	}
+	wg.Wait()
+
+	mentions := make([]store.Mention, 0, len(usernames))
+	seen := map[string]bool{}
+	for i, userID := range userIDs {
+		if userID == "" || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		mentions = append(mentions, store.Mention{UserID: userID, Username: usernames[i]})
+	}
+	return mentions
+}
+
+// mentionAudience - user được mention mà visibility cho phép xem post, chỉ họ mới nhận notification.
+// Gọi trước transaction; follow-service/Postgres lỗi thì không báo ai (không để lộ post bị ẩn).
+func (p *PostManager) mentionAudience(authorID, visibility string, mentions []store.Mention) map[string]bool {
+	userIDs := make([]string, len(mentions))
+	for i, m := range mentions {
+		userIDs[i] = m.UserID
+	}
+	allowed, err := p.visibility.Audience(authorID, visibility, userIDs)
+	if err != nil {
+		log.Printf("[PostManager] failed to resolve audience of %s mentions: %v", visibility, err)
+	}
+	notifiable := make(map[string]bool, len(allowed))
+	for _, userID := range allowed {
+		notifiable[userID] = true
+	}
+	return notifiable
+}
+
+// saveTags ghi hashtag và mention của content trong tx tạo/sửa post. User được mention lần đầu
+// (trừ chính author, chỉ người có trong notifiable) sinh outbox event PostMentioned.
+// Trả về hashtag thêm/bớt để cập nhật trending sau commit.
+func (p *PostManager) saveTags(tx *sql.Tx, postID, authorID, content string, createdAt time.Time, mentions []store.Mention, notifiable map[string]bool) (added, removed []string, err error) {
+	added, removed, err = p.TagStore.ReplaceHashtags(tx, postID, ParseHashtags(content), createdAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	mentioned, err := p.TagStore.ReplaceMentions(tx, postID, mentions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notify := make([]string, 0, len(mentioned))
+	for _, userID := range mentioned {
+		if userID != authorID && notifiable[userID] {
+			notify = append(notify, userID)
+		}
+	}
+	if len(notify) == 0 {
+		return added, removed, nil
+	}
+	payload, err := json.Marshal(model.PostMentionedEvent{
+		PostID:           postID,
+		UserID:           authorID,
+		MentionedUserIDs: notify,
+		OccurredAt:       time.Now(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal mention event: %w", err)
+	}
+	if err := p.OutboxStore.InsertEvent(tx, postID, model.PostMentioned, payload); err != nil {
+		return nil, nil, err
+	}
+	return added, removed, nil
+}
+
+// updateTrends chạy sau commit; lỗi Redis chỉ log vì trending là số liệu gần đúng
+func (p *PostManager) updateTrends(createdAt time.Time, added, removed []string) {
+	ctx := context.Background()
+	if err := p.trends.Remove(ctx, createdAt, removed); err != nil {
+		log.Printf("[PostManager] %v", err)
+	}
+	if err := p.trends.Add(ctx, createdAt, added); err != nil {
+		log.Printf("[PostManager] %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package postpurger
+
+import (
+	"feedservice/internal/core/fanoutmanager/workerpocessor"
+	"feedservice/internal/infra/store"
+	"log"
+	"time"
+)
+
+type PurgeConfig struct {
+	Interval  time.Duration
+	Retention time.Duration // post soft delete quá retention thì xoá hẳn
+	BatchSize int
+}
+
+// PostPurger định kỳ hard delete các post đã soft delete quá retention
+// (revision và post_media đi theo ON DELETE CASCADE)
+type PostPurger struct {
+	workerpocessor.BaseWorkerProcessor
+	postStore *store.PostStore
+	cfg       PurgeConfig
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewPostPurger(postStore_ *store.PostStore, cfg PurgeConfig) *PostPurger {
+	s := &PostPurger{
+		postStore: postStore_,
+		cfg:       cfg,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.Init(s)
+	return s
+}
+
+func (s *PostPurger) RunningTask() error {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-ticker.C:
+			s.purge()
+		}
+	}
+}
+
+// Stop chờ batch đang xoá xong
+func (s *PostPurger) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// purge xoá theo batch đến khi hết post quá hạn để không giữ lock lâu
+func (s *PostPurger) purge() {
+	var total int64
+	for {
+		n, err := s.postStore.PurgeDeleted(s.cfg.Retention, s.cfg.BatchSize)
+		if err != nil {
+			log.Printf("[PostPurger] %v", err)
+			break
+		}
+		total += n
+		if n < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("[PostPurger] purged %d deleted posts", total)
+	}
+}
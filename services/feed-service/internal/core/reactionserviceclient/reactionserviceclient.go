@@ -0,0 +1,68 @@
+package reactionserviceclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type ReactionServiceClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewReactionServiceClient(baseURL string) *ReactionServiceClient {
+	return &ReactionServiceClient{
+		BaseURL: baseURL,
+		Client:  &http.Client{},
+	}
+}
+
+type Summary struct {
+	Counts         map[string]int64 `json:"counts"`
+	Total          int64            `json:"total"`
+	ViewerReaction *string          `json:"viewer_reaction,omitempty"`
+}
+
+// GetSummaries lấy counter theo type và reaction của viewer cho 1 trang post
+// (GET /reactions/summary?post_ids=...). viewerID rỗng thì không có ViewerReaction.
+func (c *ReactionServiceClient) GetSummaries(viewerID string, postIDs []string) (map[string]Summary, error) {
+	if len(postIDs) == 0 {
+		return map[string]Summary{}, nil
+	}
+	endpoint := fmt.Sprintf("%s/reactions/summary?post_ids=%s", c.BaseURL, url.QueryEscape(strings.Join(postIDs, ",")))
+
+	// feed vẫn trả được khi reaction-service chậm, chỉ thiếu stats
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ReactionServiceClient] failed to build get request: %w", err)
+	}
+	if viewerID != "" {
+		req.Header.Set("X-User-ID", viewerID)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[ReactionServiceClient] failed to get summaries: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[ReactionServiceClient] unexpected status code: %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Summaries map[string]Summary `json:"summaries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("[ReactionServiceClient] failed to decode response: %w", err)
+	}
+	return res.Summaries, nil
+}
@@ -0,0 +1,381 @@
+package scrollingfeedmanager
+
+import (
+	"context"
+	"encoding/json"
+	"feedservice/internal/core/reactionserviceclient"
+	"feedservice/internal/core/visibility"
+	"feedservice/internal/model"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// itemCacheTTL phải ngắn hơn MinValidity của mediaurl (URL trong item còn hạn ít nhất chừng đó)
+	itemCacheTTL = 5 * time.Minute
+	// số request song song tối đa tới user-service khi lấy author
+	authorLookupConcurrency = 8
+)
+
+// hydrate dựng FeedItem cho danh sách post id, giữ đúng thứ tự đầu vào
+// (thứ tự ZSET). Post không tồn tại/đã xoá hoặc viewer không được xem (visibility) thì bỏ qua.
+//   - item đã render được cache ở post:{id}:item (không phụ thuộc viewer, visibility kiểm tra sau cache)
+//   - cache miss thì đọc post và media bằng 2 query ANY($1), author lấy song song
+//   - bài share được nhúng post gốc (hoặc tombstone) theo viewer, không cache
+//   - reaction (counter + reaction của viewer), số comment và số share lấy 1 lần cho cả trang, không cache
+func (s *SrollingFeedManager) hydrate(viewerID string, postIDs []string) ([]FeedItem, error) {
+	if len(postIDs) == 0 {
+		return []FeedItem{}, nil
+	}
+	ctx := context.Background()
+
+	feed, err := s.visibleItems(ctx, viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.embedShared(ctx, viewerID, feed); err != nil {
+		return nil, err
+	}
+	s.attachStats(viewerID, feed)
+	return feed, nil
+}
+
+// visibleItems lấy item (cache hoặc build) của postIDs mà viewer được xem, giữ thứ tự postIDs
+func (s *SrollingFeedManager) visibleItems(ctx context.Context, viewerID string, postIDs []string) ([]FeedItem, error) {
+	items := s.cachedItems(ctx, postIDs)
+
+	var misses []string
+	for _, postID := range postIDs {
+		if _, ok := items[postID]; !ok {
+			misses = append(misses, postID)
+		}
+	}
+
+	if len(misses) > 0 {
+		built, cacheable, err := s.buildItems(misses)
+		if err != nil {
+			return nil, err
+		}
+		s.cacheItems(ctx, cacheable)
+		for postID, item := range built {
+			items[postID] = item
+		}
+	}
+
+	found := make([]FeedItem, 0, len(postIDs))
+	targets := make([]visibility.Target, 0, len(postIDs))
+	for _, postID := range postIDs {
+		if item, ok := items[postID]; ok {
+			found = append(found, item)
+			targets = append(targets, visibility.Target{AuthorID: item.Author.UserID, Visibility: item.Visibility})
+		}
+	}
+	allowed := s.visibility.CanView(viewerID, targets)
+	feed := make([]FeedItem, 0, len(found))
+	for i, item := range found {
+		if allowed[i] {
+			feed = append(feed, item)
+		}
+	}
+	return feed, nil
+}
+
+// embedShared điền post gốc cho các bài share trong feed; post gốc đã xoá hoặc viewer không được xem thì tombstone
+func (s *SrollingFeedManager) embedShared(ctx context.Context, viewerID string, feed []FeedItem) error {
+	var sharedIDs []string
+	seen := map[string]bool{}
+	for _, item := range feed {
+		if item.SharedPost != nil && !seen[item.SharedPost.PostID] {
+			seen[item.SharedPost.PostID] = true
+			sharedIDs = append(sharedIDs, item.SharedPost.PostID)
+		}
+	}
+	if len(sharedIDs) == 0 {
+		return nil
+	}
+
+	originals, err := s.visibleItems(ctx, viewerID, sharedIDs)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]FeedItem, len(originals))
+	for _, original := range originals {
+		byID[original.PostID] = original
+	}
+
+	for i := range feed {
+		if feed[i].SharedPost == nil {
+			continue
+		}
+		// SharedPost của item có thể trỏ vào item cache, luôn gán struct mới
+		postID := feed[i].SharedPost.PostID
+		original, ok := byID[postID]
+		if !ok {
+			feed[i].SharedPost = &SharedPost{PostID: postID, Unavailable: true}
+			continue
+		}
+		author, createdAt := original.Author, original.CreatedAt
+		feed[i].SharedPost = &SharedPost{
+			PostID:     postID,
+			Author:     &author,
+			Content:    original.Content,
+			Visibility: original.Visibility,
+			CreatedAt:  &createdAt,
+			UpdatedAt:  original.UpdatedAt,
+			Media:      original.Media,
+		}
+	}
+	return nil
+}
+
+// attachStats điền Stats và ViewerReaction từ reaction-service, comment-service và số share từ Postgres
+// (gọi song song), nguồn nào lỗi thì feed vẫn trả về với counter đó = 0
+func (s *SrollingFeedManager) attachStats(viewerID string, feed []FeedItem) {
+	if len(feed) == 0 {
+		return
+	}
+	postIDs := make([]string, len(feed))
+	for i, item := range feed {
+		postIDs[i] = item.PostID
+	}
+
+	var summaries map[string]reactionserviceclient.Summary
+	var commentCounts map[string]int64
+	var shareCounts map[string]int64
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		var err error
+		if summaries, err = s.reactionclient.GetSummaries(viewerID, postIDs); err != nil {
+			log.Printf("[hydrate] failed to fetch reactions: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if commentCounts, err = s.commentclient.GetCounts(postIDs); err != nil {
+			log.Printf("[hydrate] failed to fetch comment counts: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if shareCounts, err = s.PostStore.CountShares(postIDs); err != nil {
+			log.Printf("[hydrate] %v", err)
+		}
+	}()
+	wg.Wait()
+
+	for i := range feed {
+		feed[i].Stats.Comments = commentCounts[feed[i].PostID]
+		feed[i].Stats.Shares = shareCounts[feed[i].PostID]
+		summary, ok := summaries[feed[i].PostID]
+		if !ok {
+			continue
+		}
+		feed[i].Stats.Reactions = summary.Total
+		feed[i].Stats.Likes = summary.Total
+		feed[i].Stats.ReactionCounts = summary.Counts
+		feed[i].ViewerReaction = summary.ViewerReaction
+	}
+}
+
+// cachedItems đọc item đã cache bằng 1 lệnh MGET, lỗi Redis coi như cache miss
+func (s *SrollingFeedManager) cachedItems(ctx context.Context, postIDs []string) map[string]FeedItem {
+	items := make(map[string]FeedItem, len(postIDs))
+
+	keys := make([]string, len(postIDs))
+	for i, postID := range postIDs {
+		keys[i] = model.PostItemKey(postID)
+	}
+	values, err := s.redisclient.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("[hydrate] failed to read item cache: %v", err)
+		return items
+	}
+
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var item FeedItem
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			continue
+		}
+		items[postIDs[i]] = item
+	}
+	return items
+}
+
+func (s *SrollingFeedManager) cacheItems(ctx context.Context, items map[string]FeedItem) {
+	if len(items) == 0 {
+		return
+	}
+	_, err := s.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for postID, item := range items {
+			raw, err := json.Marshal(item)
+			if err != nil {
+				continue
+			}
+			pipe.Set(ctx, model.PostItemKey(postID), raw, itemCacheTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[hydrate] failed to cache %d items: %v", len(items), err)
+	}
+}
+
+// buildItems đọc post, media, author cho các post id chưa có trong cache.
+// cacheable chỉ gồm item lấy được đủ author và rendition, item thiếu không cache để lần sau thử lại.
+func (s *SrollingFeedManager) buildItems(postIDs []string) (items, cacheable map[string]FeedItem, err error) {
+	posts, err := s.PostStore.GetPostsByIDs(postIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	items = make(map[string]FeedItem, len(posts))
+	cacheable = make(map[string]FeedItem, len(posts))
+	if len(posts) == 0 {
+		return items, cacheable, nil
+	}
+
+	foundIDs := make([]string, 0, len(posts))
+	authorIDs := make([]string, 0, len(posts))
+	seenAuthor := map[string]struct{}{}
+	for postID, post := range posts {
+		foundIDs = append(foundIDs, postID)
+		if _, ok := seenAuthor[post.UserID]; !ok {
+			seenAuthor[post.UserID] = struct{}{}
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+
+	medias, err := s.MediaStore.GetMediaByPostIDs(foundIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var mediaIDs []string
+	for _, list := range medias {
+		for _, m := range list {
+			mediaIDs = append(mediaIDs, m.MediaID)
+		}
+	}
+	renditions, err := s.RenditionStore.GetRenditionsByMediaIDs(mediaIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var objectKeys []string
+	for _, list := range renditions {
+		for _, rd := range list {
+			objectKeys = append(objectKeys, rd.Objectkeys3)
+		}
+	}
+	urls := s.MediaStore.URLSigner.URLs(context.Background(), objectKeys)
+	authors := s.fetchAuthors(authorIDs)
+
+	for postID, post := range posts {
+		author, authorOK := authors[post.UserID]
+		if !authorOK {
+			author = UserBrief{UserID: post.UserID}
+		}
+
+		processing := false
+		mediaList := []Media{}
+		for _, m := range medias[postID] {
+			if m.Status == model.MediaFailed {
+				continue
+			}
+			media := renderMedia(m, renditions[m.MediaID], urls)
+			processing = processing || media.Processing
+			mediaList = append(mediaList, media)
+		}
+
+		item := FeedItem{
+			PostID:     post.ID,
+			Author:     author,
+			Content:    post.Content,
+			Visibility: post.Visibility,
+			CreatedAt:  post.CreatedAt,
+			UpdatedAt:  post.UpdatedAt,
+			Media:      mediaList,
+		}
+		if post.SharedPostID != "" {
+			// chỉ giữ tham chiếu, embedShared render post gốc theo viewer lúc đọc
+			item.SharedPost = &SharedPost{PostID: post.SharedPostID}
+		}
+		items[postID] = item
+		// media đang xử lý thì không cache để rendition hiện ra ngay khi worker xong
+		if authorOK && !processing {
+			cacheable[postID] = item
+		}
+	}
+	return items, cacheable, nil
+}
+
+// renderMedia gắn URL (đã ký theo bucket thời gian) cho từng rendition, media chưa có rendition thì Processing
+func renderMedia(m model.Media, renditions []model.Rendition, urls map[string]string) Media {
+	media := Media{
+		MediaID:    m.MediaID,
+		Type:       m.MediaType,
+		Processing: len(renditions) == 0,
+	}
+	if media.Processing {
+		return media
+	}
+	media.Renditions = make(map[string]RenditionURL, len(renditions))
+	for _, rd := range renditions {
+		url, ok := urls[rd.Objectkeys3]
+		if !ok {
+			// ký lỗi (URLSigner đã log): bỏ rendition này, SelectRendition sẽ lấy cỡ khác
+			continue
+		}
+		media.Renditions[rd.Name] = RenditionURL{
+			URL:    url,
+			Width:  rd.Width,
+			Height: rd.Height,
+		}
+	}
+	// ký lỗi hết thì coi như chưa có rendition (item không được cache)
+	media.Processing = len(media.Renditions) == 0
+	return media
+}
+
+// fetchAuthors gọi user-service song song (giới hạn authorLookupConcurrency),
+// user lấy lỗi không có trong kết quả
+func (s *SrollingFeedManager) fetchAuthors(userIDs []string) map[string]UserBrief {
+	authors := make(map[string]UserBrief, len(userIDs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, authorLookupConcurrency)
+
+	for _, userID := range userIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(userID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			profile, err := s.userserviceclient.GetUserProfile(userID)
+			if err != nil {
+				log.Printf("[hydrate] failed to fetch author for user %s: %v", userID, err)
+				return
+			}
+			brief := UserBrief{
+				UserID:   userID,
+				Username: profile.Username,
+				Avatar:   profile.AvatarURL, // will be gen by objects3 and fetch from s3
+			}
+
+			mu.Lock()
+			authors[userID] = brief
+			mu.Unlock()
+		}(userID)
+	}
+	wg.Wait()
+	return authors
+}
@@ -0,0 +1,73 @@
+package scrollingfeedmanager
+
+import (
+	"container/heap"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// mergeCursor trỏ tới phần tử kế tiếp của 1 nguồn (đã sort score giảm dần)
+type mergeCursor struct {
+	items []redis.Z
+	pos   int
+}
+
+type mergeHeap []*mergeCursor
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	a, b := h[i].items[h[i].pos], h[j].items[h[j].pos]
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	// cùng score: giữ thứ tự giống ZREVRANGE (member lớn hơn đứng trước)
+	return a.Member.(string) > b.Member.(string)
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x any)   { *h = append(*h, x.(*mergeCursor)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// mergeByScore k-way merge các nguồn đã sort score giảm dần, bỏ post trùng
+// (1 post có thể vừa được push vào feed vừa nằm trong posts của celebrity),
+// bỏ qua skip phần tử đầu và trả về tối đa limit phần tử.
+func mergeByScore(sources [][]redis.Z, skip, limit int) []redis.Z {
+	h := &mergeHeap{}
+	for _, src := range sources {
+		if len(src) > 0 {
+			*h = append(*h, &mergeCursor{items: src})
+		}
+	}
+	heap.Init(h)
+
+	seen := map[string]struct{}{}
+	result := make([]redis.Z, 0, limit)
+	for h.Len() > 0 && len(result) < limit {
+		c := (*h)[0]
+		z := c.items[c.pos]
+		c.pos++
+		if c.pos < len(c.items) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+
+		member := z.Member.(string)
+		if _, dup := seen[member]; dup {
+			continue
+		}
+		seen[member] = struct{}{}
+
+		if skip > 0 {
+			skip--
+			continue
+		}
+		result = append(result, z)
+	}
+	return result
+}
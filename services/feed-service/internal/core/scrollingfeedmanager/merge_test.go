@@ -0,0 +1,156 @@
+package scrollingfeedmanager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// zs tạo nguồn từ cặp member, score
+func zs(pairs ...any) []redis.Z {
+	out := make([]redis.Z, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		out = append(out, redis.Z{Member: pairs[i].(string), Score: float64(pairs[i+1].(int))})
+	}
+	return out
+}
+
+func members(items []redis.Z) []string {
+	out := make([]string, len(items))
+	for i, z := range items {
+		out[i] = z.Member.(string)
+	}
+	return out
+}
+
+func TestMergeByScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources [][]redis.Z
+		skip    int
+		limit   int
+		want    []string
+	}{
+		{
+			name:  "no sources",
+			limit: 10,
+			want:  []string{},
+		},
+		{
+			name:    "empty sources",
+			sources: [][]redis.Z{nil, {}},
+			limit:   10,
+			want:    []string{},
+		},
+		{
+			name:    "single source keeps order",
+			sources: [][]redis.Z{zs("c", 30, "b", 20, "a", 10)},
+			limit:   10,
+			want:    []string{"c", "b", "a"},
+		},
+		{
+			name: "interleaves by score",
+			sources: [][]redis.Z{
+				zs("f1", 90, "f2", 50, "f3", 10),
+				zs("c1", 80, "c2", 60),
+				zs("d1", 70),
+			},
+			limit: 10,
+			want:  []string{"f1", "c1", "d1", "c2", "f2", "f3"},
+		},
+		{
+			name: "same score orders by member descending",
+			sources: [][]redis.Z{
+				zs("b", 50, "x", 10),
+				zs("c", 50, "a", 50),
+			},
+			limit: 10,
+			want:  []string{"c", "b", "a", "x"},
+		},
+		{
+			name: "post in feed and celebrity posts appears once",
+			sources: [][]redis.Z{
+				zs("p3", 30, "p2", 20, "p1", 10),
+				zs("p2", 20, "p0", 5),
+			},
+			limit: 10,
+			want:  []string{"p3", "p2", "p1", "p0"},
+		},
+		{
+			name:    "limit",
+			sources: [][]redis.Z{zs("c", 30, "a", 10), zs("b", 20)},
+			limit:   2,
+			want:    []string{"c", "b"},
+		},
+		{
+			name:    "skip",
+			sources: [][]redis.Z{zs("c", 30, "a", 10), zs("b", 20)},
+			skip:    1,
+			limit:   10,
+			want:    []string{"b", "a"},
+		},
+		{
+			name: "skip counts unique posts only",
+			sources: [][]redis.Z{
+				zs("p3", 30, "p2", 20, "p1", 10),
+				zs("p3", 30, "p2", 20),
+			},
+			skip:  2,
+			limit: 10,
+			want:  []string{"p1"},
+		},
+		{
+			name:    "skip past the end",
+			sources: [][]redis.Z{zs("b", 20, "a", 10)},
+			skip:    5,
+			limit:   10,
+			want:    []string{},
+		},
+		{
+			name:    "zero limit",
+			sources: [][]redis.Z{zs("b", 20, "a", 10)},
+			limit:   0,
+			want:    []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeByScore(tt.sources, tt.skip, tt.limit)
+			if !reflect.DeepEqual(members(got), tt.want) {
+				t.Errorf("mergeByScore() = %v, want %v", members(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeByScorePages(t *testing.T) {
+	sources := [][]redis.Z{
+		zs("p9", 90, "p7", 70, "p5", 50, "p3", 30, "p1", 10),
+		zs("p8", 80, "p7", 70, "p4", 40, "p2", 20),
+		zs("p6", 60, "p5", 50),
+	}
+	all := members(mergeByScore(sources, 0, 100))
+	want := []string{"p9", "p8", "p7", "p6", "p5", "p4", "p3", "p2", "p1"}
+	if !reflect.DeepEqual(all, want) {
+		t.Fatalf("mergeByScore() = %v, want %v", all, want)
+	}
+
+	// đọc từng trang bằng skip phải ra đúng chuỗi như đọc 1 lần
+	var paged []string
+	for skip := 0; ; skip += 4 {
+		page := members(mergeByScore(sources, skip, 4))
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+	}
+	if !reflect.DeepEqual(paged, want) {
+		t.Errorf("paged merge = %v, want %v", paged, want)
+	}
+
+	// nguồn không bị sửa
+	if sources[0][0].Member != "p9" || len(sources[1]) != 4 {
+		t.Errorf("sources were modified: %v", sources)
+	}
+}
@@ -0,0 +1,83 @@
+package scrollingfeedmanager
+
+import "feedservice/internal/model"
+
+// imageRenditions theo thứ tự từ nhỏ tới lớn
+var imageRenditions = []string{model.RenditionThumb, model.RenditionMedium, model.RenditionFull}
+
+// IsImageRendition - name hợp lệ cho tham số ?rendition=
+func IsImageRendition(name string) bool {
+	for _, r := range imageRenditions {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectRendition điền URL/kích thước của rendition name cho từng media rồi bỏ map Renditions.
+// Ảnh chưa có rendition đó thì lấy bản lớn hơn gần nhất, không có thì bản nhỏ hơn;
+// video luôn dùng full + poster, áp dụng cả media của post gốc trong bài share.
+// Trả về slice mới, items gốc (có thể từ cache) không bị sửa.
+func SelectRendition(items []FeedItem, name string) []FeedItem {
+	out := make([]FeedItem, len(items))
+	for i, item := range items {
+		item.Media = selectMedias(item.Media, name)
+		if item.SharedPost != nil {
+			shared := *item.SharedPost
+			shared.Media = selectMedias(shared.Media, name)
+			item.SharedPost = &shared
+		}
+		out[i] = item
+	}
+	return out
+}
+
+func selectMedias(medias []Media, name string) []Media {
+	out := make([]Media, len(medias))
+	for i, m := range medias {
+		out[i] = selectMedia(m, name)
+	}
+	return out
+}
+
+func selectMedia(m Media, name string) Media {
+	renditions := m.Renditions
+	m.Renditions = nil
+	if len(renditions) == 0 {
+		return m
+	}
+
+	if m.Type == "video" {
+		if poster, ok := renditions[model.RenditionPoster]; ok {
+			m.PosterURL = poster.URL
+		}
+		if full, ok := renditions[model.RenditionFull]; ok {
+			m.URL, m.Width, m.Height = full.URL, full.Width, full.Height
+		}
+		return m
+	}
+
+	for _, candidate := range renditionOrder(name) {
+		if rd, ok := renditions[candidate]; ok {
+			m.URL, m.Width, m.Height = rd.URL, rd.Width, rd.Height
+			break
+		}
+	}
+	return m
+}
+
+// renditionOrder: name trước, rồi các bản lớn hơn, cuối cùng các bản nhỏ hơn (gần nhất trước)
+func renditionOrder(name string) []string {
+	idx := 0
+	for i, r := range imageRenditions {
+		if r == name {
+			idx = i
+		}
+	}
+	order := append([]string{}, imageRenditions[idx:]...)
+	for i := idx - 1; i >= 0; i-- {
+		order = append(order, imageRenditions[i])
+	}
+	return order
+}
@@ -2,45 +2,95 @@ package scrollingfeedmanager
 
 import (
 	"context"
+	"feedservice/internal/core/commentserviceclient"
+	"feedservice/internal/core/followserviceclient"
+	"feedservice/internal/core/reactionserviceclient"
 	"feedservice/internal/core/userserviceclient"
+	"feedservice/internal/core/visibility"
+	"feedservice/internal/infra/feedcache"
 	"feedservice/internal/infra/redisclient"
 	"feedservice/internal/infra/store"
+	"feedservice/internal/infra/trending"
+	"feedservice/internal/model"
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 type SrollingFeedManager struct {
-	MediaStore        *store.MediaStore
-	PostStore         *store.PostStore
-	PostMediaStore    *store.PostMediaStore
-	userserviceclient *userserviceclient.UserService
-	redisclient       *redisclient.RedisClient
+	MediaStore          *store.MediaStore
+	RenditionStore      *store.RenditionStore
+	PostStore           *store.PostStore
+	PostMediaStore      *store.PostMediaStore
+	TagStore            *store.TagStore
+	visibility          *visibility.Checker
+	trends              *trending.HashtagTrends
+	userserviceclient   *userserviceclient.UserService
+	followserviceclient *followserviceclient.FollowServiceClient
+	reactionclient      *reactionserviceclient.ReactionServiceClient
+	commentclient       *commentserviceclient.CommentServiceClient
+	redisclient         *redisclient.RedisClient
+	feedcache           *feedcache.FeedCache
 }
 
 func NewSrollingFeedManager(
 	MediaStore_ *store.MediaStore,
+	RenditionStore_ *store.RenditionStore,
 	PostStore_ *store.PostStore,
 	PostMediaStore_ *store.PostMediaStore,
+	TagStore_ *store.TagStore,
+	visibility_ *visibility.Checker,
+	trends_ *trending.HashtagTrends,
 	userserviceclient_ *userserviceclient.UserService,
-	redisclient_ *redisclient.RedisClient) *SrollingFeedManager {
+	followserviceclient_ *followserviceclient.FollowServiceClient,
+	reactionclient_ *reactionserviceclient.ReactionServiceClient,
+	commentclient_ *commentserviceclient.CommentServiceClient,
+	redisclient_ *redisclient.RedisClient,
+	feedcache_ *feedcache.FeedCache) *SrollingFeedManager {
 	return &SrollingFeedManager{
-		MediaStore:        MediaStore_,
-		PostStore:         PostStore_,
-		PostMediaStore:    PostMediaStore_,
-		userserviceclient: userserviceclient_,
-		redisclient:       redisclient_,
+		MediaStore:          MediaStore_,
+		RenditionStore:      RenditionStore_,
+		PostStore:           PostStore_,
+		PostMediaStore:      PostMediaStore_,
+		TagStore:            TagStore_,
+		visibility:          visibility_,
+		trends:              trends_,
+		userserviceclient:   userserviceclient_,
+		followserviceclient: followserviceclient_,
+		reactionclient:      reactionclient_,
+		commentclient:       commentclient_,
+		redisclient:         redisclient_,
+		feedcache:           feedcache_,
 	}
 }
 
 type FeedItem struct {
-	PostID         string    `json:"post_id"`
-	Author         UserBrief `json:"author"`
-	Content        string    `json:"content"`
-	CreatedAt      time.Time `json:"created_at"`
-	Media          []Media   `json:"media"`
-	Stats          PostStats `json:"stats"`
-	ViewerReaction *string   `json:"viewer_reaction,omitempty"`
+	PostID     string     `json:"post_id"`
+	Author     UserBrief  `json:"author"`
+	Content    string     `json:"content"`
+	Visibility string     `json:"visibility"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	Media      []Media    `json:"media"`
+	// SharedPost - post gốc nếu đây là bài share (content là commentary của người share)
+	SharedPost     *SharedPost `json:"shared_post,omitempty"`
+	Stats          PostStats   `json:"stats"`
+	ViewerReaction *string     `json:"viewer_reaction,omitempty"`
+}
+
+// SharedPost - post gốc nhúng trong bài share, render theo viewer lúc đọc (item cache chỉ giữ PostID).
+// Post gốc đã xoá hoặc viewer không được xem thì là tombstone: chỉ có PostID và Unavailable.
+type SharedPost struct {
+	PostID      string     `json:"post_id"`
+	Unavailable bool       `json:"unavailable,omitempty"`
+	Author      *UserBrief `json:"author,omitempty"`
+	Content     string     `json:"content,omitempty"`
+	Visibility  string     `json:"visibility,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	Media       []Media    `json:"media,omitempty"`
 }
 
 type UserBrief struct {
@@ -50,97 +100,309 @@ type UserBrief struct {
 }
 
 type Media struct {
-	MediaID string `json:"media_id"`
-	Type    string `json:"type"`
-	URL     string `json:"url"`
+	MediaID   string `json:"media_id"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	PosterURL string `json:"poster_url,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	// Processing: media worker chưa tạo xong rendition, URL rỗng
+	Processing bool `json:"processing,omitempty"`
+	// Renditions chỉ dùng trong item cache, SelectRendition chọn ra URL rồi bỏ đi
+	Renditions map[string]RenditionURL `json:"renditions,omitempty"`
+}
+
+type RenditionURL struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
 }
 
 type PostStats struct {
-	Likes    int `json:"likes"`
-	Comments int `json:"comments"`
+	// Likes - client cũ (trước khi có reaction) đọc field này, giữ lại với giá trị = Reactions
+	Likes          int64            `json:"likes"`
+	Reactions      int64            `json:"reactions"`
+	ReactionCounts map[string]int64 `json:"reaction_counts,omitempty"`
+	Comments       int64            `json:"comments"`
+	Shares         int64            `json:"shares"`
 }
 
 type FeedResponse struct {
-	Feed       []FeedItem `json:"feed"`
-	NextOffset int64      `json:"next_offset"`
+	Feed []FeedItem `json:"feed"`
+	// NextCursor truyền vào before để lấy trang kế tiếp, rỗng khi đã hết feed
+	NextCursor string `json:"next_cursor,omitempty"`
+	// NewestCursor là vị trí post đầu trang, client dùng để hỏi số post mới (GET /feeds/new?since=)
+	NewestCursor string `json:"newest_cursor,omitempty"`
+}
+
+type PostsResponse struct {
+	Posts      []FeedItem `json:"posts"`
+	NextCursor string     `json:"next_cursor,omitempty"`
 }
 
-func (s *SrollingFeedManager) ScrollingFeed(userID string, offset, limit int64) (FeedResponse, error) {
+// ScrollingFeed merge feed đã được push (user:{id}:feed) với posts gần nhất của
+// các celebrity mà user follow (fan-out on read), sắp xếp theo score giảm dần.
+// before là cursor opaque của trang trước (rỗng = trang đầu).
+func (s *SrollingFeedManager) ScrollingFeed(userID string, before string, limit int64) (FeedResponse, error) {
 	ctx := context.Background()
-	feedKey := fmt.Sprintf("user:%s:feed", userID)
 
-	// Get posts (most recent first)
-	postIDs, err := s.redisclient.GetClient().
-		ZRevRange(ctx, feedKey, offset, offset+limit-1).
-		Result()
+	var cursor *feedcache.Cursor
+	if before != "" {
+		c, err := feedcache.DecodeCursor(before)
+		if err != nil {
+			return FeedResponse{}, err
+		}
+		cursor = &c
+	}
+
+	// Đọc feed thì gia hạn TTL, feed của user inactive sẽ tự expire
+	exists, err := s.feedcache.Touch(ctx, userID)
 	if err != nil {
-		return FeedResponse{}, fmt.Errorf("[ScrollingFeed] failed to fetch feed for user %s: %w", userID, err)
+		return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
 	}
 
-	feed := make([]FeedItem, 0, len(postIDs))
+	followees, celebrities, followErr := s.splitFollowees(ctx, userID)
+	if followErr != nil {
+		if !exists {
+			return FeedResponse{}, fmt.Errorf("[ScrollingFeed] cannot rebuild feed of %s: %w", userID, followErr)
+		}
+		// feed vẫn trả được phần đã push
+		log.Printf("[ScrollingFeed] failed to resolve followees of %s: %v", userID, followErr)
+	}
 
-	for _, postID := range postIDs {
-		// 1. Fetch post content
-		post, err := s.PostStore.GetPostByID(postID)
+	if !exists {
+		// feed expire hoặc chưa từng build (user quay lại sau thời gian dài)
+		posts, err := s.recentPosts(followees, store.Audience{ViewerID: userID, Follower: true}, nil, s.feedcache.MaxLen())
 		if err != nil {
-			log.Printf("[ScrollingFeed] failed to fetch post %s: %v", postID, err)
-			continue
+			return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
 		}
-		if post == nil {
-			continue
+		if err := s.feedcache.Rebuild(ctx, userID, posts); err != nil {
+			return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
 		}
+		log.Printf("[ScrollingFeed] rebuilt feed of %s with %d posts", userID, len(posts))
+	}
 
-		// 2. Fetch media linked to this post
-		mediaIDs, err := s.PostMediaStore.GetMediaByPostID(postID)
+	pushed, err := s.feedcache.RangeAfter(ctx, feedcache.FeedKey(userID), cursor, limit)
+	if err != nil {
+		return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
+	}
+
+	// Feed chỉ giữ MaxLen post gần nhất: đọc quá phần đã trim thì lấy tiếp từ Postgres
+	if int64(len(pushed)) < limit && followErr == nil {
+		card, err := s.feedcache.Card(ctx, userID)
 		if err != nil {
-			log.Printf("[ScrollingFeed] failed to fetch media for post %s: %v", postID, err)
-			continue
+			return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
 		}
-		mediaList := []Media{}
-		for _, mid := range mediaIDs {
-			m, err := s.MediaStore.GetMediaByID(mid)
-			if err == nil {
-				mediaList = append(mediaList, Media{
-					MediaID: m.MediaID,
-					Type:    m.MediaType,
-					URL:     m.URL,
-				})
+		if card >= s.feedcache.MaxLen() {
+			pushed, err = s.recentPosts(followees, store.Audience{ViewerID: userID, Follower: true}, cursor, limit)
+			if err != nil {
+				return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
 			}
 		}
+	}
+	sources := [][]redis.Z{pushed}
 
-		// 3. Fetch author profile
-		author, err := s.userserviceclient.GetUserProfile(post.UserID)
+	for _, celebrityID := range celebrities {
+		posts, err := s.feedcache.RangeAfter(ctx, fmt.Sprintf("user:%s:posts", celebrityID), cursor, limit)
 		if err != nil {
-			log.Printf("[ScrollingFeed] failed to fetch author for user %s: %v", post.UserID, err)
+			log.Printf("[ScrollingFeed] failed to fetch posts of celebrity %s: %v", celebrityID, err)
+			continue
 		}
+		sources = append(sources, posts)
+	}
 
-		authorBrief := UserBrief{
-			UserID:   post.UserID,
-			Username: author.Username,
-			Avatar:   author.AvatarURL, // will be gen by objects3 and fetch from s3
+	merged := mergeByScore(sources, 0, int(limit))
+	postIDs := make([]string, 0, len(merged))
+	for _, z := range merged {
+		postIDs = append(postIDs, z.Member.(string))
+	}
+
+	var resp FeedResponse
+	if len(merged) > 0 {
+		resp.NewestCursor = feedcache.CursorOf(merged[0]).Encode()
+	}
+	if int64(len(merged)) == limit {
+		resp.NextCursor = feedcache.CursorOf(merged[len(merged)-1]).Encode()
+	}
+
+	resp.Feed, err = s.hydrate(userID, postIDs)
+	if err != nil {
+		return FeedResponse{}, fmt.Errorf("[ScrollingFeed] %w", err)
+	}
+	return resp, nil
+}
+
+// GetPost trả về 1 post đã hydrate, store.ErrPostNotFound nếu không có, đã xoá hoặc viewer không được xem.
+// viewerID rỗng (không đăng nhập) chỉ thấy post public và không có ViewerReaction.
+func (s *SrollingFeedManager) GetPost(viewerID, postID string) (FeedItem, error) {
+	items, err := s.hydrate(viewerID, []string{postID})
+	if err != nil {
+		return FeedItem{}, err
+	}
+	if len(items) == 0 {
+		return FeedItem{}, store.ErrPostNotFound
+	}
+	return items[0], nil
+}
+
+// GetUserPosts trang post của 1 user đọc thẳng từ Postgres, cùng format cursor với feed
+func (s *SrollingFeedManager) GetUserPosts(viewerID, userID string, before string, limit int64) (PostsResponse, error) {
+	var cursor *feedcache.Cursor
+	if before != "" {
+		c, err := feedcache.DecodeCursor(before)
+		if err != nil {
+			return PostsResponse{}, err
 		}
+		cursor = &c
+	}
 
-		// 4. Fetch stats (stub now, real impl later)
-		stats := PostStats{
-			Likes:    0, // TODO: call LikeService
-			Comments: 0, // TODO: call CommentService
+	audience := store.Audience{ViewerID: viewerID}
+	if viewerID != "" && viewerID != userID {
+		followees, err := s.followserviceclient.GetFollowees(viewerID)
+		if err != nil {
+			// không biết viewer có follow không thì chỉ hiện post public
+			log.Printf("[GetUserPosts] failed to fetch followees of %s: %v", viewerID, err)
 		}
+		for _, id := range followees {
+			audience.Follower = audience.Follower || id == userID
+		}
+	}
+
+	posts, err := s.recentPosts([]string{userID}, audience, cursor, limit)
+	if err != nil {
+		return PostsResponse{}, fmt.Errorf("[GetUserPosts] %w", err)
+	}
 
-		feedItem := FeedItem{
-			PostID:    post.ID,
-			Author:    authorBrief,
-			Content:   post.Content,
-			CreatedAt: post.CreatedAt,
-			Media:     mediaList,
-			Stats:     stats,
-			// ViewerReaction: TODO: fetch from LikeService if current user reacted
+	var resp PostsResponse
+	if int64(len(posts)) == limit {
+		resp.NextCursor = feedcache.CursorOf(posts[len(posts)-1]).Encode()
+	}
+	postIDs := make([]string, 0, len(posts))
+	for _, z := range posts {
+		postIDs = append(postIDs, z.Member.(string))
+	}
+	resp.Posts, err = s.hydrate(viewerID, postIDs)
+	if err != nil {
+		return PostsResponse{}, fmt.Errorf("[GetUserPosts] %w", err)
+	}
+	return resp, nil
+}
+
+// GetHashtagPosts trang post có hashtag tag (đã normalize), mới nhất trước, cùng format cursor với feed.
+// Post followers-only chỉ còn trong trang khi hydrate xác nhận viewer follow author.
+func (s *SrollingFeedManager) GetHashtagPosts(viewerID, tag string, before string, limit int64) (PostsResponse, error) {
+	// trang hashtag gồm nhiều author nên để hydrate kiểm tra follow từng post
+	audience := store.Audience{ViewerID: viewerID, Follower: viewerID != ""}
+	var posts []store.Post
+	if before == "" {
+		var err error
+		if posts, err = s.TagStore.GetRecentPostsByHashtag(tag, audience, limit); err != nil {
+			return PostsResponse{}, fmt.Errorf("[GetHashtagPosts] %w", err)
 		}
+	} else {
+		cursor, err := feedcache.DecodeCursor(before)
+		if err != nil {
+			return PostsResponse{}, err
+		}
+		if posts, err = s.TagStore.GetPostsByHashtagBefore(tag, audience, int64(cursor.Score), cursor.PostID, limit); err != nil {
+			return PostsResponse{}, fmt.Errorf("[GetHashtagPosts] %w", err)
+		}
+	}
+
+	var resp PostsResponse
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+	if int64(len(posts)) == limit {
+		last := posts[len(posts)-1]
+		resp.NextCursor = feedcache.Cursor{Score: float64(last.CreatedAt.Unix()), PostID: last.ID}.Encode()
+	}
+	var err error
+	resp.Posts, err = s.hydrate(viewerID, postIDs)
+	if err != nil {
+		return PostsResponse{}, fmt.Errorf("[GetHashtagPosts] %w", err)
+	}
+	return resp, nil
+}
+
+// TrendingHashtags - limit hashtag có nhiều post mới nhất trong window (trending.ErrInvalidWindow nếu window không hỗ trợ)
+func (s *SrollingFeedManager) TrendingHashtags(window time.Duration, limit int64) ([]trending.HashtagCount, error) {
+	return s.trends.Top(context.Background(), window, limit)
+}
+
+// NewPostsCount đếm số post mới hơn since (NewestCursor của lần load trước)
+func (s *SrollingFeedManager) NewPostsCount(userID string, since string) (int64, error) {
+	cursor, err := feedcache.DecodeCursor(since)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	keys := []string{feedcache.FeedKey(userID)}
+	_, celebrities, err := s.splitFollowees(ctx, userID)
+	if err != nil {
+		log.Printf("[NewPostsCount] failed to resolve followees of %s: %v", userID, err)
+	}
+	for _, celebrityID := range celebrities {
+		keys = append(keys, fmt.Sprintf("user:%s:posts", celebrityID))
+	}
+
+	count, err := s.feedcache.CountNewer(ctx, keys, cursor)
+	if err != nil {
+		return 0, fmt.Errorf("[NewPostsCount] %w", err)
+	}
+	return count, nil
+}
+
+// splitFollowees chia followee của user thành author được push vào feed
+// và celebrity ở chế độ fan-out on read
+func (s *SrollingFeedManager) splitFollowees(ctx context.Context, userID string) (pushed []string, celebrities []string, err error) {
+	followees, err := s.followserviceclient.GetFollowees(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(followees) == 0 {
+		return nil, nil, nil
+	}
 
-		feed = append(feed, feedItem)
+	members := make([]interface{}, len(followees))
+	for i, id := range followees {
+		members[i] = id
+	}
+	flags, err := s.redisclient.GetClient().SMIsMember(ctx, model.CelebritiesKey, members...).Result()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return FeedResponse{
-		Feed:       feed,
-		NextOffset: offset + int64(len(postIDs)),
-	}, nil
+	for i, isCelebrity := range flags {
+		if isCelebrity {
+			celebrities = append(celebrities, followees[i])
+		} else {
+			pushed = append(pushed, followees[i])
+		}
+	}
+	return pushed, celebrities, nil
+}
+
+// recentPosts đọc limit post mới nhất (sau cursor nếu có) của authors mà audience được xem từ Postgres, score giống lúc fan-out
+func (s *SrollingFeedManager) recentPosts(authorIDs []string, audience store.Audience, cursor *feedcache.Cursor, limit int64) ([]redis.Z, error) {
+	var posts []store.Post
+	var err error
+	if cursor == nil {
+		posts, err = s.PostStore.GetRecentPostsByUsers(authorIDs, audience, limit)
+	} else {
+		posts, err = s.PostStore.GetPostsByUsersBefore(authorIDs, audience, int64(cursor.Score), cursor.PostID, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make([]redis.Z, 0, len(posts))
+	for _, post := range posts {
+		result = append(result, redis.Z{
+			Score:  float64(post.CreatedAt.Unix()),
+			Member: post.ID,
+		})
+	}
+	return result, nil
 }
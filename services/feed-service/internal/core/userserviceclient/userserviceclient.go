@@ -2,10 +2,15 @@ package userserviceclient
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
+// ErrUserNotFound - username không tồn tại (hoặc user đã xoá)
+var ErrUserNotFound = errors.New("user not found")
+
 type UserService struct {
 	BaseURL string
 	Client  *http.Client
@@ -50,3 +55,29 @@ func (u *UserService) GetUserProfile(userID string) (UserProfileResponse, error)
 
 	return res, nil
 }
+
+// GetUserIDByUsername resolve @username trong post sang user_id
+func (u *UserService) GetUserIDByUsername(username string) (string, error) {
+	endpoint := fmt.Sprintf("%s/users/by-username/%s", u.BaseURL, url.PathEscape(username))
+
+	resp, err := u.Client.Get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("[UserServiceClient] failed to get user by username: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("[UserServiceClient] unexpected status code: %d", resp.StatusCode)
+	}
+
+	var res struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("[UserServiceClient] failed to decode response: %w", err)
+	}
+	return res.UserID, nil
+}
@@ -0,0 +1,123 @@
+package visibility
+
+import (
+	"feedservice/internal/core/followserviceclient"
+	"feedservice/internal/infra/store"
+	"feedservice/internal/model"
+	"log"
+)
+
+// Checker kiểm tra post visibility lúc đọc (GetPost, hydrate, revisions) và lọc người nhận theo audience của post
+type Checker struct {
+	followserviceclient *followserviceclient.FollowServiceClient
+	closeFriendStore    *store.CloseFriendStore
+}
+
+func NewChecker(followserviceclient_ *followserviceclient.FollowServiceClient, closeFriendStore_ *store.CloseFriendStore) *Checker {
+	return &Checker{
+		followserviceclient: followserviceclient_,
+		closeFriendStore:    closeFriendStore_,
+	}
+}
+
+// Target - post cần kiểm tra, Visibility rỗng (item cache/event cũ) coi như public
+type Target struct {
+	AuthorID   string
+	Visibility string
+}
+
+// CanView trả về allowed[i] = viewerID được xem targets[i].
+// Follow-service/Postgres lỗi thì ẩn các post cần đến kết quả đó, không bao giờ để lộ post.
+func (c *Checker) CanView(viewerID string, targets []Target) []bool {
+	allowed := make([]bool, len(targets))
+	var needFollow, needList bool
+	var listAuthors []string
+	for i, t := range targets {
+		switch {
+		case t.Visibility == "" || t.Visibility == model.VisibilityPublic:
+			allowed[i] = true
+		case viewerID == "":
+		case t.AuthorID == viewerID:
+			allowed[i] = true
+		case t.Visibility == model.VisibilityFollowers:
+			needFollow = true
+		case t.Visibility == model.VisibilityCloseFriends:
+			needList = true
+			listAuthors = append(listAuthors, t.AuthorID)
+		}
+	}
+
+	following := map[string]bool{}
+	if needFollow {
+		followees, err := c.followserviceclient.GetFollowees(viewerID)
+		if err != nil {
+			log.Printf("[visibility] failed to fetch followees of %s: %v", viewerID, err)
+		}
+		for _, id := range followees {
+			following[id] = true
+		}
+	}
+	listed := map[string]bool{}
+	if needList {
+		var err error
+		if listed, err = c.closeFriendStore.ListedBy(viewerID, listAuthors); err != nil {
+			log.Printf("[visibility] %v", err)
+			listed = map[string]bool{}
+		}
+	}
+
+	for i, t := range targets {
+		if allowed[i] || viewerID == "" {
+			continue
+		}
+		switch t.Visibility {
+		case model.VisibilityFollowers:
+			allowed[i] = following[t.AuthorID]
+		case model.VisibilityCloseFriends:
+			allowed[i] = listed[t.AuthorID]
+		}
+	}
+	return allowed
+}
+
+// Audience lọc userIDs còn lại những user được xem post của authorID với visibility (vd người được @mention)
+func (c *Checker) Audience(authorID, visibility string, userIDs []string) ([]string, error) {
+	var members []string
+	switch visibility {
+	case "", model.VisibilityPublic:
+		return userIDs, nil
+	case model.VisibilityOnlyMe:
+		members = []string{authorID}
+	case model.VisibilityFollowers:
+		followers, err := c.followserviceclient.GetFollowers(authorID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(followers, authorID)
+	case model.VisibilityCloseFriends:
+		friends, err := c.closeFriendStore.List(authorID)
+		if err != nil {
+			return nil, err
+		}
+		members = []string{authorID}
+		for _, f := range friends {
+			members = append(members, f.UserID)
+		}
+	}
+	return Intersect(userIDs, members), nil
+}
+
+// Intersect giữ các phần tử của ids có trong members, giữ thứ tự của ids
+func Intersect(ids, members []string) []string {
+	in := make(map[string]bool, len(members))
+	for _, id := range members {
+		in[id] = true
+	}
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if in[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,265 @@
+package eventstream
+
+import (
+	"context"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RetryPolicy - message xử lý lỗi sẽ được retry sau backoff tăng dần (BaseBackoff * 2^(n-1)),
+// quá MaxRetries lần thì chuyển sang dead-letter stream
+type RetryPolicy struct {
+	MaxRetries  int64
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// reclaimPageSize - số entry mỗi lần XPENDING khi Reclaim duyệt pending list
+const reclaimPageSize = 100
+
+type pendingAction int
+
+const (
+	pendingWait       pendingAction = iota // chưa hết backoff, để lần Reclaim sau
+	pendingClaim                           // claim về để xử lý lại
+	pendingDeadLetter                      // đã giao quá MaxRetries lần
+)
+
+// action quyết định làm gì với 1 entry trong pending list
+func (p RetryPolicy) action(deliveries int64, idle time.Duration) pendingAction {
+	if deliveries > p.MaxRetries {
+		return pendingDeadLetter
+	}
+	if idle < p.backoff(deliveries) {
+		return pendingWait
+	}
+	return pendingClaim
+}
+
+func (p RetryPolicy) backoff(deliveries int64) time.Duration {
+	d := p.BaseBackoff
+	for i := int64(1); i < deliveries && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// Consumer đọc 1 Redis Stream theo consumer group (at-least-once).
+// Message chỉ bị xoá khỏi pending list khi được Ack hoặc bị chuyển sang dead-letter.
+type Consumer struct {
+	redisclient *redisclient.RedisClient
+	stream      string
+	group       string
+	consumer    string
+	policy      RetryPolicy
+}
+
+func NewConsumer(redisclient_ *redisclient.RedisClient, stream, group, consumer string, policy RetryPolicy) *Consumer {
+	return &Consumer{
+		redisclient: redisclient_,
+		stream:      stream,
+		group:       group,
+		consumer:    consumer,
+		policy:      policy,
+	}
+}
+
+// DeadLetterKey - stream chứa message group đã retry quá số lần cho phép.
+// Mỗi group 1 stream riêng vì 1 stream có thể được nhiều group đọc (VD post events: fan-out và notification).
+func DeadLetterKey(stream, group string) string {
+	return stream + ":dead:" + group
+}
+
+// errorsKey - hash message_id -> lỗi gần nhất của group, dùng để inspect dead letters
+func errorsKey(stream, group string) string {
+	return stream + ":errors:" + group
+}
+
+// ReplayGroupField - field đánh dấu message được replay từ dead letter cho riêng 1 group,
+// các group khác Ack và bỏ qua để không xử lý lại message chúng đã xử lý xong
+const ReplayGroupField = "replay_group"
+
+// EnsureGroup tạo consumer group (và stream nếu chưa có), bỏ qua nếu group đã tồn tại
+func (c *Consumer) EnsureGroup() error {
+	err := c.redisclient.GetClient().XGroupCreateMkStream(context.Background(), c.stream, c.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("[EventStream] failed to create group %s on %s: %w", c.group, c.stream, err)
+	}
+	return nil
+}
+
+// Read trả về tối đa count message mới chưa giao cho consumer nào
+func (c *Consumer) Read(count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := c.redisclient.GetClient().XReadGroup(context.Background(), &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.consumer,
+		Streams:  []string{c.stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[EventStream] failed to read %s: %w", c.stream, err)
+	}
+
+	var messages []redis.XMessage
+	for _, s := range streams {
+		messages = append(messages, s.Messages...)
+	}
+	return c.own(messages), nil
+}
+
+// own bỏ các message replay cho group khác (Ack luôn để không nằm lại trong pending list)
+func (c *Consumer) own(messages []redis.XMessage) []redis.XMessage {
+	var owned []redis.XMessage
+	var skipped []string
+	for _, msg := range messages {
+		if c.replayedForOtherGroup(msg) {
+			skipped = append(skipped, msg.ID)
+			continue
+		}
+		owned = append(owned, msg)
+	}
+	if err := c.Ack(skipped...); err != nil {
+		log.Printf("%v", err)
+	}
+	return owned
+}
+
+func (c *Consumer) replayedForOtherGroup(msg redis.XMessage) bool {
+	group, ok := msg.Values[ReplayGroupField]
+	return ok && group != c.group
+}
+
+// Reclaim duyệt pending list của group:
+//   - message đã giao quá MaxRetries lần -> chuyển sang dead-letter stream
+//   - message đã idle lâu hơn backoff -> claim về consumer này để xử lý lại
+//
+// (bao gồm cả message của consumer đã chết trước khi kịp Ack).
+// Pending list được duyệt từng trang theo ID cho tới khi claim đủ count message,
+// để message còn chờ backoff ở đầu list không chặn các message phía sau.
+func (c *Consumer) Reclaim(count int64) ([]redis.XMessage, error) {
+	ctx := context.Background()
+	pageSize := max(count, reclaimPageSize)
+
+	var messages []redis.XMessage
+	start := "-"
+	for int64(len(messages)) < count {
+		pending, err := c.redisclient.GetClient().XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: c.stream,
+			Group:  c.group,
+			Idle:   c.policy.BaseBackoff,
+			Start:  start,
+			End:    "+",
+			Count:  pageSize,
+		}).Result()
+		if err != nil {
+			return messages, fmt.Errorf("[EventStream] failed to list pending of %s: %w", c.stream, err)
+		}
+
+		for _, p := range pending {
+			if int64(len(messages)) >= count {
+				break
+			}
+			switch c.policy.action(p.RetryCount, p.Idle) {
+			case pendingWait:
+				continue
+			case pendingDeadLetter:
+				if err := c.deadLetter(p.ID, p.RetryCount); err != nil {
+					log.Printf("%v", err)
+				}
+				continue
+			}
+
+			claimed, err := c.redisclient.GetClient().XClaim(ctx, &redis.XClaimArgs{
+				Stream:   c.stream,
+				Group:    c.group,
+				Consumer: c.consumer,
+				MinIdle:  c.policy.backoff(p.RetryCount),
+				Messages: []string{p.ID},
+			}).Result()
+			if err != nil {
+				return messages, fmt.Errorf("[EventStream] failed to claim %s on %s: %w", p.ID, c.stream, err)
+			}
+			messages = append(messages, c.own(claimed)...)
+		}
+
+		if int64(len(pending)) < pageSize {
+			break
+		}
+		// "(" = exclusive, trang sau bắt đầu ngay sau entry cuối của trang này
+		start = "(" + pending[len(pending)-1].ID
+	}
+	return messages, nil
+}
+
+// Ack đánh dấu message đã xử lý xong
+func (c *Consumer) Ack(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	if err := c.redisclient.GetClient().XAck(ctx, c.stream, c.group, ids...).Err(); err != nil {
+		return fmt.Errorf("[EventStream] failed to ack %v on %s: %w", ids, c.stream, err)
+	}
+	c.redisclient.GetClient().HDel(ctx, errorsKey(c.stream, c.group), ids...)
+	return nil
+}
+
+// Fail ghi lại lỗi của lần xử lý gần nhất, message vẫn nằm trong pending để retry
+func (c *Consumer) Fail(id string, cause error) {
+	err := c.redisclient.GetClient().HSet(context.Background(), errorsKey(c.stream, c.group), id, cause.Error()).Err()
+	if err != nil {
+		log.Printf("[EventStream] failed to record error of %s on %s: %v", id, c.stream, err)
+	}
+}
+
+// deadLetter copy message sang dead-letter stream rồi Ack message gốc
+func (c *Consumer) deadLetter(id string, deliveries int64) error {
+	ctx := context.Background()
+	rdb := c.redisclient.GetClient()
+
+	msgs, err := rdb.XRange(ctx, c.stream, id, id).Result()
+	if err != nil {
+		return fmt.Errorf("[EventStream] failed to load %s from %s: %w", id, c.stream, err)
+	}
+
+	if len(msgs) > 0 {
+		lastErr, _ := rdb.HGet(ctx, errorsKey(c.stream, c.group), id).Result()
+		if err := rdb.XAdd(ctx, &redis.XAddArgs{
+			Stream: DeadLetterKey(c.stream, c.group),
+			Values: c.deadLetterValues(msgs[0], deliveries, lastErr),
+		}).Err(); err != nil {
+			return fmt.Errorf("[EventStream] failed to dead-letter %s: %w", id, err)
+		}
+		log.Printf("[EventStream] moved %s from %s to %s after %d deliveries: %s",
+			id, c.stream, DeadLetterKey(c.stream, c.group), deliveries, lastErr)
+	}
+
+	return c.Ack(id)
+}
+
+// deadLetterValues - field của entry dead-letter: metadata nguồn + field gốc với prefix "field:"
+func (c *Consumer) deadLetterValues(msg redis.XMessage, deliveries int64, lastErr string) map[string]interface{} {
+	values := map[string]interface{}{
+		"source_stream": c.stream,
+		"source_id":     msg.ID,
+		"group":         c.group,
+		"deliveries":    deliveries,
+		"last_error":    lastErr,
+	}
+	for k, v := range msg.Values {
+		values["field:"+k] = v
+	}
+	return values
+}
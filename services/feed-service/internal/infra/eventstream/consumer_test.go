@@ -0,0 +1,144 @@
+package eventstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var testPolicy = RetryPolicy{
+	MaxRetries:  5,
+	BaseBackoff: time.Second,
+	MaxBackoff:  10 * time.Second,
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		deliveries int64
+		want       time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second}, // 16s bị chặn ở MaxBackoff
+		{100, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := testPolicy.backoff(tt.deliveries); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.deliveries, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffBaseAboveMax(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 3, BaseBackoff: time.Minute, MaxBackoff: time.Second}
+	if got := p.backoff(1); got != time.Second {
+		t.Errorf("backoff(1) = %v, want %v", got, time.Second)
+	}
+}
+
+func TestRetryPolicyAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		deliveries int64
+		idle       time.Duration
+		want       pendingAction
+	}{
+		{"first delivery still idle", 1, 500 * time.Millisecond, pendingWait},
+		{"first delivery due", 1, time.Second, pendingClaim},
+		{"backoff grows with deliveries", 3, 3 * time.Second, pendingWait},
+		{"third delivery due", 3, 4 * time.Second, pendingClaim},
+		{"last retry due", 5, 10 * time.Second, pendingClaim},
+		{"last retry waits for max backoff", 5, 9 * time.Second, pendingWait},
+		{"over max retries", 6, 0, pendingDeadLetter},
+		{"over max retries ignores backoff", 6, time.Hour, pendingDeadLetter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testPolicy.action(tt.deliveries, tt.idle); got != tt.want {
+				t.Errorf("action(%d, %v) = %v, want %v", tt.deliveries, tt.idle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadLetterValues(t *testing.T) {
+	c := &Consumer{stream: "posts:events", group: "fanout"}
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"type": "NewPost", "data": `{"post_id":"p1"}`}}
+
+	got := c.deadLetterValues(msg, 6, "boom")
+	want := map[string]interface{}{
+		"source_stream": "posts:events",
+		"source_id":     "1-0",
+		"group":         "fanout",
+		"deliveries":    int64(6),
+		"last_error":    "boom",
+		"field:type":    "NewPost",
+		"field:data":    `{"post_id":"p1"}`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deadLetterValues() = %v, want %v", got, want)
+	}
+
+	// Replay lấy lại đúng field gốc, metadata bị bỏ
+	if original := originalValues(got); !reflect.DeepEqual(original, msg.Values) {
+		t.Errorf("originalValues() = %v, want %v", original, msg.Values)
+	}
+}
+
+func TestDeadLetterValuesKeepsMetadata(t *testing.T) {
+	c := &Consumer{stream: "s", group: "g"}
+	// field gốc trùng tên metadata không ghi đè metadata
+	msg := redis.XMessage{ID: "2-0", Values: map[string]interface{}{"source_id": "spoofed"}}
+
+	got := c.deadLetterValues(msg, 1, "")
+	if got["source_id"] != "2-0" || got["field:source_id"] != "spoofed" {
+		t.Errorf("deadLetterValues() = %v", got)
+	}
+}
+
+func TestReplayOnlyReachesFailedGroup(t *testing.T) {
+	// post events được 2 group đọc: fan-out (feed-service) và notification-service
+	fanout := &Consumer{stream: "post:events", group: "feed-service-fanout"}
+	notification := &Consumer{stream: "post:events", group: "notification-service"}
+	if DeadLetterKey(fanout.stream, fanout.group) == DeadLetterKey(notification.stream, notification.group) {
+		t.Fatalf("groups share dead-letter stream %s", DeadLetterKey(fanout.stream, fanout.group))
+	}
+	if errorsKey(fanout.stream, fanout.group) == errorsKey(notification.stream, notification.group) {
+		t.Fatalf("groups share errors hash %s", errorsKey(fanout.stream, fanout.group))
+	}
+
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"type": "NewPost", "data": `{"post_id":"p1"}`}}
+	for _, c := range []*Consumer{fanout, notification} {
+		if c.replayedForOtherGroup(msg) {
+			t.Errorf("group %s skips a normal message", c.group)
+		}
+	}
+
+	// notification xử lý lỗi quá MaxRetries, fan-out đã xử lý xong từ trước
+	dl := &DeadLetters{stream: notification.stream, group: notification.group}
+	replayed := redis.XMessage{ID: "2-0", Values: dl.replayValues(notification.deadLetterValues(msg, 6, "boom"))}
+	if replayed.Values["type"] != "NewPost" || replayed.Values["data"] != msg.Values["data"] {
+		t.Errorf("replayed values = %v, want original fields", replayed.Values)
+	}
+	if notification.replayedForOtherGroup(replayed) {
+		t.Errorf("failed group skips its replayed message")
+	}
+	if !fanout.replayedForOtherGroup(replayed) {
+		t.Errorf("other group processes a message replayed for %s", notification.group)
+	}
+
+	// message replay lỗi tiếp và bị dead-letter lần nữa vẫn chỉ replay cho group đó
+	again := redis.XMessage{ID: "3-0", Values: dl.replayValues(notification.deadLetterValues(replayed, 6, "boom"))}
+	if again.Values[ReplayGroupField] != notification.group || !fanout.replayedForOtherGroup(again) {
+		t.Errorf("second replay values = %v", again.Values)
+	}
+
+	if dl.replayValues(map[string]interface{}{"source_id": "1-0"}) != nil {
+		t.Errorf("replayValues without original fields should be nil")
+	}
+}
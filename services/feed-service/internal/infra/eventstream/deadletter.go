@@ -0,0 +1,89 @@
+package eventstream
+
+import (
+	"context"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DeadLetters - inspect và replay message trong dead-letter stream của 1 consumer group trên 1 stream
+type DeadLetters struct {
+	redisclient *redisclient.RedisClient
+	stream      string
+	group       string
+}
+
+func NewDeadLetters(redisclient_ *redisclient.RedisClient, stream, group string) *DeadLetters {
+	return &DeadLetters{
+		redisclient: redisclient_,
+		stream:      stream,
+		group:       group,
+	}
+}
+
+// Key - dead-letter stream đang inspect
+func (d *DeadLetters) Key() string {
+	return DeadLetterKey(d.stream, d.group)
+}
+
+// List trả về tối đa count dead letter cũ nhất
+func (d *DeadLetters) List(count int64) ([]redis.XMessage, error) {
+	msgs, err := d.redisclient.GetClient().XRangeN(context.Background(), d.Key(), "-", "+", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("[DeadLetters] failed to list %s: %w", d.Key(), err)
+	}
+	return msgs, nil
+}
+
+// Replay publish lại message gốc vào source stream (đánh dấu ReplayGroupField để chỉ group này xử lý lại)
+// rồi xoá khỏi dead-letter stream
+func (d *DeadLetters) Replay(id string) (string, error) {
+	ctx := context.Background()
+	rdb := d.redisclient.GetClient()
+
+	msgs, err := rdb.XRange(ctx, d.Key(), id, id).Result()
+	if err != nil {
+		return "", fmt.Errorf("[DeadLetters] failed to load %s: %w", id, err)
+	}
+	if len(msgs) == 0 {
+		return "", fmt.Errorf("[DeadLetters] dead letter %s not found", id)
+	}
+
+	values := d.replayValues(msgs[0].Values)
+	if values == nil {
+		return "", fmt.Errorf("[DeadLetters] dead letter %s has no original fields", id)
+	}
+
+	newID, err := rdb.XAdd(ctx, &redis.XAddArgs{Stream: d.stream, Values: values}).Result()
+	if err != nil {
+		return "", fmt.Errorf("[DeadLetters] failed to republish %s: %w", id, err)
+	}
+	if err := rdb.XDel(ctx, d.Key(), id).Err(); err != nil {
+		return newID, fmt.Errorf("[DeadLetters] replayed %s as %s but failed to delete it: %w", id, newID, err)
+	}
+	return newID, nil
+}
+
+// replayValues - field gốc của dead letter kèm group được replay, nil nếu không có field gốc
+func (d *DeadLetters) replayValues(deadLetter map[string]interface{}) map[string]interface{} {
+	values := originalValues(deadLetter)
+	if len(values) == 0 {
+		return nil
+	}
+	values[ReplayGroupField] = d.group
+	return values
+}
+
+// originalValues - field gốc của message trong 1 entry dead-letter (bỏ prefix "field:")
+func originalValues(deadLetter map[string]interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	for k, v := range deadLetter {
+		if field, ok := strings.CutPrefix(k, "field:"); ok {
+			values[field] = v
+		}
+	}
+	return values
+}
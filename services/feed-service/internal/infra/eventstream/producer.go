@@ -0,0 +1,47 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Producer append event vào Redis Stream, payload được encode JSON
+type Producer struct {
+	redisclient *redisclient.RedisClient
+	stream      string
+	maxLen      int64
+}
+
+func NewProducer(redisclient_ *redisclient.RedisClient, stream string, maxLen int64) *Producer {
+	return &Producer{
+		redisclient: redisclient_,
+		stream:      stream,
+		maxLen:      maxLen,
+	}
+}
+
+// Publish trả về ID của message trong stream
+func (p *Producer) Publish(eventType string, event interface{}) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("[EventStream] failed to marshal %s: %w", eventType, err)
+	}
+
+	id, err := p.redisclient.GetClient().XAdd(context.Background(), &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":    eventType,
+			"payload": payload,
+		},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("[EventStream] failed to publish %s to %s: %w", eventType, p.stream, err)
+	}
+	return id, nil
+}
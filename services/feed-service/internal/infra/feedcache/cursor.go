@@ -0,0 +1,136 @@
+package feedcache
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor là vị trí của 1 post trong feed theo thứ tự (score giảm dần, post_id giảm dần),
+// giống thứ tự ZREVRANGE nên không bị lệch khi có post mới chen vào đầu feed
+type Cursor struct {
+	Score  float64
+	PostID string
+}
+
+func CursorOf(z redis.Z) Cursor {
+	return Cursor{Score: z.Score, PostID: z.Member.(string)}
+}
+
+// Encode trả về cursor dạng opaque cho client
+func (c Cursor) Encode() string {
+	raw := strconv.FormatFloat(c.Score, 'f', -1, 64) + ":" + c.PostID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor - cursor do client gửi lên không đáng tin: score phải là số hữu hạn
+// và post_id phải là UUID dạng chuẩn, ngược lại trả về ErrInvalidCursor
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	scoreStr, postID, ok := strings.Cut(string(raw), ":")
+	if !ok || postID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	score, err := strconv.ParseFloat(scoreStr, 64)
+	if err != nil || math.IsNaN(score) || math.IsInf(score, 0) {
+		return Cursor{}, ErrInvalidCursor
+	}
+	// so với dạng chuẩn để loại các dạng uuid.Parse vẫn nhận ({...}, urn:uuid:, chữ hoa)
+	// vì post_id được so sánh chuỗi với member của ZSET
+	if id, err := uuid.Parse(postID); err != nil || id.String() != postID {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Score: score, PostID: postID}, nil
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+// RangeAfter trả về tối đa limit phần tử của ZSET key đứng sau cursor
+// (cursor nil = từ đầu). Post cùng score với cursor được lọc theo post_id,
+// phần còn lại lấy bằng ZREVRANGEBYSCORE với cận trên exclusive.
+// Cận dưới "(-inf" bỏ emptyMarker của feed rỗng.
+func (c *FeedCache) RangeAfter(ctx context.Context, key string, cursor *Cursor, limit int64) ([]redis.Z, error) {
+	client := c.redisclient.GetClient()
+	if cursor == nil {
+		posts, err := client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "(-inf", Max: "+inf", Count: limit}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		return posts, nil
+	}
+
+	score := formatScore(cursor.Score)
+	var ties, older *redis.ZSliceCmd
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ties = pipe.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: score, Max: score})
+		older = pipe.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "(-inf", Max: "(" + score, Count: limit})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s after cursor: %w", key, err)
+	}
+
+	result := make([]redis.Z, 0, limit)
+	for _, z := range ties.Val() {
+		if z.Member.(string) < cursor.PostID {
+			result = append(result, z)
+		}
+	}
+	result = append(result, older.Val()...)
+	if int64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// CountNewer đếm số phần tử đứng trước cursor (mới hơn) trên tất cả keys,
+// dùng cho banner "N bài viết mới"
+func (c *FeedCache) CountNewer(ctx context.Context, keys []string, cursor Cursor) (int64, error) {
+	score := formatScore(cursor.Score)
+	newer := make([]*redis.IntCmd, len(keys))
+	ties := make([]*redis.StringSliceCmd, len(keys))
+	_, err := c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			newer[i] = pipe.ZCount(ctx, key, "("+score, "+inf")
+			ties[i] = pipe.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: score, Max: score})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count new posts: %w", err)
+	}
+
+	var total int64
+	for i := range keys {
+		total += newer[i].Val()
+		for _, postID := range ties[i].Val() {
+			if postID > cursor.PostID {
+				total++
+			}
+		}
+	}
+	return total, nil
+}
+
+// Card trả về số post đang có trong feed của user (không tính emptyMarker)
+func (c *FeedCache) Card(ctx context.Context, userID string) (int64, error) {
+	n, err := c.redisclient.GetClient().ZCount(ctx, FeedKey(userID), "(-inf", "+inf").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count feed of %s: %w", userID, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package feedcache
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const testPostID = "0b6f5c2e-3f4a-4c1d-9e7b-2a8d6f1c3e5a"
+
+func encodeRaw(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{Score: 0, PostID: testPostID},
+		{Score: 1718000000, PostID: testPostID},
+		{Score: 1718000000123, PostID: testPostID},
+		// score có phần thập phân (trending) phải giữ nguyên từng bit, lệch 1 ulp là trang sau lặp/mất post
+		{Score: 1718000000.0000002, PostID: testPostID},
+		{Score: math.Nextafter(1718000000, 0), PostID: testPostID},
+		{Score: -42.5, PostID: testPostID},
+		{Score: math.MaxFloat64, PostID: testPostID},
+		{Score: math.SmallestNonzeroFloat64, PostID: testPostID},
+	}
+	for _, want := range tests {
+		got, err := DecodeCursor(want.Encode())
+		if err != nil {
+			t.Errorf("DecodeCursor(%+v) error = %v", want, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("round trip = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestCursorOfLastItem(t *testing.T) {
+	// next_cursor của trang là phần tử cuối, decode lại phải trỏ đúng phần tử đó
+	page := []redis.Z{
+		{Score: 300, Member: "9b6f5c2e-3f4a-4c1d-9e7b-2a8d6f1c3e5a"},
+		{Score: 200.5, Member: testPostID},
+	}
+	cursor, err := DecodeCursor(CursorOf(page[len(page)-1]).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Score != 200.5 || cursor.PostID != testPostID {
+		t.Errorf("cursor = %+v, want last item of page", cursor)
+	}
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"std base64 padding", base64.StdEncoding.EncodeToString([]byte("1:" + testPostID))},
+		{"no separator", encodeRaw("1718000000")},
+		{"missing post id", encodeRaw("1718000000:")},
+		{"missing score", encodeRaw(":" + testPostID)},
+		{"score not a number", encodeRaw("abc:" + testPostID)},
+		{"score with spaces", encodeRaw(" 1:" + testPostID)},
+		{"score NaN", encodeRaw("NaN:" + testPostID)},
+		{"score +Inf", encodeRaw("+Inf:" + testPostID)},
+		{"score -Inf", encodeRaw("-Inf:" + testPostID)},
+		{"score overflows", encodeRaw("1e400:" + testPostID)},
+		{"score redis exclusive prefix", encodeRaw("(100:" + testPostID)},
+		{"post id not uuid", encodeRaw("1:post-1")},
+		{"post id with extra field", encodeRaw("1:" + testPostID + ":x")},
+		{"post id uppercase", encodeRaw("1:0B6F5C2E-3F4A-4C1D-9E7B-2A8D6F1C3E5A")},
+		{"post id braces", encodeRaw("1:{" + testPostID + "}")},
+		{"post id urn", encodeRaw("1:urn:uuid:" + testPostID)},
+		{"post id without dashes", encodeRaw("1:0b6f5c2e3f4a4c1d9e7b2a8d6f1c3e5a")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := DecodeCursor(tt.cursor)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %+v, %v; want ErrInvalidCursor", tt.cursor, c, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorTampered(t *testing.T) {
+	// client sửa score/post_id trong cursor hợp lệ: vẫn decode được nếu đúng định dạng
+	// (cursor không ký, chỉ là vị trí), nhưng không thể chèn giá trị ngoài định dạng
+	valid := Cursor{Score: 1718000000, PostID: testPostID}.Encode()
+	raw, _ := base64.RawURLEncoding.DecodeString(valid)
+
+	tampered := append([]byte(nil), raw...)
+	tampered[0] = '9'
+	got, err := DecodeCursor(base64.RawURLEncoding.EncodeToString(tampered))
+	if err != nil || got.Score != 9718000000 {
+		t.Errorf("tampered score = %+v, %v", got, err)
+	}
+
+	tampered = append([]byte(nil), raw...)
+	tampered[len(tampered)-1] = 'z'
+	if _, err := DecodeCursor(base64.RawURLEncoding.EncodeToString(tampered)); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("tampered post id error = %v, want ErrInvalidCursor", err)
+	}
+
+	if _, err := DecodeCursor(valid[:len(valid)-3]); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("truncated cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
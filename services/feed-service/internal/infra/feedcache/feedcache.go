@@ -0,0 +1,180 @@
+package feedcache
+
+import (
+	"context"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type FeedConfig struct {
+	MaxLen    int64         // số post tối đa giữ trong user:{id}:feed, cũ hơn thì đọc từ Postgres
+	TTL       time.Duration // feed không được đọc trong TTL thì expire, user quay lại sẽ rebuild
+	ChunkSize int           // số follower mỗi pipeline khi fan-out
+}
+
+// pushScript chỉ ZADD vào feed đang tồn tại rồi trim về MaxLen.
+// Feed đã expire (user inactive) thì bỏ qua: feed thiếu sẽ được rebuild đầy đủ
+// từ Postgres lúc đọc, push 1 post vào sẽ tạo ra feed "có mà không đủ".
+//
+//	KEYS[1] = user:{id}:feed, ARGV[1] = max len, ARGV[2..] = score, member, ...
+var pushScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+for i = 2, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[1]) - 1)
+return 1
+`)
+
+// FeedCache gom các thao tác trên user:{id}:feed để fan-out, backfill và
+// scrolling feed dùng chung giới hạn độ dài và TTL
+type FeedCache struct {
+	redisclient *redisclient.RedisClient
+	cfg         FeedConfig
+}
+
+func NewFeedCache(redisclient_ *redisclient.RedisClient, cfg FeedConfig) *FeedCache {
+	return &FeedCache{
+		redisclient: redisclient_,
+		cfg:         cfg,
+	}
+}
+
+// emptyMarker giữ cho feed rỗng (user chưa follow ai hoặc followee chưa có post) vẫn tồn tại
+// sau Rebuild: ZSET rỗng không lưu được nên thêm 1 member score -inf, mọi thao tác đọc
+// lọc score > -inf. Feed có marker là feed đầy đủ nên push/backfill vẫn ghi vào được.
+var emptyMarker = redis.Z{Score: math.Inf(-1), Member: "empty"}
+
+func FeedKey(userID string) string {
+	return fmt.Sprintf("user:%s:feed", userID)
+}
+
+func (c *FeedCache) MaxLen() int64 {
+	return c.cfg.MaxLen
+}
+
+// PushToFeeds thêm post vào feed của nhiều user, mỗi chunk là 1 pipeline (1 round trip)
+func (c *FeedCache) PushToFeeds(ctx context.Context, userIDs []string, post redis.Z) error {
+	chunkSize := c.cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(userIDs)
+	}
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		if err := c.pushChunk(ctx, userIDs[start:end], []redis.Z{post}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveFromFeeds xoá post khỏi feed của nhiều user theo chunk pipeline
+func (c *FeedCache) RemoveFromFeeds(ctx context.Context, userIDs []string, postID string) error {
+	chunkSize := c.cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(userIDs)
+	}
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		_, err := c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, userID := range userIDs[start:end] {
+				pipe.ZRem(ctx, FeedKey(userID), postID)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove post %s from %d feeds: %w", postID, end-start, err)
+		}
+	}
+	return nil
+}
+
+// MergeIntoFeed thêm nhiều post vào feed của 1 user (backfill khi follow)
+func (c *FeedCache) MergeIntoFeed(ctx context.Context, userID string, posts []redis.Z) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	return c.pushChunk(ctx, []string{userID}, posts)
+}
+
+func (c *FeedCache) pushChunk(ctx context.Context, userIDs []string, posts []redis.Z) error {
+	args := make([]interface{}, 0, 1+2*len(posts))
+	args = append(args, c.cfg.MaxLen)
+	for _, z := range posts {
+		args = append(args, z.Score, z.Member)
+	}
+
+	exec := func() error {
+		cmds, err := c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, userID := range userIDs {
+				pipe.EvalSha(ctx, pushScript.Hash(), []string{FeedKey(userID)}, args...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, cmd := range cmds {
+			if cmd.Err() != nil {
+				return cmd.Err()
+			}
+		}
+		return nil
+	}
+
+	err := exec()
+	if redis.HasErrorPrefix(err, "NOSCRIPT") {
+		// Redis restart/flush làm mất script cache: load lại rồi chạy lại cả chunk (ZADD idempotent)
+		if err := pushScript.Load(ctx, c.redisclient.GetClient()).Err(); err != nil {
+			return fmt.Errorf("failed to load feed push script: %w", err)
+		}
+		err = exec()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to push %d posts to %d feeds: %w", len(posts), len(userIDs), err)
+	}
+	return nil
+}
+
+// Touch gia hạn TTL của feed khi user đọc; false nghĩa là feed đã expire hoặc chưa từng build
+func (c *FeedCache) Touch(ctx context.Context, userID string) (bool, error) {
+	ok, err := c.redisclient.GetClient().Expire(ctx, FeedKey(userID), c.cfg.TTL).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to refresh feed ttl of %s: %w", userID, err)
+	}
+	return ok, nil
+}
+
+// Rebuild ghi đè feed bằng posts (đọc từ Postgres), trim về MaxLen và set TTL.
+// posts rỗng thì ghi emptyMarker để các lần đọc trong TTL không phải rebuild lại.
+func (c *FeedCache) Rebuild(ctx context.Context, userID string, posts []redis.Z) error {
+	key := FeedKey(userID)
+	_, err := c.redisclient.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(posts) == 0 {
+			pipe.ZAdd(ctx, key, emptyMarker)
+			pipe.Expire(ctx, key, c.cfg.TTL)
+			return nil
+		}
+		pipe.ZAdd(ctx, key, posts...)
+		pipe.ZRemRangeByRank(ctx, key, 0, -c.cfg.MaxLen-1)
+		pipe.Expire(ctx, key, c.cfg.TTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rebuild feed of %s: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package mediaurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrURLExpired   = errors.New("media url expired")
+	ErrBadSignature = errors.New("invalid media url signature")
+)
+
+// SignCDN tạo {baseURL}/{objectKey}?exp={unix}&sig={HMAC-SHA256(secret, objectKey + "\n" + exp)}
+func SignCDN(baseURL string, secret []byte, objectKey string, exp time.Time) string {
+	expires := strconv.FormatInt(exp.Unix(), 10)
+	q := url.Values{}
+	q.Set("exp", expires)
+	q.Set("sig", cdnSignature(secret, objectKey, expires))
+	return strings.TrimRight(baseURL, "/") + "/" + escapePath(objectKey) + "?" + q.Encode()
+}
+
+// VerifyCDN kiểm tra chữ ký và hạn của URL do SignCDN tạo (objectKey đã unescape)
+func VerifyCDN(secret []byte, objectKey, expires, sig string, now time.Time) (time.Time, error) {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrBadSignature
+	}
+	expected := cdnSignature(secret, objectKey, expires)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return time.Time{}, ErrBadSignature
+	}
+	exp := time.Unix(unix, 0)
+	if !now.Before(exp) {
+		return time.Time{}, ErrURLExpired
+	}
+	return exp, nil
+}
+
+func cdnSignature(secret []byte, objectKey, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(objectKey + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// escapePath escape từng segment, giữ "/" (key có tên file của user)
+func escapePath(objectKey string) string {
+	segments := strings.Split(objectKey, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
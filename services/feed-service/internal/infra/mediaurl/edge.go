@@ -0,0 +1,94 @@
+package mediaurl
+
+import (
+	"errors"
+	"feedservice/internal/infra/s3client"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EdgeHandler - media-edge tối giản đứng trước bucket: xác thực URL của SignCDN rồi stream
+// object (hỗ trợ Range cho video) với Cache-Control đến hết hạn URL. Chạy local qua cmd/mediaedge,
+// production thay bằng CDN có cùng logic ký (edge function).
+type EdgeHandler struct {
+	objects *s3client.S3Client
+	secret  []byte
+	prefix  string // path prefix của baseURL, vd "/media/"
+}
+
+func NewEdgeHandler(objects *s3client.S3Client, secret []byte, prefix string) *EdgeHandler {
+	return &EdgeHandler{
+		objects: objects,
+		secret:  secret,
+		prefix:  "/" + strings.Trim(prefix, "/") + "/",
+	}
+}
+
+func (h *EdgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	objectKey, ok := strings.CutPrefix(r.URL.Path, h.prefix)
+	if !ok || objectKey == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+	exp, err := VerifyCDN(h.secret, objectKey, q.Get("exp"), q.Get("sig"), time.Now())
+	if errors.Is(err, ErrURLExpired) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// không đặt timeout: stream video lớn có thể lâu, client ngắt thì r.Context() huỷ
+	obj, err := h.objects.GetObjectRange(r.Context(), objectKey, r.Header.Get("Range"))
+	if errors.Is(err, s3client.ErrObjectNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if errors.Is(err, s3client.ErrInvalidRange) {
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	if err != nil {
+		log.Printf("[MediaEdge] %v", err)
+		http.Error(w, "failed to read object", http.StatusBadGateway)
+		return
+	}
+	defer obj.Body.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", obj.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(obj.ContentLength, 10))
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("ETag", obj.ETag)
+	if !obj.LastModified.IsZero() {
+		header.Set("Last-Modified", obj.LastModified.UTC().Format(http.TimeFormat))
+	}
+	// object theo key không bao giờ đổi nội dung, cache đến khi URL hết hạn
+	maxAge := int(time.Until(exp).Seconds())
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge)+", immutable")
+
+	status := http.StatusOK
+	if obj.ContentRange != "" {
+		header.Set("Content-Range", obj.ContentRange)
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, obj.Body); err != nil {
+		log.Printf("[MediaEdge] failed to stream %s: %v", objectKey, err)
+	}
+}
@@ -0,0 +1,109 @@
+package mediaurl
+
+import (
+	"context"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/infra/s3client"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ModePresign = "presign" // presigned GET S3/MinIO
+	ModeCDN     = "cdn"     // URL qua CDN/media-edge, ký HMAC (xem cdn.go)
+)
+
+type Config struct {
+	Mode string
+	// URL của 1 object giống hệt nhau trong cùng bucket thời gian để browser/CDN cache hit
+	Bucket time.Duration
+	// URL còn hạn ít nhất MinValidity sau khi bucket kết thúc (> TTL item cache của feed)
+	MinValidity time.Duration
+	CDNBaseURL  string // ModeCDN: vd http://localhost:9097/media
+	CDNSecret   []byte // ModeCDN: dùng chung với media-edge
+}
+
+// URLCacheKey - URL đã ký của objectKey trong bucket bắt đầu lúc bucketStart (unix)
+func URLCacheKey(mode string, bucketStart int64, objectKey string) string {
+	return "mediaurl:" + mode + ":" + strconv.FormatInt(bucketStart, 10) + ":" + objectKey
+}
+
+// URLSigner sinh URL đọc media ổn định theo bucket thời gian: ký với thời điểm đầu bucket
+// (cùng input -> cùng URL trên mọi instance) và cache trong Redis đến hết bucket
+type URLSigner struct {
+	objects     s3client.ObjectStore
+	redisclient *redisclient.RedisClient
+	cfg         Config
+}
+
+func NewURLSigner(objects s3client.ObjectStore, redisclient_ *redisclient.RedisClient, cfg Config) *URLSigner {
+	return &URLSigner{
+		objects:     objects,
+		redisclient: redisclient_,
+		cfg:         cfg,
+	}
+}
+
+// URLs trả URL cho từng object key. Key ký lỗi không có trong map (đã log);
+// Redis lỗi thì vẫn ký trực tiếp vì kết quả ký là như nhau.
+func (s *URLSigner) URLs(ctx context.Context, objectKeys []string) map[string]string {
+	urls := make(map[string]string, len(objectKeys))
+	if len(objectKeys) == 0 {
+		return urls
+	}
+
+	now := time.Now()
+	start := now.Truncate(s.cfg.Bucket)
+	cacheKeys := make([]string, len(objectKeys))
+	for i, key := range objectKeys {
+		cacheKeys[i] = URLCacheKey(s.cfg.Mode, start.Unix(), key)
+	}
+
+	client := s.redisclient.GetClient()
+	cached, err := client.MGet(ctx, cacheKeys...).Result()
+	if err != nil {
+		log.Printf("[URLSigner] failed to read url cache: %v", err)
+		cached = make([]interface{}, len(objectKeys))
+	}
+
+	var pipe redis.Pipeliner
+	ttl := max(start.Add(s.cfg.Bucket).Sub(now), time.Second)
+	for i, key := range objectKeys {
+		if url, ok := cached[i].(string); ok {
+			urls[key] = url
+			continue
+		}
+		url, err := s.sign(key, start)
+		if err != nil {
+			log.Printf("[URLSigner] %v", err)
+			continue
+		}
+		urls[key] = url
+		if pipe == nil {
+			pipe = client.Pipeline()
+		}
+		pipe.Set(ctx, cacheKeys[i], url, ttl)
+	}
+	if pipe != nil {
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("[URLSigner] failed to cache urls: %v", err)
+		}
+	}
+	return urls
+}
+
+func (s *URLSigner) sign(objectKey string, start time.Time) (string, error) {
+	validity := s.cfg.Bucket + s.cfg.MinValidity
+	switch s.cfg.Mode {
+	case ModeCDN:
+		return SignCDN(s.cfg.CDNBaseURL, s.cfg.CDNSecret, objectKey, start.Add(validity)), nil
+	case ModePresign:
+		return s.objects.PresignGetAt(objectKey, start, validity)
+	default:
+		return "", fmt.Errorf("unknown media url mode %q", s.cfg.Mode)
+	}
+}
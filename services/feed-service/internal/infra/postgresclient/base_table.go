@@ -11,9 +11,13 @@ type BaseTable struct {
 	TableName   string
 	Columns     map[string]string // column_name -> type (VD: "id": "SERIAL PRIMARY KEY")
 	Constraints []string          // danh sách constraint ở mức table (FOREIGN KEY, UNIQUE, CHECK, ...)
+	Indexes     []string          // CREATE [UNIQUE] INDEX IF NOT EXISTS ..., không nằm được trong CREATE TABLE nên chạy riêng
+	// AddedColumns - cột (key của Columns) thêm vào sau khi bảng đã được tạo ở môi trường cũ,
+	// Migrate chạy ALTER TABLE ... ADD COLUMN IF NOT EXISTS cho các cột này
+	AddedColumns []string
 }
 
-// CreateTable tạo bảng dựa trên metadata
+// CreateTable tạo bảng dựa trên metadata, sau đó tạo index
 func (bt *BaseTable) CreateTable() {
 	var cols []string
 	for col, typ := range bt.Columns {
@@ -34,9 +38,36 @@ func (bt *BaseTable) CreateTable() {
 	if err != nil {
 		log.Fatalf("❌ Lỗi tạo bảng %s: %v", bt.TableName, err)
 	}
+	bt.CreateIndexes()
 	log.Printf("✅ Bảng %s sẵn sàng.", bt.TableName)
 }
 
+// CreateIndexes tạo các index còn thiếu (IF NOT EXISTS), chạy được cả với bảng đã tồn tại
+func (bt *BaseTable) CreateIndexes() {
+	for _, index := range bt.Indexes {
+		if _, err := bt.Client.DB.Exec(index); err != nil {
+			log.Fatalf("❌ Lỗi tạo index cho bảng %s: %v", bt.TableName, err)
+		}
+	}
+}
+
+// Migrate đưa bảng đã tồn tại lên schema hiện tại: thêm cột còn thiếu rồi tạo index
+// (index có thể nằm trên cột mới thêm)
+func (bt *BaseTable) Migrate() {
+	for _, col := range bt.AddedColumns {
+		typ, ok := bt.Columns[col]
+		if !ok {
+			log.Fatalf("❌ Cột %s của bảng %s không có trong Columns", col, bt.TableName)
+		}
+		query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, bt.TableName, col, typ)
+		if _, err := bt.Client.DB.Exec(query); err != nil {
+			log.Fatalf("❌ Lỗi thêm cột %s vào bảng %s: %v", col, bt.TableName, err)
+		}
+	}
+	bt.CreateIndexes()
+	log.Printf("✅ Bảng %s đã migrate.", bt.TableName)
+}
+
 // Insert thêm dữ liệu vào bảng
 func (bt *BaseTable) Insert(values map[string]interface{}) {
 	cols := []string{}
@@ -1,12 +1,18 @@
 package main
 
 import (
-	dbclient "authservice/internal/infra/postgresclient"
-	"authservice/internal/infra/postgresclient/tables"
+	dbclient "feedservice/internal/infra/postgresclient"
+	"feedservice/internal/infra/postgresclient/tables"
 	"fmt"
 	"log"
 )
 
+type table interface {
+	CreateTable()
+	Migrate()
+	GetAll() ([]map[string]interface{}, error)
+}
+
 func main() {
 	client := dbclient.NewPostgresClient(
 		"localhost", // IP
@@ -17,40 +23,45 @@ func main() {
 	)
 	defer client.Close()
 
-	// Tạo bảng rules
-	cedentialsTable := tables.NewCredentialsTable(client)
-
-	if !client.SearchTable(cedentialsTable.TableName) {
-		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", cedentialsTable.TableName)
-		cedentialsTable.CreateTable()
-	} else {
-		fmt.Printf("%s EXISTED\n", cedentialsTable.TableName)
-	}
-
-	// Lấy tất cả rules
-	rows, err := cedentialsTable.GetAll()
-	if err != nil {
-		log.Fatal(err)
-	}
-	for _, row := range rows {
-		fmt.Println(row)
-	}
+	// Thứ tự tạo bảng theo foreign key: posts, medias -> post_media, post_revisions, media_renditions, post_hashtags, post_mentions; close_friends
+	postsTable := tables.NewPostsTable(client)
+	mediasTable := tables.NewMediasTable(client)
+	postMediaTable := tables.NewPostMediaTable(client)
+	outboxTable := tables.NewOutboxTable(client)
+	postRevisionsTable := tables.NewPostRevisionsTable(client)
+	mediaRenditionsTable := tables.NewMediaRenditionsTable(client)
+	postHashtagsTable := tables.NewPostHashtagsTable(client)
+	postMentionsTable := tables.NewPostMentionsTable(client)
+	closeFriendsTable := tables.NewCloseFriendsTable(client)
 
-	sessionsTable := tables.NewSessionsTable(client)
+	for _, tb := range []struct {
+		name string
+		t    table
+	}{
+		{postsTable.TableName, postsTable},
+		{mediasTable.TableName, mediasTable},
+		{postMediaTable.TableName, postMediaTable},
+		{outboxTable.TableName, outboxTable},
+		{postRevisionsTable.TableName, postRevisionsTable},
+		{mediaRenditionsTable.TableName, mediaRenditionsTable},
+		{postHashtagsTable.TableName, postHashtagsTable},
+		{postMentionsTable.TableName, postMentionsTable},
+		{closeFriendsTable.TableName, closeFriendsTable},
+	} {
+		if !client.SearchTable(tb.name) {
+			fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", tb.name)
+			tb.t.CreateTable()
+		} else {
+			fmt.Printf("%s EXISTED - MIGRATING\n", tb.name)
+			tb.t.Migrate()
+		}
 
-	if !client.SearchTable(sessionsTable.TableName) {
-		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", sessionsTable.TableName)
-		sessionsTable.CreateTable()
-	} else {
-		fmt.Printf("%s EXISTED\n", sessionsTable.TableName)
-	}
-
-	// Lấy tất cả rules
-	rows, err = sessionsTable.GetAll()
-	if err != nil {
-		log.Fatal(err)
-	}
-	for _, row := range rows {
-		fmt.Println(row)
+		rows, err := tb.t.GetAll()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, row := range rows {
+			fmt.Println(row)
+		}
 	}
 }
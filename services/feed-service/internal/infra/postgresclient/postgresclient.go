@@ -13,8 +13,9 @@ type PostgresClient struct {
 }
 
 func NewPostgresClient(host, port, user, password, dbname string) *PostgresClient {
+	// timezone=UTC: cột TIMESTAMP (không time zone) lưu giờ UTC, now() của DB khớp với time.Time UTC bên Go
 	psqlInfo := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable timezone=UTC",
 		host, port, user, password, dbname,
 	)
 
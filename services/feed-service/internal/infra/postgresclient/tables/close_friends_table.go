@@ -0,0 +1,29 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// CloseFriendsTable kế thừa BaseTable
+type CloseFriendsTable struct {
+	dbclient.BaseTable
+}
+
+// NewCloseFriendsTable khởi tạo table close_friends (danh sách close friends của mỗi user, dùng cho post visibility close_friends)
+func NewCloseFriendsTable(client *dbclient.PostgresClient) *CloseFriendsTable {
+	return &CloseFriendsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "close_friends",
+			Columns: map[string]string{
+				"user_id":    "UUID NOT NULL", // chủ danh sách
+				"friend_id":  "UUID NOT NULL",
+				"created_at": "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (user_id, friend_id)",
+				"CHECK (user_id <> friend_id)",
+				"FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE",
+				"FOREIGN KEY (friend_id) REFERENCES users(user_id) ON DELETE CASCADE",
+			},
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// MediaRenditionsTable kế thừa BaseTable
+type MediaRenditionsTable struct {
+	dbclient.BaseTable
+}
+
+// NewMediaRenditionsTable khởi tạo table media_renditions (bản dẫn xuất do media worker tạo)
+func NewMediaRenditionsTable(client *dbclient.PostgresClient) *MediaRenditionsTable {
+	return &MediaRenditionsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "media_renditions",
+			Columns: map[string]string{
+				"media_id":     "UUID NOT NULL",
+				"rendition":    "VARCHAR(10) NOT NULL CHECK (rendition IN ('thumb','medium','full','poster'))",
+				"objectkeys3":  "TEXT NOT NULL",
+				"content_type": "VARCHAR(100) NOT NULL",
+				"width":        "INT",
+				"height":       "INT",
+				"size_bytes":   "BIGINT NOT NULL",
+				"created_at":   "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (media_id, rendition)",
+				"FOREIGN KEY (media_id) REFERENCES medias(media_id) ON DELETE CASCADE",
+			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_media_renditions_objectkeys3 ON media_renditions(objectkeys3)", // MediaJanitor tìm orphan
+			},
+		},
+	}
+}
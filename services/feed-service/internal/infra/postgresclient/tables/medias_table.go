@@ -14,17 +14,26 @@ func NewMediasTable(client *dbclient.PostgresClient) *MediasTable {
 			Client:    client,
 			TableName: "medias",
 			Columns: map[string]string{
-				"media_id":    "UUID PRIMARY KEY",
-				"user_id":     "UUID NOT NULL",
-				"media_type":  "VARCHAR(10) NOT NULL CHECK (media_type IN ('image','video'))",
-				"objectkeys3": "TEXT NOT NULL", // S3/CDN URL
-				"status":      "VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','uploaded','failed'))",
-				"created_at":  "TIMESTAMP NOT NULL DEFAULT now()",
+				"media_id":      "UUID PRIMARY KEY",
+				"user_id":       "UUID NOT NULL",
+				"media_type":    "VARCHAR(10) NOT NULL CHECK (media_type IN ('image','video'))",
+				"objectkeys3":   "TEXT NOT NULL", // S3/CDN URL
+				"status":        "VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','uploaded','failed'))",
+				"content_type":  "VARCHAR(100)", // theo HeadObject lúc xác nhận upload
+				"size_bytes":    "BIGINT",
+				"upload_id":     "TEXT",   // multipart upload id, NULL = presigned PUT/POST
+				"declared_size": "BIGINT", // size khai báo khi tạo multipart upload
+				"created_at":    "TIMESTAMP NOT NULL DEFAULT now()",
 			},
 			Constraints: []string{
 				"FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE",
-				"CREATE INDEX idx_medias_user_status ON medias(user_id, status)",
 			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_medias_user_status ON medias(user_id, status)",
+				"CREATE INDEX IF NOT EXISTS idx_medias_status_created ON medias(status, created_at)", // MediaJanitor
+				"CREATE INDEX IF NOT EXISTS idx_medias_objectkeys3 ON medias(objectkeys3)",           // MediaJanitor tìm orphan
+			},
+			AddedColumns: []string{"content_type", "size_bytes", "upload_id", "declared_size"},
 		},
 	}
 }
@@ -0,0 +1,26 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// OutboxTable kế thừa BaseTable
+type OutboxTable struct {
+	dbclient.BaseTable
+}
+
+// NewOutboxTable khởi tạo table outbox (transactional outbox cho post events)
+func NewOutboxTable(client *dbclient.PostgresClient) *OutboxTable {
+	return &OutboxTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "outbox",
+			Columns: map[string]string{
+				"id":           "BIGSERIAL PRIMARY KEY",
+				"aggregate_id": "UUID NOT NULL",
+				"event_type":   "VARCHAR(50) NOT NULL",
+				"payload":      "JSONB NOT NULL",
+				"created_at":   "TIMESTAMP NOT NULL DEFAULT now()",
+				"delivered_at": "TIMESTAMP",
+			},
+		},
+	}
+}
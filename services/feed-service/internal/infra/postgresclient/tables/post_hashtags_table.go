@@ -0,0 +1,30 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// PostHashtagsTable kế thừa BaseTable
+type PostHashtagsTable struct {
+	dbclient.BaseTable
+}
+
+// NewPostHashtagsTable khởi tạo table post_hashtags (hashtag đã normalize của content hiện tại)
+func NewPostHashtagsTable(client *dbclient.PostgresClient) *PostHashtagsTable {
+	return &PostHashtagsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "post_hashtags",
+			Columns: map[string]string{
+				"post_id":    "UUID NOT NULL",
+				"hashtag":    "VARCHAR(100) NOT NULL",
+				"created_at": "TIMESTAMP NOT NULL", // = posts.created_at, để phân trang theo hashtag không cần join sort
+			},
+			Constraints: []string{
+				"PRIMARY KEY (post_id, hashtag)",
+				"FOREIGN KEY (post_id) REFERENCES posts(post_id) ON DELETE CASCADE",
+			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_post_hashtags_tag_created ON post_hashtags(hashtag, created_at DESC, post_id DESC)",
+			},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// PostMentionsTable kế thừa BaseTable
+type PostMentionsTable struct {
+	dbclient.BaseTable
+}
+
+// NewPostMentionsTable khởi tạo table post_mentions (user được @mention trong content hiện tại)
+func NewPostMentionsTable(client *dbclient.PostgresClient) *PostMentionsTable {
+	return &PostMentionsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "post_mentions",
+			Columns: map[string]string{
+				"post_id":    "UUID NOT NULL",
+				"user_id":    "UUID NOT NULL",        // user được mention
+				"username":   "VARCHAR(50) NOT NULL", // username lúc mention, user đổi tên thì link cũ vẫn đúng user
+				"created_at": "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (post_id, user_id)",
+				"FOREIGN KEY (post_id) REFERENCES posts(post_id) ON DELETE CASCADE",
+			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_post_mentions_user ON post_mentions(user_id, created_at DESC)",
+			},
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package tables
+
+import dbclient "feedservice/internal/infra/postgresclient"
+
+// PostRevisionsTable kế thừa BaseTable
+type PostRevisionsTable struct {
+	dbclient.BaseTable
+}
+
+// NewPostRevisionsTable khởi tạo table post_revisions (các phiên bản content trước mỗi lần sửa post)
+func NewPostRevisionsTable(client *dbclient.PostgresClient) *PostRevisionsTable {
+	return &PostRevisionsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "post_revisions",
+			Columns: map[string]string{
+				"revision_id": "BIGSERIAL PRIMARY KEY",
+				"post_id":     "UUID NOT NULL",
+				"content":     "TEXT",
+				"created_at":  "TIMESTAMP NOT NULL", // thời điểm content này được viết
+				"replaced_at": "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"FOREIGN KEY (post_id) REFERENCES posts(post_id) ON DELETE CASCADE",
+			},
+		},
+	}
+}
@@ -14,18 +14,25 @@ func NewPostsTable(client *dbclient.PostgresClient) *PostsTable {
 			Client:    client,
 			TableName: "posts",
 			Columns: map[string]string{
-				"post_id":    "UUID PRIMARY KEY",
-				"user_id":    "UUID NOT NULL",
-				"content":    "TEXT",
-				"created_at": "TIMESTAMP NOT NULL DEFAULT now()",
-				"updated_at": "TIMESTAMP",
-				"is_deleted": "BOOLEAN NOT NULL DEFAULT FALSE",
+				"post_id":        "UUID PRIMARY KEY",
+				"user_id":        "UUID NOT NULL",
+				"content":        "TEXT",
+				"created_at":     "TIMESTAMP NOT NULL DEFAULT now()",
+				"updated_at":     "TIMESTAMP",
+				"visibility":     "VARCHAR(20) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers', 'only_me', 'close_friends'))",
+				"shared_post_id": "UUID", // post gốc nếu đây là bài share; không FK để share còn lại (tombstone) khi post gốc bị purge
+				"is_deleted":     "BOOLEAN NOT NULL DEFAULT FALSE",
+				"deleted_at":     "TIMESTAMP", // hard purge sau retention
 			},
 			Constraints: []string{
 				"FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE",
-				"CREATE INDEX idx_posts_user_created ON posts(user_id, created_at DESC)",
-				"CREATE INDEX idx_posts_created ON posts(created_at DESC)",
 			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS idx_posts_user_created ON posts(user_id, created_at DESC)",
+				"CREATE INDEX IF NOT EXISTS idx_posts_created ON posts(created_at DESC)",
+				"CREATE INDEX IF NOT EXISTS idx_posts_shared ON posts(shared_post_id)", // đếm share
+			},
+			AddedColumns: []string{"deleted_at", "visibility", "shared_post_id"},
 		},
 	}
 }
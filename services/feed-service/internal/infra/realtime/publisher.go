@@ -0,0 +1,111 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"feedservice/internal/infra/redisclient"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Event types đẩy tới client qua realtime-service
+const (
+	FeedNewPost = "feed.new_post"
+)
+
+// ChannelKey - pub/sub channel realtime-service subscribe cho user đang kết nối
+func ChannelKey(userID string) string {
+	return "user:" + userID + ":events"
+}
+
+// LogKey - stream giữ các event gần nhất để client reconnect resume từ Last-Event-ID
+func LogKey(userID string) string {
+	return "user:" + userID + ":events:log"
+}
+
+// PresenceKey - realtime-service giữ key này (có TTL) khi user đang/vừa kết nối,
+// user offline lâu thì không ghi event để khỏi tốn memory
+func PresenceKey(userID string) string {
+	return "user:" + userID + ":events:presence"
+}
+
+// publishScript ghi event vào log stream rồi PUBLISH kèm id của entry đó
+//
+//	KEYS = presence, log, channel; ARGV = maxLen, ttl (giây), type, data
+var publishScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local id = redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[1], '*', 'type', ARGV[3], 'data', ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[2])
+redis.call('PUBLISH', KEYS[3], cjson.encode({id = id, type = ARGV[3], data = ARGV[4]}))
+return 1
+`)
+
+type Config struct {
+	LogMaxLen int64         // số event giữ lại cho mỗi user
+	LogTTL    time.Duration // log của user không còn event mới sẽ expire
+	ChunkSize int           // số user mỗi pipeline
+}
+
+// Publisher đẩy event tới user:{id}:events, chỉ user có presence mới được ghi
+type Publisher struct {
+	redisclient *redisclient.RedisClient
+	cfg         Config
+}
+
+func NewPublisher(redisclient_ *redisclient.RedisClient, cfg Config) *Publisher {
+	return &Publisher{
+		redisclient: redisclient_,
+		cfg:         cfg,
+	}
+}
+
+// Publish gửi cùng 1 event tới nhiều user, mỗi chunk là 1 pipeline
+func (p *Publisher) Publish(ctx context.Context, userIDs []string, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("[Realtime] failed to marshal %s: %w", eventType, err)
+	}
+
+	chunkSize := p.cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(userIDs)
+	}
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := min(start+chunkSize, len(userIDs))
+		if err := p.publishChunk(ctx, userIDs[start:end], eventType, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Publisher) publishChunk(ctx context.Context, userIDs []string, eventType string, payload []byte) error {
+	args := []interface{}{p.cfg.LogMaxLen, int64(p.cfg.LogTTL / time.Second), eventType, payload}
+	exec := func() error {
+		_, err := p.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, userID := range userIDs {
+				keys := []string{PresenceKey(userID), LogKey(userID), ChannelKey(userID)}
+				pipe.EvalSha(ctx, publishScript.Hash(), keys, args...)
+			}
+			return nil
+		})
+		return err
+	}
+
+	err := exec()
+	if redis.HasErrorPrefix(err, "NOSCRIPT") {
+		// Redis restart/flush làm mất script cache: load lại rồi chạy lại cả chunk
+		if err := publishScript.Load(ctx, p.redisclient.GetClient()).Err(); err != nil {
+			return fmt.Errorf("[Realtime] failed to load publish script: %w", err)
+		}
+		err = exec()
+	}
+	if err != nil {
+		return fmt.Errorf("[Realtime] failed to publish %s to %d users: %w", eventType, len(userIDs), err)
+	}
+	return nil
+}
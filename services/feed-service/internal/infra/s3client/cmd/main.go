@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"feedservice/internal/infra/s3client"
 	"fmt"
+	"log"
 	"path/filepath"
 	"time"
 
@@ -23,11 +25,32 @@ func main() {
 	secretKey := "minioadmin"
 
 	// Init client
-	s3client := s3client.NewS3Client(endpoint, region, accessKey, secretKey, bucket)
+	client, err := s3client.NewS3Client(context.Background(), s3client.Config{
+		Endpoint:     endpoint,
+		Region:       region,
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+		Bucket:       bucket,
+		UsePathStyle: true,
+		CreateBucket: true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	randomKey := GenerateRandomObjectKey("bell.png")
-	url := s3client.GeneratePreSignedURL(randomKey, 5*time.Minute)
-	fmt.Println("Presigned URL:", url)
+	cond := s3client.UploadConditions{ContentType: "image/png", MaxSize: 20 << 20}
+	upload, err := client.PresignPut(randomKey, cond, 5*time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Presigned URL:", upload.URL, upload.Headers)
+
+	form, err := client.PresignPost(randomKey, cond, 5*time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Presigned POST:", form.URL, form.Fields)
 
 	// Example: upload directly (optional)
 	//s3client.UploadFile("direct-upload.png", "../assets/bell.png")
@@ -0,0 +1,258 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FakeObjectStore - ObjectStore trong memory cho test, URL ký ra có dạng
+// fake://bucket/key?expires=... và không dùng được để upload/download thật.
+// Test giả lập client upload bằng PutObject (hoặc Upload với content type tuỳ ý).
+type FakeObjectStore struct {
+	Bucket string
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	uploads map[string]*fakeUpload // upload_id -> upload
+	nextID  int
+	now     func() time.Time
+}
+
+type fakeUpload struct {
+	key         string
+	contentType string
+	parts       map[int32][]byte
+}
+
+type fakeObject struct {
+	data         []byte
+	contentType  string
+	lastModified time.Time
+}
+
+func NewFakeObjectStore(bucket string) *FakeObjectStore {
+	return &FakeObjectStore{
+		Bucket:  bucket,
+		objects: make(map[string]fakeObject),
+		uploads: make(map[string]*fakeUpload),
+		now:     time.Now,
+	}
+}
+
+var _ ObjectStore = (*FakeObjectStore)(nil)
+var _ ObjectStore = (*S3Client)(nil)
+
+// SetClock đổi nguồn thời gian (LastModified, hạn URL) để test các nhánh quá hạn
+func (f *FakeObjectStore) SetClock(now func() time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Upload ghi object như client upload qua presigned URL, không kiểm tra điều kiện
+func (f *FakeObjectStore) Upload(objectKey string, data []byte, contentType string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[objectKey] = fakeObject{
+		data:         append([]byte(nil), data...),
+		contentType:  contentType,
+		lastModified: f.now(),
+	}
+}
+
+// Keys - các key đang có, đã sắp xếp
+func (f *FakeObjectStore) Keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *FakeObjectStore) presignedURL(method, objectKey string, expires time.Duration) string {
+	q := url.Values{}
+	q.Set("method", method)
+	q.Set("expires", strconv.FormatInt(f.now().Add(expires).Unix(), 10))
+	return fmt.Sprintf("fake://%s/%s?%s", f.Bucket, url.PathEscape(objectKey), q.Encode())
+}
+
+func (f *FakeObjectStore) PresignPut(objectKey string, cond UploadConditions, expires time.Duration) (PresignedUpload, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload := PresignedUpload{URL: f.presignedURL("PUT", objectKey, expires)}
+	if cond.ContentType != "" {
+		upload.Headers = map[string]string{"Content-Type": cond.ContentType}
+	}
+	return upload, nil
+}
+
+func (f *FakeObjectStore) PresignPost(objectKey string, cond UploadConditions, expires time.Duration) (PresignedPost, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fields := map[string]string{"key": objectKey}
+	if cond.ContentType != "" {
+		fields["Content-Type"] = cond.ContentType
+	}
+	if cond.MaxSize > 0 {
+		fields["x-fake-max-size"] = strconv.FormatInt(cond.MaxSize, 10)
+	}
+	return PresignedPost{URL: fmt.Sprintf("fake://%s", f.Bucket), Fields: fields}, nil
+}
+
+func (f *FakeObjectStore) PresignGet(objectKey string, expires time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.presignedURL("GET", objectKey, expires), nil
+}
+
+func (f *FakeObjectStore) PresignGetAt(objectKey string, signedAt time.Time, expires time.Duration) (string, error) {
+	q := url.Values{}
+	q.Set("method", "GET")
+	q.Set("expires", strconv.FormatInt(signedAt.Add(expires).Unix(), 10))
+	return fmt.Sprintf("fake://%s/%s?%s", f.Bucket, url.PathEscape(objectKey), q.Encode()), nil
+}
+
+func (f *FakeObjectStore) HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[objectKey]
+	if !ok {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+	return ObjectInfo{Size: int64(len(obj.data)), ContentType: obj.contentType}, nil
+}
+
+func (f *FakeObjectStore) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[objectKey]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (f *FakeObjectStore) PutObject(ctx context.Context, objectKey string, body io.ReadSeeker, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", objectKey, err)
+	}
+	f.Upload(objectKey, data, contentType)
+	return nil
+}
+
+// WalkObjects trả về theo thứ tự key như S3, mỗi trang tối đa 1000 key
+func (f *FakeObjectStore) WalkObjects(ctx context.Context, fn func(page []ObjectSummary) error) error {
+	f.mu.Lock()
+	all := make([]ObjectSummary, 0, len(f.objects))
+	for k, obj := range f.objects {
+		all = append(all, ObjectSummary{Key: k, Size: int64(len(obj.data)), LastModified: obj.lastModified})
+	}
+	f.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+
+	for start := 0; start < len(all); start += 1000 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(all[start:min(start+1000, len(all))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FakeObjectStore) DeleteObjects(ctx context.Context, objectKeys []string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, k := range objectKeys {
+		delete(f.objects, k)
+	}
+	return len(objectKeys), nil
+}
+
+func (f *FakeObjectStore) CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	uploadID := fmt.Sprintf("fake-upload-%d", f.nextID)
+	f.uploads[uploadID] = &fakeUpload{key: objectKey, contentType: contentType, parts: make(map[int32][]byte)}
+	return uploadID, nil
+}
+
+func (f *FakeObjectStore) PresignUploadPart(objectKey, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.presignedURL("PUT", fmt.Sprintf("%s?uploadId=%s&partNumber=%d", objectKey, uploadID, partNumber), expires), nil
+}
+
+// UploadPart ghi 1 part như client PUT lên URL của PresignUploadPart, trả về ETag
+func (f *FakeObjectStore) UploadPart(uploadID string, partNumber int32, data []byte) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload, ok := f.uploads[uploadID]
+	if !ok {
+		return "", ErrUploadNotFound
+	}
+	upload.parts[partNumber] = append([]byte(nil), data...)
+	return fakeETag(partNumber, len(data)), nil
+}
+
+func fakeETag(partNumber int32, size int) string {
+	return fmt.Sprintf(`"%d-%d"`, partNumber, size)
+}
+
+func (f *FakeObjectStore) ListParts(ctx context.Context, objectKey, uploadID string) ([]UploadedPart, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload, ok := f.uploads[uploadID]
+	if !ok || upload.key != objectKey {
+		return nil, ErrUploadNotFound
+	}
+	parts := make([]UploadedPart, 0, len(upload.parts))
+	for n, data := range upload.parts {
+		parts = append(parts, UploadedPart{PartNumber: n, ETag: fakeETag(n, len(data)), Size: int64(len(data))})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+func (f *FakeObjectStore) CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []UploadedPart) error {
+	f.mu.Lock()
+	upload, ok := f.uploads[uploadID]
+	if !ok || upload.key != objectKey {
+		f.mu.Unlock()
+		return ErrUploadNotFound
+	}
+	var data []byte
+	for i, p := range parts {
+		part, ok := upload.parts[p.PartNumber]
+		if !ok || p.ETag != fakeETag(p.PartNumber, len(part)) || (i > 0 && p.PartNumber <= parts[i-1].PartNumber) {
+			f.mu.Unlock()
+			return fmt.Errorf("failed to complete multipart upload %s: invalid part %d", objectKey, p.PartNumber)
+		}
+		data = append(data, part...)
+	}
+	delete(f.uploads, uploadID)
+	f.mu.Unlock()
+
+	f.Upload(objectKey, data, upload.contentType)
+	return nil
+}
+
+func (f *FakeObjectStore) AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.uploads, uploadID)
+	return nil
+}
@@ -2,96 +2,563 @@ package s3client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+var (
+	// ErrObjectNotFound - object chưa được upload lên key này (hoặc đã bị xoá)
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrPresign - không ký được URL (thiếu credentials, input sai), không làm chết service
+	ErrPresign = errors.New("failed to presign request")
+	// ErrInvalidRange - header Range nằm ngoài object (HTTP 416)
+	ErrInvalidRange = errors.New("requested range not satisfiable")
+	// ErrUploadNotFound - multipart upload đã complete/abort hoặc upload_id sai
+	ErrUploadNotFound = errors.New("multipart upload not found")
+)
+
+// ObjectStore - các thao tác feed-service cần trên bucket media,
+// S3Client cho MinIO/S3 thật, FakeObjectStore chạy trong memory cho test
+type ObjectStore interface {
+	PresignPut(objectKey string, cond UploadConditions, expires time.Duration) (PresignedUpload, error)
+	PresignPost(objectKey string, cond UploadConditions, expires time.Duration) (PresignedPost, error)
+	PresignGet(objectKey string, expires time.Duration) (string, error)
+	PresignGetAt(objectKey string, signedAt time.Time, expires time.Duration) (string, error)
+	HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error)
+	GetObject(ctx context.Context, objectKey string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, objectKey string, body io.ReadSeeker, contentType string) error
+	WalkObjects(ctx context.Context, fn func(page []ObjectSummary) error) error
+	DeleteObjects(ctx context.Context, objectKeys []string) (int, error)
+
+	CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error)
+	PresignUploadPart(objectKey, uploadID string, partNumber int32, expires time.Duration) (string, error)
+	ListParts(ctx context.Context, objectKey, uploadID string) ([]UploadedPart, error)
+	CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []UploadedPart) error
+	AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error
+}
+
+type Config struct {
+	Endpoint     string // rỗng = endpoint AWS mặc định của region
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	Bucket       string
+	UsePathStyle bool // MinIO cần path style (endpoint/bucket/key)
+	CreateBucket bool // tạo bucket nếu chưa có, chỉ dùng cho MinIO local
+	SSE          SSEConfig
+}
+
+// SSEConfig - mã hoá phía server cho object feed-service ghi và object client upload qua presigned URL
+type SSEConfig struct {
+	Algorithm string // "" (theo cấu hình bucket) | "AES256" | "aws:kms"
+	KMSKeyID  string // chỉ dùng với aws:kms, rỗng = key mặc định của account
+}
+
+// UploadConditions - ràng buộc cho object client upload, rỗng/0 = không ràng buộc
+type UploadConditions struct {
+	ContentType string
+	MaxSize     int64
+}
+
+// PresignedUpload - presigned PUT, client phải gửi đúng Headers vì chúng nằm trong chữ ký
+type PresignedUpload struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// PresignedPost - form upload (POST policy): S3 tự từ chối file sai Content-Type hoặc quá MaxSize,
+// client gửi multipart/form-data gồm toàn bộ Fields rồi đến field "file"
+type PresignedPost struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
 type S3Client struct {
 	Client *s3.Client
 	Bucket string
+	sse    SSEConfig
 }
 
-// NewS3Client with direct parameters instead of ENV
-func NewS3Client(endpoint, region, accessKey, secretKey, bucket string) *S3Client {
-	customResolver := aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
-		return aws.Endpoint{
-			URL:           endpoint,
-			SigningRegion: region,
-		}, nil
-	})
-
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(region),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
-		config.WithEndpointResolver(customResolver),
+// NewS3Client tạo client theo cfg (BaseEndpoint thay cho global endpoint resolver đã deprecated)
+func NewS3Client(ctx context.Context, cfg Config) (*S3Client, error) {
+	awscfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(cfg.Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, "")),
 	)
 	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
 	}
 
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.UsePathStyle = true // ✅ Important for MinIO
+	client := s3.NewFromConfig(awscfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
 	})
 
-	return &S3Client{
+	s := &S3Client{
 		Client: client,
-		Bucket: bucket,
+		Bucket: cfg.Bucket,
+		sse:    cfg.SSE,
+	}
+	if cfg.CreateBucket {
+		if err := s.ensureBucket(ctx, cfg.Region); err != nil {
+			return nil, err
+		}
 	}
+	return s, nil
 }
 
-// Generate a pre-signed PUT URL (safe to copy-paste into curl)
-func (s *S3Client) GeneratePreSignedURL(objectKey string, expires time.Duration) string {
-	ps := s3.NewPresignClient(s.Client)
+func (s *S3Client) ensureBucket(ctx context.Context, region string) error {
+	_, err := s.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &s.Bucket})
+	if err == nil {
+		return nil
+	}
+	if !isNotFound(err) {
+		return fmt.Errorf("failed to check bucket %s: %w", s.Bucket, err)
+	}
 
-	req, err := ps.PresignPutObject(context.TODO(), &s3.PutObjectInput{
+	input := &s3.CreateBucketInput{Bucket: &s.Bucket}
+	if region != "" && region != "us-east-1" { // us-east-1 không nhận LocationConstraint
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		}
+	}
+	_, err = s.Client.CreateBucket(ctx, input)
+	var owned *types.BucketAlreadyOwnedByYou
+	if err != nil && !errors.As(err, &owned) { // instance khác vừa tạo xong
+		return fmt.Errorf("failed to create bucket %s: %w", s.Bucket, err)
+	}
+	log.Printf("[S3Client] created bucket %s", s.Bucket)
+	return nil
+}
+
+// PresignPut ký URL PUT, SSE (nếu có) thành signed header. SDK không ký Content-Type và PUT không
+// giới hạn được kích thước, nên cond chỉ được kiểm tra lúc HeadObject; muốn S3 chặn từ đầu thì dùng PresignPost.
+// Headers gồm signed header và Content-Type mà client cần gửi.
+func (s *S3Client) PresignPut(objectKey string, cond UploadConditions, expires time.Duration) (PresignedUpload, error) {
+	input := &s3.PutObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	}
+	if cond.ContentType != "" {
+		input.ContentType = aws.String(cond.ContentType)
+	}
+	s.applySSE(input)
+
+	req, err := s3.NewPresignClient(s.Client).PresignPutObject(context.TODO(), input, s3.WithPresignExpires(expires))
+	if err != nil {
+		return PresignedUpload{}, fmt.Errorf("%w: PUT %s: %v", ErrPresign, objectKey, err)
+	}
+
+	upload := PresignedUpload{URL: req.URL, Headers: map[string]string{}}
+	for name, values := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		upload.Headers[name] = values[0]
+	}
+	if cond.ContentType != "" {
+		upload.Headers["Content-Type"] = cond.ContentType
+	}
+	return upload, nil
+}
+
+// PresignPost ký POST policy với content-length-range và Content-Type cố định
+func (s *S3Client) PresignPost(objectKey string, cond UploadConditions, expires time.Duration) (PresignedPost, error) {
+	fields := map[string]string{}
+	var conditions []interface{}
+	if cond.ContentType != "" {
+		fields["Content-Type"] = cond.ContentType
+		conditions = append(conditions, map[string]string{"Content-Type": cond.ContentType})
+	}
+	if cond.MaxSize > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", 1, cond.MaxSize})
+	}
+	if s.sse.Algorithm != "" {
+		fields["x-amz-server-side-encryption"] = s.sse.Algorithm
+		conditions = append(conditions, map[string]string{"x-amz-server-side-encryption": s.sse.Algorithm})
+		if s.sse.KMSKeyID != "" {
+			fields["x-amz-server-side-encryption-aws-kms-key-id"] = s.sse.KMSKeyID
+			conditions = append(conditions, map[string]string{"x-amz-server-side-encryption-aws-kms-key-id": s.sse.KMSKeyID})
+		}
+	}
+
+	req, err := s3.NewPresignClient(s.Client).PresignPostObject(context.TODO(), &s3.PutObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = expires
+		o.Conditions = conditions
+	})
+	if err != nil {
+		return PresignedPost{}, fmt.Errorf("%w: POST %s: %v", ErrPresign, objectKey, err)
+	}
+	for k, v := range req.Values {
+		fields[k] = v
+	}
+	return PresignedPost{URL: req.URL, Fields: fields}, nil
+}
+
+// PresignGet ký URL GET có hạn
+func (s *S3Client) PresignGet(objectKey string, expires time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.Client).PresignGetObject(context.TODO(), &s3.GetObjectInput{
 		Bucket: &s.Bucket,
 		Key:    &objectKey,
 	}, s3.WithPresignExpires(expires))
 	if err != nil {
-		log.Fatalf("failed to sign request: %v", err)
+		return "", fmt.Errorf("%w: GET %s: %v", ErrPresign, objectKey, err)
+	}
+	return req.URL, nil
+}
+
+// PresignGetAt ký URL GET với signing time cố định: cùng key, signedAt, expires thì URL giống hệt nhau
+// (trên mọi instance dùng chung credentials), URL hợp lệ từ signedAt đến signedAt + expires
+func (s *S3Client) PresignGetAt(objectKey string, signedAt time.Time, expires time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.Client).PresignGetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	}, s3.WithPresignExpires(expires), func(o *s3.PresignOptions) {
+		o.Presigner = fixedTimePresigner{signer: v4.NewSigner(), signedAt: signedAt}
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: GET %s: %v", ErrPresign, objectKey, err)
+	}
+	return req.URL, nil
+}
+
+// fixedTimePresigner bỏ qua signing time SDK truyền vào (thời điểm hiện tại)
+type fixedTimePresigner struct {
+	signer   *v4.Signer
+	signedAt time.Time
+}
+
+func (p fixedTimePresigner) PresignHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request,
+	payloadHash string, service string, region string, _ time.Time, optFns ...func(*v4.SignerOptions),
+) (string, http.Header, error) {
+	return p.signer.PresignHTTP(ctx, credentials, r, payloadHash, service, region, p.signedAt, optFns...)
+}
+
+func (s *S3Client) applySSE(input *s3.PutObjectInput) {
+	if s.sse.Algorithm == "" {
+		return
+	}
+	input.ServerSideEncryption = types.ServerSideEncryption(s.sse.Algorithm)
+	if s.sse.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(s.sse.KMSKeyID)
+	}
+}
+
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	var noBucket *types.NoSuchBucket
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &notFound) || errors.As(err, &noBucket) ||
+		(errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound)
+}
+
+// ObjectInfo - metadata S3 lưu lúc client PUT object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// HeadObject đọc metadata của object mà không tải nội dung, ErrObjectNotFound nếu chưa có
+func (s *S3Client) HeadObject(ctx context.Context, objectKey string) (ObjectInfo, error) {
+	out, err := s.Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to head object %s: %w", objectKey, err)
+	}
+	return ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}, nil
+}
+
+// GetObject mở nội dung object, caller phải Close
+func (s *S3Client) GetObject(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object %s: %w", objectKey, err)
 	}
-	// ✅ Wrap with quotes so it's shell-safe
-	return req.URL
+	return out.Body, nil
 }
 
-// GeneratePreSignedGetURL generates a pre-signed URL for GET requests
-func (s *S3Client) GeneratePreSignedGetURL(objectKey string, expires time.Duration) string {
-	ps := s3.NewPresignClient(s.Client)
+// ObjectContent - nội dung (hoặc 1 đoạn) object kèm metadata để trả thẳng cho HTTP client
+type ObjectContent struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+	ContentRange  string // có khi đọc theo Range, response là 206
+	ETag          string
+	LastModified  time.Time
+}
 
-	req, err := ps.PresignGetObject(context.TODO(), &s3.GetObjectInput{
+// GetObjectRange đọc object theo header Range của HTTP (rỗng = cả object), caller phải Close Body
+func (s *S3Client) GetObjectRange(ctx context.Context, objectKey string, byteRange string) (ObjectContent, error) {
+	input := &s3.GetObjectInput{
 		Bucket: &s.Bucket,
 		Key:    &objectKey,
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+	out, err := s.Client.GetObject(ctx, input)
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) || isNotFound(err) {
+			return ObjectContent{}, ErrObjectNotFound
+		}
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusRequestedRangeNotSatisfiable {
+			return ObjectContent{}, ErrInvalidRange
+		}
+		return ObjectContent{}, fmt.Errorf("failed to get object %s: %w", objectKey, err)
+	}
+	return ObjectContent{
+		Body:          out.Body,
+		ContentType:   aws.ToString(out.ContentType),
+		ContentLength: aws.ToInt64(out.ContentLength),
+		ContentRange:  aws.ToString(out.ContentRange),
+		ETag:          aws.ToString(out.ETag),
+		LastModified:  aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// PutObject ghi object do server tạo ra (rendition, ...), body phải seek được để SDK tính checksum
+func (s *S3Client) PutObject(ctx context.Context, objectKey string, body io.ReadSeeker, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket:      &s.Bucket,
+		Key:         &objectKey,
+		Body:        body,
+		ContentType: &contentType,
+	}
+	s.applySSE(input)
+	if _, err := s.Client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", objectKey, err)
+	}
+	return nil
+}
+
+// ObjectSummary - 1 object khi liệt kê bucket
+type ObjectSummary struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// WalkObjects liệt kê toàn bộ object trong bucket theo trang (tối đa 1000 key),
+// fn trả lỗi thì dừng
+func (s *S3Client) WalkObjects(ctx context.Context, fn func(page []ObjectSummary) error) error {
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: &s.Bucket,
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list bucket %s: %w", s.Bucket, err)
+		}
+		page := make([]ObjectSummary, 0, len(out.Contents))
+		for _, obj := range out.Contents {
+			page = append(page, ObjectSummary{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteObjects xoá nhiều object (chia batch 1000 key theo giới hạn S3), key không tồn tại
+// coi như đã xoá. Trả về số key xoá được, lỗi gồm các key xoá thất bại.
+func (s *S3Client) DeleteObjects(ctx context.Context, objectKeys []string) (int, error) {
+	const maxKeys = 1000
+	deleted := 0
+	var failed []string
+	for start := 0; start < len(objectKeys); start += maxKeys {
+		batch := objectKeys[start:min(start+maxKeys, len(objectKeys))]
+		ids := make([]types.ObjectIdentifier, len(batch))
+		for i := range batch {
+			ids[i] = types.ObjectIdentifier{Key: aws.String(batch[i])}
+		}
+		out, err := s.Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: &s.Bucket,
+			Delete: &types.Delete{Objects: ids, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete objects: %w", err)
+		}
+		// Quiet: chỉ trả về các key lỗi
+		deleted += len(batch) - len(out.Errors)
+		for _, e := range out.Errors {
+			failed = append(failed, fmt.Sprintf("%s (%s)", aws.ToString(e.Key), aws.ToString(e.Message)))
+		}
+	}
+	if len(failed) > 0 {
+		return deleted, fmt.Errorf("failed to delete %d objects: %v", len(failed), failed)
+	}
+	return deleted, nil
+}
+
+// UploadedPart - 1 part đã upload của multipart upload, ETag do S3 trả về khi PUT part
+type UploadedPart struct {
+	PartNumber int32  `json:"part_number"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size,omitempty"`
+}
+
+// CreateMultipartUpload bắt đầu multipart upload (áp SSE), trả về upload_id
+func (s *S3Client) CreateMultipartUpload(ctx context.Context, objectKey string, contentType string) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: &s.Bucket,
+		Key:    &objectKey,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if s.sse.Algorithm != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(s.sse.Algorithm)
+		if s.sse.KMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(s.sse.KMSKeyID)
+		}
+	}
+	out, err := s.Client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload %s: %w", objectKey, err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// PresignUploadPart ký URL PUT cho 1 part (partNumber 1..10000)
+func (s *S3Client) PresignUploadPart(objectKey, uploadID string, partNumber int32, expires time.Duration) (string, error) {
+	req, err := s3.NewPresignClient(s.Client).PresignUploadPart(context.TODO(), &s3.UploadPartInput{
+		Bucket:     &s.Bucket,
+		Key:        &objectKey,
+		UploadId:   &uploadID,
+		PartNumber: aws.Int32(partNumber),
 	}, s3.WithPresignExpires(expires))
 	if err != nil {
-		log.Fatalf("failed to sign GET request: %v", err)
+		return "", fmt.Errorf("%w: part %d of %s: %v", ErrPresign, partNumber, objectKey, err)
+	}
+	return req.URL, nil
+}
+
+// ListParts liệt kê các part S3 đã nhận, theo part number tăng dần
+func (s *S3Client) ListParts(ctx context.Context, objectKey, uploadID string) ([]UploadedPart, error) {
+	paginator := s3.NewListPartsPaginator(s.Client, &s3.ListPartsInput{
+		Bucket:   &s.Bucket,
+		Key:      &objectKey,
+		UploadId: &uploadID,
+	})
+	var parts []UploadedPart
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, uploadError(err, "failed to list parts of "+objectKey)
+		}
+		for _, p := range out.Parts {
+			parts = append(parts, UploadedPart{
+				PartNumber: aws.ToInt32(p.PartNumber),
+				ETag:       aws.ToString(p.ETag),
+				Size:       aws.ToInt64(p.Size),
+			})
+		}
+	}
+	return parts, nil
+}
+
+// CompleteMultipartUpload ghép các part (phải theo part number tăng dần) thành object
+func (s *S3Client) CompleteMultipartUpload(ctx context.Context, objectKey, uploadID string, parts []UploadedPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = types.CompletedPart{PartNumber: aws.Int32(p.PartNumber), ETag: aws.String(p.ETag)}
+	}
+	_, err := s.Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &s.Bucket,
+		Key:             &objectKey,
+		UploadId:        &uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return uploadError(err, "failed to complete multipart upload "+objectKey)
 	}
-	return req.URL
+	return nil
 }
 
-// Upload directly from Go (simulate user upload)
-func UploadWithPresignedURL(url string, filePath string) error {
+// AbortMultipartUpload huỷ upload và xoá các part đã nhận, upload không còn thì bỏ qua
+func (s *S3Client) AbortMultipartUpload(ctx context.Context, objectKey, uploadID string) error {
+	_, err := s.Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &s.Bucket,
+		Key:      &objectKey,
+		UploadId: &uploadID,
+	})
+	if err != nil {
+		if err := uploadError(err, "failed to abort multipart upload "+objectKey); !errors.Is(err, ErrUploadNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func uploadError(err error, msg string) error {
+	var noUpload *types.NoSuchUpload
+	if errors.As(err, &noUpload) || isNotFound(err) {
+		return ErrUploadNotFound
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// Upload directly from Go (simulate user upload).
+// contentType phải khớp media_type đã khai báo, nếu không post sẽ bị từ chối.
+func UploadWithPresignedURL(upload PresignedUpload, filePath string, contentType string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	req, err := http.NewRequest("PUT", url, file)
+	req, err := http.NewRequest("PUT", upload.URL, file)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set Content-Type (optional, e.g., image/png)
-	req.Header.Set("Content-Type", "application/octet-stream")
+	// Content-Type được S3 lưu lại, feed-service kiểm tra bằng HeadObject
+	req.Header.Set("Content-Type", contentType)
+	// signed header (Content-Type, SSE, ...) phải gửi đúng giá trị đã ký
+	for name, value := range upload.Headers {
+		req.Header.Set(name, value)
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Audience - người đang xem 1 danh sách post, dùng để lọc visibility ngay trong query
+// (trang không bị hụt vì post bị ẩn, cursor vẫn đúng thứ tự)
+type Audience struct {
+	ViewerID string // rỗng = không đăng nhập, chỉ thấy post public
+	Follower bool   // viewer follow các author trong query (rebuild feed, trang profile của followee)
+}
+
+// filter trả về điều kiện visibility cho bảng posts có alias, ViewerID được truyền ở tham số $n.
+// So sánh bằng ::text để ViewerID rỗng không lỗi cast UUID.
+func (a Audience) filter(alias string, n int) string {
+	viewer := fmt.Sprintf("$%d", n)
+	conds := []string{
+		alias + ".user_id::text = " + viewer,
+		alias + ".visibility = 'public'",
+	}
+	if a.Follower {
+		conds = append(conds, alias+".visibility = 'followers'")
+	}
+	conds = append(conds, "("+alias+".visibility = 'close_friends' AND EXISTS ("+
+		"SELECT 1 FROM close_friends cf WHERE cf.user_id = "+alias+".user_id AND cf.friend_id::text = "+viewer+"))")
+	return "(" + strings.Join(conds, " OR ") + ")"
+}
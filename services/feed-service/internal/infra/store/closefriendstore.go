@@ -0,0 +1,101 @@
+package store
+
+import (
+	"errors"
+	dbclient "feedservice/internal/infra/postgresclient"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CloseFriendStore - danh sách close friends của user (close_friends), audience của post visibility close_friends
+type CloseFriendStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+func NewCloseFriendStore(postgrescfg *PostGresConfig) *CloseFriendStore {
+	closeFriendStore := &CloseFriendStore{}
+	closeFriendStore.DBClient = dbclient.NewPostgresClient(postgrescfg.Host, postgrescfg.Port, postgrescfg.User, postgrescfg.Password, postgrescfg.DBname)
+	return closeFriendStore
+}
+
+var ErrFriendNotFound = errors.New("user not found")
+
+type CloseFriend struct {
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Add thêm friendID vào danh sách của userID, đã có thì giữ nguyên
+func (c *CloseFriendStore) Add(userID, friendID string) error {
+	query := `INSERT INTO close_friends (user_id, friend_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := c.DBClient.DB.Exec(query, userID, friendID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+			return ErrFriendNotFound
+		}
+		return fmt.Errorf("failed to add close friend %s of %s: %w", friendID, userID, err)
+	}
+	return nil
+}
+
+// Remove bỏ friendID khỏi danh sách của userID, không có thì bỏ qua
+func (c *CloseFriendStore) Remove(userID, friendID string) error {
+	query := `DELETE FROM close_friends WHERE user_id = $1 AND friend_id = $2`
+	if _, err := c.DBClient.DB.Exec(query, userID, friendID); err != nil {
+		return fmt.Errorf("failed to remove close friend %s of %s: %w", friendID, userID, err)
+	}
+	return nil
+}
+
+// List trả về danh sách close friends của userID, thêm gần nhất trước
+func (c *CloseFriendStore) List(userID string) ([]CloseFriend, error) {
+	query := `SELECT friend_id, created_at FROM close_friends WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := c.DBClient.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch close friends of %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	friends := []CloseFriend{}
+	for rows.Next() {
+		var f CloseFriend
+		if err := rows.Scan(&f.UserID, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan close friend: %w", err)
+		}
+		friends = append(friends, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return friends, nil
+}
+
+// ListedBy trả về các author (trong authorIDs) có viewerID trong danh sách close friends
+func (c *CloseFriendStore) ListedBy(viewerID string, authorIDs []string) (map[string]bool, error) {
+	listed := make(map[string]bool, len(authorIDs))
+	if viewerID == "" || len(authorIDs) == 0 {
+		return listed, nil
+	}
+
+	query := `SELECT user_id FROM close_friends WHERE user_id = ANY($1) AND friend_id = $2`
+	rows, err := c.DBClient.DB.Query(query, pq.Array(authorIDs), viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check close friends of %s: %w", viewerID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var authorID string
+		if err := rows.Scan(&authorID); err != nil {
+			return nil, fmt.Errorf("failed to scan close friend: %w", err)
+		}
+		listed[authorID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return listed, nil
+}
@@ -0,0 +1,24 @@
+package store
+
+import "fmt"
+
+// feedOrder - thứ tự post giống feed trong Redis: score = created_at tính bằng giây, tie thì post_id (text) giảm dần.
+// Trang đầu và các trang sau cursor phải sort cùng 1 key, nếu không post cùng giây ở biên trang bị lặp/mất.
+type feedOrder struct {
+	key string // (giây, post_id) để so với cursor
+	by  string // ORDER BY tương ứng với key
+}
+
+func newFeedOrder(alias string) feedOrder {
+	seconds := "floor(extract(epoch FROM " + alias + ".created_at))::bigint"
+	postID := alias + ".post_id::text"
+	return feedOrder{
+		key: "(" + seconds + ", " + postID + ")",
+		by:  seconds + " DESC, " + postID + " DESC",
+	}
+}
+
+// before trả về điều kiện "đứng sau cursor", cursor truyền ở tham số $n (giây) và $n+1 (post_id)
+func (o feedOrder) before(n int) string {
+	return fmt.Sprintf("%s < ($%d, $%d)", o.key, n, n+1)
+}
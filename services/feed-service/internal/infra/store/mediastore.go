@@ -1,17 +1,22 @@
 package store
 
 import (
+	"context"
 	"database/sql"
+	"feedservice/internal/infra/mediaurl"
 	dbclient "feedservice/internal/infra/postgresclient"
 	s3 "feedservice/internal/infra/s3client"
 	"feedservice/internal/model"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type MediaStore struct {
-	DBClient *dbclient.PostgresClient
-	S3client *s3.S3Client
+	DBClient  *dbclient.PostgresClient
+	S3client  s3.ObjectStore
+	URLSigner *mediaurl.URLSigner // URL đọc media, nil với tool không serve media (janitor)
 }
 
 type PostGresConfig struct {
@@ -22,18 +27,12 @@ type PostGresConfig struct {
 	DBname   string
 }
 
-type S3Config struct {
-	Endpoint  string
-	Bucket    string
-	Region    string
-	AccessKey string
-	SecretKey string
-}
-
-func NewMediaStore(postgrescfg *PostGresConfig, s3cfg *S3Config) *MediaStore {
+// NewMediaStore - objectStore là *s3client.S3Client khi chạy thật, FakeObjectStore khi test
+func NewMediaStore(postgrescfg *PostGresConfig, objectStore s3.ObjectStore, urlSigner *mediaurl.URLSigner) *MediaStore {
 	mediaStore := &MediaStore{}
 	mediaStore.DBClient = dbclient.NewPostgresClient(postgrescfg.Host, postgrescfg.Port, postgrescfg.User, postgrescfg.Password, postgrescfg.DBname)
-	mediaStore.S3client = s3.NewS3Client(s3cfg.Endpoint, s3cfg.Region, s3cfg.AccessKey, s3cfg.SecretKey, s3cfg.Bucket)
+	mediaStore.S3client = objectStore
+	mediaStore.URLSigner = urlSigner
 	return mediaStore
 }
 
@@ -43,7 +42,7 @@ func (m *MediaStore) ValidateUserMedia(userID string, mediaIDs []string) error {
 	}
 
 	query := `SELECT media_id FROM medias WHERE user_id=$1 AND media_id = ANY($2)`
-	rows, err := m.DBClient.DB.Query(query, userID, mediaIDs)
+	rows, err := m.DBClient.DB.Query(query, userID, pq.Array(mediaIDs))
 	if err != nil {
 		return fmt.Errorf("DB query failed: %w", err)
 	}
@@ -65,19 +64,185 @@ func (m *MediaStore) ValidateUserMedia(userID string, mediaIDs []string) error {
 	return nil
 }
 
-func (m *MediaStore) UpdateMediaStatus(mediaIDs []string, status string) error {
+// GetMediaByIDs đọc các media (không sinh URL) để xác nhận upload
+func (m *MediaStore) GetMediaByIDs(mediaIDs []string) ([]model.Media, error) {
 	if len(mediaIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	query := `UPDATE medias SET status=$1 WHERE media_id = ANY($2)`
-	_, err := m.DBClient.DB.Exec(query, status, mediaIDs)
+	query := `
+		SELECT media_id, user_id, media_type, objectkeys3, status,
+			COALESCE(content_type, ''), COALESCE(size_bytes, 0),
+			COALESCE(upload_id, ''), COALESCE(declared_size, 0), created_at
+		FROM medias
+		WHERE media_id = ANY($1)`
+	rows, err := m.DBClient.DB.Query(query, pq.Array(mediaIDs))
 	if err != nil {
-		return fmt.Errorf("failed to update media status: %w", err)
+		return nil, fmt.Errorf("failed to fetch medias: %w", err)
+	}
+	defer rows.Close()
+
+	var medias []model.Media
+	for rows.Next() {
+		var media model.Media
+		if err := rows.Scan(&media.MediaID, &media.UserID, &media.MediaType, &media.Objectkeys3, &media.Status,
+			&media.ContentType, &media.SizeBytes, &media.UploadID, &media.DeclaredSize, &media.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		medias = append(medias, media)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return medias, nil
+}
+
+// LockUploadedMedia chạy trong transaction của PostManager.CreatePost: khoá share các media
+// đã xác nhận để không bị dọn trong lúc gắn vào post, trả về số media khoá được
+func (m *MediaStore) LockUploadedMedia(tx *sql.Tx, mediaIDs []string) (int, error) {
+	query := `SELECT media_id FROM medias WHERE media_id = ANY($1) AND status=$2 FOR SHARE`
+	rows, err := tx.Query(query, pq.Array(mediaIDs), model.MediaUploaded)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock medias: %w", err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("row iteration error: %w", err)
+	}
+	return n, nil
+}
+
+// MarkMediaUploaded lưu metadata của object đã xác nhận, chỉ chuyển từ pending.
+// Trả về false nếu media đã được request khác chuyển trạng thái trước.
+func (m *MediaStore) MarkMediaUploaded(mediaID string, contentType string, size int64) (bool, error) {
+	query := `
+		UPDATE medias SET status=$2, content_type=$3, size_bytes=$4
+		WHERE media_id=$1 AND status=$5`
+	res, err := m.DBClient.DB.Exec(query, mediaID, model.MediaUploaded, contentType, size, model.MediaPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark media %s uploaded: %w", mediaID, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// MarkMediaFailed đánh dấu media không dùng được nữa, chỉ chuyển từ fromStatus
+// (pending khi xác nhận upload, uploaded khi media worker không xử lý được file)
+func (m *MediaStore) MarkMediaFailed(mediaID string, fromStatus string) error {
+	query := `UPDATE medias SET status=$2 WHERE media_id=$1 AND status=$3`
+	if _, err := m.DBClient.DB.Exec(query, mediaID, model.MediaFailed, fromStatus); err != nil {
+		return fmt.Errorf("failed to mark media %s failed: %w", mediaID, err)
 	}
 	return nil
 }
 
+// AbandonedMedia - media pending/failed quá hạn cùng mọi object S3 của nó (gốc + rendition)
+type AbandonedMedia struct {
+	MediaID    string
+	ObjectKeys []string // ObjectKeys[0] là object gốc
+	UploadID   string   // multipart upload có thể còn mở, cần abort để S3 xoá các part
+}
+
+// firstMediaID - cursor bắt đầu của ListAbandonedMedia
+const firstMediaID = "00000000-0000-0000-0000-000000000000"
+
+// ListAbandonedMedia đọc media pending/failed tạo trước cutoff theo thứ tự media_id,
+// afterID là media_id cuối của trang trước (rỗng = từ đầu)
+func (m *MediaStore) ListAbandonedMedia(cutoff time.Time, afterID string, limit int) ([]AbandonedMedia, error) {
+	if afterID == "" {
+		afterID = firstMediaID
+	}
+	query := `
+		SELECT m.media_id, m.objectkeys3, COALESCE(m.upload_id, ''),
+			COALESCE(array_agg(r.objectkeys3) FILTER (WHERE r.objectkeys3 IS NOT NULL), '{}')
+		FROM medias m
+		LEFT JOIN media_renditions r ON r.media_id = m.media_id
+		WHERE m.status IN ($1, $2) AND m.created_at < $3 AND m.media_id > $4
+		GROUP BY m.media_id
+		ORDER BY m.media_id
+		LIMIT $5`
+	rows, err := m.DBClient.DB.Query(query, model.MediaPending, model.MediaFailed, cutoff.UTC(), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list abandoned medias: %w", err)
+	}
+	defer rows.Close()
+
+	var result []AbandonedMedia
+	for rows.Next() {
+		var media AbandonedMedia
+		var original string
+		var renditions []string
+		if err := rows.Scan(&media.MediaID, &original, &media.UploadID, pq.Array(&renditions)); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		media.ObjectKeys = append([]string{original}, renditions...)
+		result = append(result, media)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
+
+// DeleteAbandonedMedia xoá các media vẫn còn pending/failed và tạo trước cutoff (media vừa
+// được xác nhận upload giữa lúc list và xoá thì giữ lại), trả về media_id đã xoá.
+// media_renditions và post_media đi theo ON DELETE CASCADE.
+func (m *MediaStore) DeleteAbandonedMedia(mediaIDs []string, cutoff time.Time) ([]string, error) {
+	query := `
+		DELETE FROM medias
+		WHERE media_id = ANY($1) AND status IN ($2, $3) AND created_at < $4
+		RETURNING media_id`
+	rows, err := m.DBClient.DB.Query(query, pq.Array(mediaIDs), model.MediaPending, model.MediaFailed, cutoff.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete abandoned medias: %w", err)
+	}
+	defer rows.Close()
+
+	var deleted []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan media_id: %w", err)
+		}
+		deleted = append(deleted, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return deleted, nil
+}
+
+// ReferencedObjectKeys trả về các key trong objectKeys còn được medias hoặc media_renditions trỏ tới
+func (m *MediaStore) ReferencedObjectKeys(objectKeys []string) (map[string]bool, error) {
+	query := `
+		SELECT objectkeys3 FROM medias WHERE objectkeys3 = ANY($1)
+		UNION
+		SELECT objectkeys3 FROM media_renditions WHERE objectkeys3 = ANY($1)`
+	rows, err := m.DBClient.DB.Query(query, pq.Array(objectKeys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check object keys: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan object key: %w", err)
+		}
+		referenced[key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return referenced, nil
+}
+
 func (s *MediaStore) CreateMediaRecord(mediaID string, userID string, mediaType string, mediaStatus string, objectkeys3 string) (model.Media, error) {
 
 	query := `
@@ -108,6 +273,18 @@ func (s *MediaStore) CreateMediaRecord(mediaID string, userID string, mediaType
 	return media, nil
 }
 
+// CreateMultipartMediaRecord ghi media pending gắn với multipart upload đã tạo trên S3
+func (s *MediaStore) CreateMultipartMediaRecord(mediaID, userID, mediaType, objectkeys3, uploadID string, declaredSize int64) error {
+	query := `
+		INSERT INTO medias (media_id, user_id, media_type, objectkeys3, status, upload_id, declared_size, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	_, err := s.DBClient.DB.Exec(query, mediaID, userID, mediaType, objectkeys3, model.MediaPending, uploadID, declaredSize)
+	if err != nil {
+		return fmt.Errorf("failed to insert media: %w", err)
+	}
+	return nil
+}
+
 func (s *MediaStore) GetMediaByID(mID string) (model.Media, error) {
 	query := `
 		SELECT media_id, user_id, media_type, objectkeys3, status, created_at
@@ -133,9 +310,13 @@ func (s *MediaStore) GetMediaByID(mID string) (model.Media, error) {
 		return model.Media{}, fmt.Errorf("failed to get media by id %s: %w", mID, err)
 	}
 
-	if objectkey.Valid {
-		// 🔑 Generate a temporary pre-signed URL (e.g., 15m)
-		media.URL = s.S3client.GeneratePreSignedGetURL(objectkey.String, 15*time.Minute)
+	if objectkey.Valid && s.URLSigner != nil {
+		// 🔑 URL ổn định trong bucket thời gian (cache Redis) để browser/CDN cache hit
+		url, ok := s.URLSigner.URLs(context.Background(), []string{objectkey.String})[objectkey.String]
+		if !ok {
+			return model.Media{}, fmt.Errorf("failed to sign url for media %s", mID)
+		}
+		media.URL = url
 	}
 
 	// Optional: fill defaults for non-persistent fields
@@ -143,3 +324,40 @@ func (s *MediaStore) GetMediaByID(mID string) (model.Media, error) {
 
 	return media, nil
 }
+
+// GetMediaByPostIDs đọc media của nhiều post trong 1 query (join post_media),
+// kết quả theo post_id. Không sinh URL: object gốc còn metadata, chỉ serve rendition.
+func (s *MediaStore) GetMediaByPostIDs(postIDs []string) (map[string][]model.Media, error) {
+	result := make(map[string][]model.Media, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT pm.post_id, m.media_id, m.user_id, m.media_type, m.objectkeys3, m.status, m.created_at
+		FROM post_media pm
+		JOIN medias m ON m.media_id = pm.media_id
+		WHERE pm.post_id = ANY($1)
+		ORDER BY m.created_at, m.media_id`
+
+	rows, err := s.DBClient.DB.Query(query, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch media of posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var media model.Media
+		var objectkey sql.NullString
+		if err := rows.Scan(&postID, &media.MediaID, &media.UserID, &media.MediaType, &objectkey, &media.Status, &media.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan media: %w", err)
+		}
+		media.Objectkeys3 = objectkey
+		result[postID] = append(result[postID], media)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
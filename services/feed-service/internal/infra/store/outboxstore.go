@@ -0,0 +1,95 @@
+package store
+
+import (
+	"database/sql"
+	dbclient "feedservice/internal/infra/postgresclient"
+	"feedservice/internal/model"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type OutboxStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+func NewOutboxStore(postgrescfg *PostGresConfig) *OutboxStore {
+	outboxStore := &OutboxStore{}
+	outboxStore.DBClient = dbclient.NewPostgresClient(postgrescfg.Host, postgrescfg.Port, postgrescfg.User, postgrescfg.Password, postgrescfg.DBname)
+	return outboxStore
+}
+
+// InsertEvent ghi event trong cùng transaction với thay đổi dữ liệu
+func (o *OutboxStore) InsertEvent(tx *sql.Tx, aggregateID string, eventType string, payload []byte) error {
+	query := `INSERT INTO outbox (aggregate_id, event_type, payload, created_at) VALUES ($1, $2, $3, now())`
+	if _, err := tx.Exec(query, aggregateID, eventType, payload); err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// RelayPending lock tối đa limit event chưa gửi (SKIP LOCKED để chạy được nhiều relay song song),
+// gọi publish theo thứ tự id và đánh dấu delivered cho những event publish thành công.
+// Dừng ở event lỗi đầu tiên để giữ thứ tự.
+func (o *OutboxStore) RelayPending(limit int, publish func(event model.OutboxEvent) error) (int, error) {
+	tx, err := o.DBClient.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, aggregate_id, event_type, payload, created_at
+		FROM outbox
+		WHERE delivered_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch outbox events: %w", err)
+	}
+
+	var events []model.OutboxEvent
+	for rows.Next() {
+		var e model.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.AggregateID, &e.EventType, &e.Payload, &e.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	delivered := []int64{}
+	var publishErr error
+	for _, e := range events {
+		if publishErr = publish(e); publishErr != nil {
+			break
+		}
+		delivered = append(delivered, e.ID)
+	}
+
+	if len(delivered) > 0 {
+		if _, err := tx.Exec(`UPDATE outbox SET delivered_at = now() WHERE id = ANY($1)`, pq.Array(delivered)); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox events delivered: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox relay: %w", err)
+	}
+	return len(delivered), publishErr
+}
+
+// PurgeDelivered xoá event đã gửi lâu hơn retention
+func (o *OutboxStore) PurgeDelivered(retention time.Duration) (int64, error) {
+	result, err := o.DBClient.DB.Exec(`DELETE FROM outbox WHERE delivered_at IS NOT NULL AND delivered_at < $1`, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -1,9 +1,13 @@
 package store
 
 import (
+	"database/sql"
+	"errors"
 	dbclient "feedservice/internal/infra/postgresclient"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type PostStore struct {
@@ -16,32 +20,293 @@ func NewPostStore(postgrescfg *PostGresConfig) *PostStore {
 	return mediaStore
 }
 
+var ErrPostNotFound = errors.New("post not found")
+
 type Post struct {
-	ID        string
-	UserID    string
-	Content   string
-	CreatedAt time.Time
+	ID         string
+	UserID     string
+	Content    string
+	Visibility string
+	// SharedPostID - post gốc nếu đây là bài share, rỗng với post thường
+	SharedPostID string
+	CreatedAt    time.Time
+	UpdatedAt    *time.Time
 }
 
-func (p *PostStore) InsertPost(postID, userID, content string) error {
-	query := `INSERT INTO posts (post_id, user_id, content, created_at) VALUES ($1, $2, $3, now())`
-	_, err := p.DBClient.DB.Exec(query, postID, userID, content)
+// InsertPost chạy trong transaction của PostManager.CreatePost/SharePost, sharedPostID rỗng = post thường.
+// createdAt là giá trị dùng cho score fan-out, post_hashtags và trending nên phải lưu đúng giá trị đó (UTC).
+func (p *PostStore) InsertPost(tx *sql.Tx, postID, userID, content, visibility, sharedPostID string, createdAt time.Time) error {
+	query := `INSERT INTO posts (post_id, user_id, content, visibility, shared_post_id, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := tx.Exec(query, postID, userID, content, visibility, sql.NullString{String: sharedPostID, Valid: sharedPostID != ""}, createdAt.UTC())
 	if err != nil {
 		return fmt.Errorf("failed to insert post: %w", err)
 	}
 	return nil
 }
 
+// GetPostByID không trả về post đã xoá
 func (p *PostStore) GetPostByID(postID string) (*Post, error) {
-	query := `SELECT post_id, user_id, content, created_at FROM posts WHERE post_id = $1`
+	query := `SELECT post_id, user_id, content, visibility, shared_post_id, created_at, updated_at FROM posts WHERE post_id = $1 AND is_deleted = FALSE`
 
 	row := p.DBClient.DB.QueryRow(query, postID)
 
 	post := &Post{}
-	err := row.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt)
+	var sharedPostID sql.NullString
+	var updatedAt sql.NullTime
+	err := row.Scan(&post.ID, &post.UserID, &post.Content, &post.Visibility, &sharedPostID, &post.CreatedAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch post %s: %w", postID, err)
 	}
+	post.SharedPostID = sharedPostID.String
+	if updatedAt.Valid {
+		post.UpdatedAt = &updatedAt.Time
+	}
 
 	return post, nil
 }
+
+// GetPostsByIDs đọc nhiều post trong 1 query, post không tồn tại/đã xoá thì không có trong map
+func (p *PostStore) GetPostsByIDs(postIDs []string) (map[string]Post, error) {
+	result := make(map[string]Post, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT post_id, user_id, content, visibility, shared_post_id, created_at, updated_at
+		FROM posts
+		WHERE post_id = ANY($1) AND is_deleted = FALSE`
+
+	rows, err := p.DBClient.DB.Query(query, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %w", err)
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		result[post.ID] = post
+	}
+	return result, nil
+}
+
+// CountShares đếm bài share chưa xoá của từng post trong postIDs, post không có share thì không có trong map
+func (p *PostStore) CountShares(postIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT shared_post_id, count(*)
+		FROM posts
+		WHERE shared_post_id = ANY($1) AND is_deleted = FALSE
+		GROUP BY shared_post_id`
+
+	rows, err := p.DBClient.DB.Query(query, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count shares: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var n int64
+		if err := rows.Scan(&postID, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan share count: %w", err)
+		}
+		counts[postID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return counts, nil
+}
+
+// LockPost đọc và khoá row post (FOR UPDATE) trong transaction sửa/xoá
+func (p *PostStore) LockPost(tx *sql.Tx, postID string) (*Post, error) {
+	query := `SELECT post_id, user_id, content, visibility, shared_post_id, created_at, updated_at FROM posts WHERE post_id = $1 AND is_deleted = FALSE FOR UPDATE`
+
+	post := &Post{}
+	var sharedPostID sql.NullString
+	var updatedAt sql.NullTime
+	err := tx.QueryRow(query, postID).Scan(&post.ID, &post.UserID, &post.Content, &post.Visibility, &sharedPostID, &post.CreatedAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock post %s: %w", postID, err)
+	}
+	post.SharedPostID = sharedPostID.String
+	if updatedAt.Valid {
+		post.UpdatedAt = &updatedAt.Time
+	}
+	return post, nil
+}
+
+// UpdatePostContent lưu content hiện tại vào post_revisions rồi ghi content mới,
+// chạy trong transaction sau LockPost
+func (p *PostStore) UpdatePostContent(tx *sql.Tx, post *Post, content string) error {
+	writtenAt := post.CreatedAt
+	if post.UpdatedAt != nil {
+		writtenAt = *post.UpdatedAt
+	}
+
+	revisionQuery := `INSERT INTO post_revisions (post_id, content, created_at, replaced_at) VALUES ($1, $2, $3, now())`
+	if _, err := tx.Exec(revisionQuery, post.ID, post.Content, writtenAt); err != nil {
+		return fmt.Errorf("failed to save revision of post %s: %w", post.ID, err)
+	}
+
+	query := `UPDATE posts SET content = $2, updated_at = now() WHERE post_id = $1`
+	if _, err := tx.Exec(query, post.ID, content); err != nil {
+		return fmt.Errorf("failed to update post %s: %w", post.ID, err)
+	}
+	return nil
+}
+
+// UpdateVisibility đổi audience của post, chạy trong transaction sau LockPost
+func (p *PostStore) UpdateVisibility(tx *sql.Tx, postID, visibility string) error {
+	query := `UPDATE posts SET visibility = $2 WHERE post_id = $1`
+	if _, err := tx.Exec(query, postID, visibility); err != nil {
+		return fmt.Errorf("failed to update visibility of post %s: %w", postID, err)
+	}
+	return nil
+}
+
+// SoftDeletePost đánh dấu is_deleted, mọi query đọc post đều bỏ qua post đã xoá.
+// Row thật bị xoá bởi PurgeDeleted sau retention.
+func (p *PostStore) SoftDeletePost(tx *sql.Tx, postID string) error {
+	query := `UPDATE posts SET is_deleted = TRUE, deleted_at = now() WHERE post_id = $1 AND is_deleted = FALSE`
+	res, err := tx.Exec(query, postID)
+	if err != nil {
+		return fmt.Errorf("failed to delete post %s: %w", postID, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted xoá hẳn tối đa limit post đã soft delete quá retention
+// (post_media, post_revisions bị xoá theo ON DELETE CASCADE)
+func (p *PostStore) PurgeDeleted(retention time.Duration, limit int) (int64, error) {
+	query := `
+		DELETE FROM posts
+		WHERE post_id IN (
+			SELECT post_id FROM posts
+			WHERE is_deleted = TRUE AND deleted_at < $1
+			LIMIT $2
+		)`
+	res, err := p.DBClient.DB.Exec(query, time.Now().Add(-retention), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted posts: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+type PostRevision struct {
+	RevisionID int64     `json:"revision_id"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// GetRevisions trả về các phiên bản cũ của post, mới nhất trước
+func (p *PostStore) GetRevisions(postID string) ([]PostRevision, error) {
+	query := `
+		SELECT revision_id, content, created_at, replaced_at
+		FROM post_revisions
+		WHERE post_id = $1
+		ORDER BY revision_id DESC`
+
+	rows, err := p.DBClient.DB.Query(query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revisions of post %s: %w", postID, err)
+	}
+	defer rows.Close()
+
+	revisions := []PostRevision{}
+	for rows.Next() {
+		var r PostRevision
+		if err := rows.Scan(&r.RevisionID, &r.Content, &r.CreatedAt, &r.ReplacedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetRecentPostsByUsers lấy limit post mới nhất của các user mà audience được xem
+// (rebuild feed đã expire/bị trim, trang post của 1 user)
+func (p *PostStore) GetRecentPostsByUsers(userIDs []string, audience Audience, limit int64) ([]Post, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := p.DBClient.DB.Query(postsByUsersQuery(audience, false), pq.Array(userIDs), limit, audience.ViewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent posts: %w", err)
+	}
+	return scanPosts(rows)
+}
+
+// GetPostsByUsersBefore lấy limit post của các user đứng sau (beforeUnix, beforeID)
+// theo đúng thứ tự feed trong Redis: score = created_at tính bằng giây, tie thì post_id giảm dần
+func (p *PostStore) GetPostsByUsersBefore(userIDs []string, audience Audience, beforeUnix int64, beforeID string, limit int64) ([]Post, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := p.DBClient.DB.Query(postsByUsersQuery(audience, true), pq.Array(userIDs), limit, audience.ViewerID, beforeUnix, beforeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts before %s: %w", beforeID, err)
+	}
+	return scanPosts(rows)
+}
+
+// postsByUsersQuery - trang đầu và trang sau cursor dùng chung 1 query để cùng thứ tự feedOrder.
+// $1 user ids, $2 limit, $3 viewer, $4 $5 cursor nếu bounded
+func postsByUsersQuery(audience Audience, bounded bool) string {
+	order := newFeedOrder("posts")
+	where := "user_id = ANY($1) AND is_deleted = FALSE AND " + audience.filter("posts", 3)
+	if bounded {
+		where += " AND " + order.before(4)
+	}
+	return `
+		SELECT post_id, user_id, content, visibility, shared_post_id, created_at, updated_at
+		FROM posts
+		WHERE ` + where + `
+		ORDER BY ` + order.by + `
+		LIMIT $2`
+}
+
+func scanPosts(rows *sql.Rows) ([]Post, error) {
+	defer rows.Close()
+
+	var posts []Post
+	for rows.Next() {
+		var post Post
+		var sharedPostID sql.NullString
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.Visibility, &sharedPostID, &post.CreatedAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		post.SharedPostID = sharedPostID.String
+		if updatedAt.Valid {
+			post.UpdatedAt = &updatedAt.Time
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return posts, nil
+}
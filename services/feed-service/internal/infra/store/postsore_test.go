@@ -0,0 +1,130 @@
+package store
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// orderBy lấy phần ORDER BY của query
+func orderBy(t *testing.T, query string) string {
+	t.Helper()
+	i := strings.Index(query, "ORDER BY ")
+	j := strings.Index(query, "LIMIT")
+	if i < 0 || j < i {
+		t.Fatalf("query has no ORDER BY ... LIMIT: %s", query)
+	}
+	return strings.TrimSpace(query[i+len("ORDER BY ") : j])
+}
+
+func TestPostsByUsersQuerySameOrder(t *testing.T) {
+	for _, audience := range []Audience{{}, {ViewerID: "v1", Follower: true}} {
+		first := postsByUsersQuery(audience, false)
+		next := postsByUsersQuery(audience, true)
+
+		want := "floor(extract(epoch FROM posts.created_at))::bigint DESC, posts.post_id::text DESC"
+		if got := orderBy(t, first); got != want {
+			t.Errorf("first page ORDER BY %q, want %q", got, want)
+		}
+		if got := orderBy(t, next); got != want {
+			t.Errorf("cursor page ORDER BY %q, want %q", got, want)
+		}
+		if strings.Contains(first, "$4") {
+			t.Errorf("first page should not be bounded: %s", first)
+		}
+		bound := "(floor(extract(epoch FROM posts.created_at))::bigint, posts.post_id::text) < ($4, $5)"
+		if !strings.Contains(next, bound) {
+			t.Errorf("cursor page missing bound %q: %s", bound, next)
+		}
+	}
+}
+
+type feedCursor struct {
+	unix   int64
+	postID string
+}
+
+// sqlPage làm đúng việc query làm: lọc (giây, post_id) < cursor, sort theo feedOrder, lấy limit
+func sqlPage(posts []Post, cursor *feedCursor, limit int) []Post {
+	less := func(a, b feedCursor) bool {
+		return a.unix < b.unix || (a.unix == b.unix && a.postID < b.postID)
+	}
+	key := func(p Post) feedCursor { return feedCursor{p.CreatedAt.Unix(), p.ID} }
+
+	var page []Post
+	for _, p := range posts {
+		if cursor == nil || less(key(p), *cursor) {
+			page = append(page, p)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool { return less(key(page[j]), key(page[i])) })
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page
+}
+
+func TestPagingPostsInSameSecond(t *testing.T) {
+	second := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	// cùng 1 giây, post tạo sau có post_id nhỏ hơn: thứ tự created_at thật khác thứ tự feed
+	posts := []Post{
+		{ID: "a0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(900 * time.Millisecond)},
+		{ID: "e0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(100 * time.Millisecond)},
+		{ID: "c0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(500 * time.Millisecond)},
+		{ID: "b0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(700 * time.Millisecond)},
+		{ID: "d0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(300 * time.Millisecond)},
+		{ID: "f0000000-0000-4000-8000-000000000000", CreatedAt: second.Add(-time.Second)},
+		{ID: "10000000-0000-4000-8000-000000000000", CreatedAt: second.Add(time.Second)},
+	}
+	// thứ tự ZREVRANGE của feed trong Redis: score giảm dần, cùng score thì member giảm dần
+	want := []string{
+		"10000000-0000-4000-8000-000000000000",
+		"e0000000-0000-4000-8000-000000000000",
+		"d0000000-0000-4000-8000-000000000000",
+		"c0000000-0000-4000-8000-000000000000",
+		"b0000000-0000-4000-8000-000000000000",
+		"a0000000-0000-4000-8000-000000000000",
+		"f0000000-0000-4000-8000-000000000000",
+	}
+
+	for limit := 1; limit <= len(posts); limit++ {
+		var got []string
+		var cursor *feedCursor
+		for {
+			page := sqlPage(posts, cursor, limit)
+			for _, p := range page {
+				got = append(got, p.ID)
+			}
+			if len(page) < limit {
+				break
+			}
+			// cursor giống scrollingfeedmanager: score = CreatedAt.Unix() của post cuối trang
+			last := page[len(page)-1]
+			cursor = &feedCursor{last.CreatedAt.Unix(), last.ID}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("limit %d: paged = %v, want %v", limit, got, want)
+		}
+	}
+}
+
+func TestPostsByHashtagQuerySameOrder(t *testing.T) {
+	audience := Audience{ViewerID: "v1"}
+	first := postsByHashtagQuery(audience, false)
+	next := postsByHashtagQuery(audience, true)
+
+	// cursor của trang hashtag tạo từ posts.created_at nên key phải theo bảng posts
+	want := "floor(extract(epoch FROM p.created_at))::bigint DESC, p.post_id::text DESC"
+	if got := orderBy(t, first); got != want {
+		t.Errorf("first page ORDER BY %q, want %q", got, want)
+	}
+	if got := orderBy(t, next); got != want {
+		t.Errorf("cursor page ORDER BY %q, want %q", got, want)
+	}
+	bound := "(floor(extract(epoch FROM p.created_at))::bigint, p.post_id::text) < ($4, $5)"
+	if strings.Contains(first, "$4") || !strings.Contains(next, bound) {
+		t.Errorf("bound %q must only be in the cursor page:\n%s\n%s", bound, first, next)
+	}
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	dbclient "feedservice/internal/infra/postgresclient"
+	"feedservice/internal/model"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type RenditionStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+func NewRenditionStore(postgrescfg *PostGresConfig) *RenditionStore {
+	renditionStore := &RenditionStore{}
+	renditionStore.DBClient = dbclient.NewPostgresClient(postgrescfg.Host, postgrescfg.Port, postgrescfg.User, postgrescfg.Password, postgrescfg.DBname)
+	return renditionStore
+}
+
+// ReplaceRenditions ghi đè toàn bộ rendition của media (worker retry/xử lý lại không bị trùng)
+func (r *RenditionStore) ReplaceRenditions(mediaID string, renditions []model.Rendition) error {
+	tx, err := r.DBClient.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM media_renditions WHERE media_id=$1`, mediaID); err != nil {
+		return fmt.Errorf("failed to clear renditions of %s: %w", mediaID, err)
+	}
+
+	query := `
+		INSERT INTO media_renditions (media_id, rendition, objectkeys3, content_type, width, height, size_bytes)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7)`
+	for _, rd := range renditions {
+		if _, err := tx.Exec(query, mediaID, rd.Name, rd.Objectkeys3, rd.ContentType, rd.Width, rd.Height, rd.SizeBytes); err != nil {
+			return fmt.Errorf("failed to insert rendition %s of %s: %w", rd.Name, mediaID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit renditions of %s: %w", mediaID, err)
+	}
+	return nil
+}
+
+// GetRenditionsByMediaIDs đọc rendition của nhiều media trong 1 query, kết quả theo media_id
+func (r *RenditionStore) GetRenditionsByMediaIDs(mediaIDs []string) (map[string][]model.Rendition, error) {
+	result := make(map[string][]model.Rendition, len(mediaIDs))
+	if len(mediaIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT media_id, rendition, objectkeys3, content_type, COALESCE(width, 0), COALESCE(height, 0), size_bytes
+		FROM media_renditions
+		WHERE media_id = ANY($1)`
+	rows, err := r.DBClient.DB.Query(query, pq.Array(mediaIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch renditions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rd model.Rendition
+		if err := rows.Scan(&rd.MediaID, &rd.Name, &rd.Objectkeys3, &rd.ContentType, &rd.Width, &rd.Height, &rd.SizeBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan rendition: %w", err)
+		}
+		result[rd.MediaID] = append(result[rd.MediaID], rd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"database/sql"
+	dbclient "feedservice/internal/infra/postgresclient"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TagStore - hashtag và mention của post (post_hashtags, post_mentions)
+type TagStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+func NewTagStore(postgrescfg *PostGresConfig) *TagStore {
+	tagStore := &TagStore{}
+	tagStore.DBClient = dbclient.NewPostgresClient(postgrescfg.Host, postgrescfg.Port, postgrescfg.User, postgrescfg.Password, postgrescfg.DBname)
+	return tagStore
+}
+
+// Mention - username đã resolve sang user_id qua user-service
+type Mention struct {
+	UserID   string
+	Username string
+}
+
+// ReplaceHashtags đặt hashtag của post thành tags (chạy trong transaction tạo/sửa/xoá post),
+// trả về hashtag mới thêm và hashtag bị bỏ so với trước để cập nhật trending
+func (t *TagStore) ReplaceHashtags(tx *sql.Tx, postID string, tags []string, createdAt time.Time) (added, removed []string, err error) {
+	current, err := queryStrings(tx, `SELECT hashtag FROM post_hashtags WHERE post_id = $1`, postID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read hashtags of post %s: %w", postID, err)
+	}
+	added, removed = diffStrings(current, tags)
+
+	if len(removed) > 0 {
+		query := `DELETE FROM post_hashtags WHERE post_id = $1 AND hashtag = ANY($2)`
+		if _, err := tx.Exec(query, postID, pq.Array(removed)); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove hashtags of post %s: %w", postID, err)
+		}
+	}
+	if len(added) > 0 {
+		query := `INSERT INTO post_hashtags (post_id, hashtag, created_at) SELECT $1, unnest($2::text[]), $3`
+		if _, err := tx.Exec(query, postID, pq.Array(added), createdAt.UTC()); err != nil {
+			return nil, nil, fmt.Errorf("failed to insert hashtags of post %s: %w", postID, err)
+		}
+	}
+	return added, removed, nil
+}
+
+// GetHashtags - hashtag hiện tại của post, đọc trong transaction sửa post
+func (t *TagStore) GetHashtags(tx *sql.Tx, postID string) ([]string, error) {
+	tags, err := queryStrings(tx, `SELECT hashtag FROM post_hashtags WHERE post_id = $1`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hashtags of post %s: %w", postID, err)
+	}
+	return tags, nil
+}
+
+// ReplaceMentions đặt mention của post thành mentions, trả về user_id mới được mention
+// (sửa post không báo lại cho người đã được mention trước đó)
+func (t *TagStore) ReplaceMentions(tx *sql.Tx, postID string, mentions []Mention) (added []string, err error) {
+	current, err := queryStrings(tx, `SELECT user_id::text FROM post_mentions WHERE post_id = $1`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mentions of post %s: %w", postID, err)
+	}
+	userIDs := make([]string, 0, len(mentions))
+	usernames := make(map[string]string, len(mentions))
+	for _, m := range mentions {
+		userIDs = append(userIDs, m.UserID)
+		usernames[m.UserID] = m.Username
+	}
+	added, removed := diffStrings(current, userIDs)
+
+	if len(removed) > 0 {
+		query := `DELETE FROM post_mentions WHERE post_id = $1 AND user_id = ANY($2)`
+		if _, err := tx.Exec(query, postID, pq.Array(removed)); err != nil {
+			return nil, fmt.Errorf("failed to remove mentions of post %s: %w", postID, err)
+		}
+	}
+	query := `INSERT INTO post_mentions (post_id, user_id, username) VALUES ($1, $2, $3)`
+	for _, userID := range added {
+		if _, err := tx.Exec(query, postID, userID, usernames[userID]); err != nil {
+			return nil, fmt.Errorf("failed to insert mention of %s in post %s: %w", userID, postID, err)
+		}
+	}
+	return added, nil
+}
+
+// GetRecentPostsByHashtag lấy limit post mới nhất có hashtag mà audience được xem (trang đầu)
+func (t *TagStore) GetRecentPostsByHashtag(tag string, audience Audience, limit int64) ([]Post, error) {
+	rows, err := t.DBClient.DB.Query(postsByHashtagQuery(audience, false), tag, limit, audience.ViewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts of #%s: %w", tag, err)
+	}
+	return scanPosts(rows)
+}
+
+// GetPostsByHashtagBefore lấy limit post có hashtag đứng sau (beforeUnix, beforeID),
+// cùng thứ tự và format cursor với GetPostsByUsersBefore
+func (t *TagStore) GetPostsByHashtagBefore(tag string, audience Audience, beforeUnix int64, beforeID string, limit int64) ([]Post, error) {
+	rows, err := t.DBClient.DB.Query(postsByHashtagQuery(audience, true), tag, limit, audience.ViewerID, beforeUnix, beforeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts of #%s before %s: %w", tag, beforeID, err)
+	}
+	return scanPosts(rows)
+}
+
+// postsByHashtagQuery - trang đầu và trang sau cursor dùng chung 1 query để cùng thứ tự feedOrder.
+// Key lấy theo p.created_at vì cursor được tạo từ Post.CreatedAt.
+// $1 hashtag, $2 limit, $3 viewer, $4 $5 cursor nếu bounded
+func postsByHashtagQuery(audience Audience, bounded bool) string {
+	order := newFeedOrder("p")
+	where := "h.hashtag = $1 AND p.is_deleted = FALSE AND " + audience.filter("p", 3)
+	if bounded {
+		where += " AND " + order.before(4)
+	}
+	return `
+		SELECT p.post_id, p.user_id, p.content, p.visibility, p.shared_post_id, p.created_at, p.updated_at
+		FROM post_hashtags h
+		JOIN posts p ON p.post_id = h.post_id
+		WHERE ` + where + `
+		ORDER BY ` + order.by + `
+		LIMIT $2`
+}
+
+func queryStrings(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// diffStrings - phần tử có trong next mà không có trong current (added) và ngược lại (removed)
+func diffStrings(current, next []string) (added, removed []string) {
+	inCurrent := make(map[string]bool, len(current))
+	for _, v := range current {
+		inCurrent[v] = true
+	}
+	inNext := make(map[string]bool, len(next))
+	for _, v := range next {
+		inNext[v] = true
+		if !inCurrent[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range current {
+		if !inNext[v] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
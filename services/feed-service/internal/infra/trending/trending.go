@@ -0,0 +1,152 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"feedservice/internal/infra/redisclient"
+	"feedservice/internal/model"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrInvalidWindow = errors.New("unsupported trending window")
+
+type TrendingConfig struct {
+	Bucket   time.Duration   // độ mịn của sliding window, mỗi bucket là 1 ZSET hashtags:bucket:{start}
+	Windows  []time.Duration // các window cho phép (bội số của Bucket), bucket giữ đến hết window dài nhất
+	CacheTTL time.Duration   // kết quả ZUNIONSTORE của 1 window được dùng lại trong CacheTTL
+}
+
+// removeScript giảm count của các hashtag trong 1 bucket, về 0 thì ZREM.
+// Bucket đã expire thì bỏ qua (không tạo lại key không có TTL).
+//
+//	KEYS[1] = hashtags:bucket:{start}, ARGV = hashtag, ...
+var removeScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+for i = 1, #ARGV do
+	if tonumber(redis.call('ZINCRBY', KEYS[1], -1, ARGV[i])) <= 0 then
+		redis.call('ZREM', KEYS[1], ARGV[i])
+	end
+end
+return 1
+`)
+
+// HashtagTrends đếm số post theo hashtag trong từng bucket thời gian (theo created_at của post),
+// trending của 1 window = tổng các bucket nằm trong window
+type HashtagTrends struct {
+	redisclient *redisclient.RedisClient
+	cfg         TrendingConfig
+	retention   time.Duration
+}
+
+type HashtagCount struct {
+	Hashtag string `json:"hashtag"`
+	Posts   int64  `json:"posts"`
+}
+
+func NewHashtagTrends(redisclient_ *redisclient.RedisClient, cfg TrendingConfig) *HashtagTrends {
+	var retention time.Duration
+	for _, w := range cfg.Windows {
+		retention = max(retention, w)
+	}
+	return &HashtagTrends{
+		redisclient: redisclient_,
+		cfg:         cfg,
+		retention:   retention + cfg.Bucket,
+	}
+}
+
+// Add cộng 1 cho mỗi hashtag vào bucket chứa createdAt; post cũ hơn window dài nhất thì bỏ qua
+func (t *HashtagTrends) Add(ctx context.Context, createdAt time.Time, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	start := createdAt.Truncate(t.cfg.Bucket)
+	expireAt := start.Add(t.retention)
+	if !expireAt.After(time.Now()) {
+		return nil
+	}
+
+	key := model.HashtagBucketKey(start.Unix())
+	pipe := t.redisclient.GetClient().TxPipeline()
+	for _, tag := range tags {
+		pipe.ZIncrBy(ctx, key, 1, tag)
+	}
+	pipe.ExpireAt(ctx, key, expireAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count hashtags: %w", err)
+	}
+	return nil
+}
+
+// Remove trừ lại khi post bị sửa bỏ hashtag hoặc bị xoá
+func (t *HashtagTrends) Remove(ctx context.Context, createdAt time.Time, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	key := model.HashtagBucketKey(createdAt.Truncate(t.cfg.Bucket).Unix())
+	args := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+	if err := removeScript.Run(ctx, t.redisclient.GetClient(), []string{key}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to uncount hashtags: %w", err)
+	}
+	return nil
+}
+
+// Top trả về limit hashtag có nhiều post nhất trong window gần nhất
+func (t *HashtagTrends) Top(ctx context.Context, window time.Duration, limit int64) ([]HashtagCount, error) {
+	if !t.allowed(window) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWindow, window)
+	}
+
+	client := t.redisclient.GetClient()
+	key := model.TrendingHashtagsKey(window)
+	exists, err := client.Exists(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trending hashtags: %w", err)
+	}
+	if exists == 0 {
+		pipe := client.TxPipeline()
+		pipe.ZUnionStore(ctx, key, &redis.ZStore{Keys: t.bucketKeys(time.Now(), window), Aggregate: "SUM"})
+		pipe.Expire(ctx, key, t.cfg.CacheTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to compute trending hashtags: %w", err)
+		}
+	}
+
+	zs, err := client.ZRevRangeWithScores(ctx, key, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trending hashtags: %w", err)
+	}
+	result := make([]HashtagCount, 0, len(zs))
+	for _, z := range zs {
+		result = append(result, HashtagCount{Hashtag: z.Member.(string), Posts: int64(z.Score)})
+	}
+	return result, nil
+}
+
+func (t *HashtagTrends) allowed(window time.Duration) bool {
+	for _, w := range t.cfg.Windows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketKeys - bucket hiện tại (đang đếm dở) và các bucket trước đó, tổng cộng window/Bucket bucket
+func (t *HashtagTrends) bucketKeys(now time.Time, window time.Duration) []string {
+	start := now.Truncate(t.cfg.Bucket)
+	n := int(window / t.cfg.Bucket)
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, model.HashtagBucketKey(start.Add(-time.Duration(i)*t.cfg.Bucket).Unix()))
+	}
+	return keys
+}
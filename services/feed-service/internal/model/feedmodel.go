@@ -2,6 +2,8 @@ package model
 
 import (
 	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,10 +16,203 @@ type Media struct {
 	Objectkeys3   sql.NullString `json:"Objectkeys3"`
 	URL           string         `json:"url"`
 	Status        string         `json:"status"`
+	ContentType   string         `json:"content_type,omitempty"`
+	SizeBytes     int64          `json:"size_bytes,omitempty"`
+	UploadID      string         `json:"-"` // multipart upload, rỗng = presigned PUT/POST
+	DeclaredSize  int64          `json:"-"` // size client khai báo khi tạo multipart upload
 	CreatedAt     time.Time      `json:"created_at"`
 }
 
+// MediaUpload - thông tin client cần để upload 1 media vừa tạo
+type MediaUpload struct {
+	MediaID string
+	// presigned PUT, client phải gửi kèm UploadHeaders (nằm trong chữ ký)
+	UploadURL     string
+	UploadHeaders map[string]string
+	// form POST policy, chỉ có khi client khai báo content_type: S3 chặn sai loại/quá cỡ ngay lúc upload
+	UploadForm *UploadForm
+	// multipart upload cho file lớn, khi có thì không có UploadURL/UploadForm
+	Multipart *MultipartUpload
+}
+
+// MultipartUpload - client cắt file thành PartCount part cỡ PartSize (part cuối nhỏ hơn),
+// PUT từng part lên URL tương ứng, giữ ETag trả về rồi gọi complete
+type MultipartUpload struct {
+	MediaID   string       `json:"media_id"`
+	PartSize  int64        `json:"part_size"`
+	PartCount int32        `json:"part_count"`
+	ExpiresAt time.Time    `json:"expires_at"` // hạn các URL part, hết hạn thì lấy lại qua GET /media/{id}/parts
+	Parts     []UploadPart `json:"parts"`      // part chưa upload, có URL
+	Uploaded  []UploadPart `json:"uploaded_parts,omitempty"`
+}
+
+type UploadPart struct {
+	PartNumber int32  `json:"part_number"`
+	URL        string `json:"url,omitempty"`
+	ETag       string `json:"etag,omitempty"`
+	Size       int64  `json:"size,omitempty"`
+}
+
+type UploadForm struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
+// ---- Media status (medias.status) ----
+const (
+	MediaPending  = "pending"  // đã cấp presigned URL, chưa xác nhận object trên S3
+	MediaUploaded = "uploaded" // HeadObject thấy object đúng loại/kích thước
+	MediaFailed   = "failed"   // object sai loại/kích thước hoặc không upload trong thời hạn URL
+)
+
+// MediaContentTypes - Content-Type client được phép PUT cho từng media_type
+var MediaContentTypes = map[string][]string{
+	"image": {"image/jpeg", "image/png", "image/gif", "image/webp"},
+	"video": {"video/mp4", "video/quicktime", "video/webm"},
+}
+
+// IsAllowedContentType - contentType (bỏ qua tham số "; charset=...") có khớp mediaType không
+func IsAllowedContentType(mediaType, contentType string) bool {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, allowed := range MediaContentTypes[mediaType] {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ---- Media renditions (media_renditions) ----
+const (
+	RenditionThumb  = "thumb"
+	RenditionMedium = "medium"
+	RenditionFull   = "full"   // bản đã strip metadata, thay cho object gốc khi serve
+	RenditionPoster = "poster" // frame đầu của video
+)
+
+// Rendition - 1 bản dẫn xuất của media, lưu ở object key riêng
+type Rendition struct {
+	MediaID     string `json:"media_id"`
+	Name        string `json:"name"`
+	Objectkeys3 string `json:"-"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// RenditionKey - object key của rendition, cùng prefix user với object gốc
+func RenditionKey(userID, mediaID, name, ext string) string {
+	return "renditions/" + userID + "/" + mediaID + "/" + name + ext
+}
+
+// ---- Media events (stream media:events) ----
+const (
+	MediaEventStream    = "media:events"
+	MediaUploadVerified = "MediaUploadVerified"
+)
+
+type MediaUploadVerifiedEvent struct {
+	MediaID    string    `json:"media_id"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// CelebritiesKey - Redis SET các author có số follower vượt ngưỡng fan-out,
+// post của họ không được push vào feed follower mà được merge lúc đọc (fan-out on read)
+const CelebritiesKey = "celebrities"
+
+// PostItemKey - cache FeedItem đã hydrate của 1 post, phải xoá khi post bị sửa/xoá
+func PostItemKey(postID string) string {
+	return "post:" + postID + ":item"
+}
+
+// HashtagBucketKey - Redis ZSET hashtag -> số post có hashtag đó tạo trong bucket thời gian bắt đầu lúc start (unix)
+func HashtagBucketKey(start int64) string {
+	return "hashtags:bucket:" + strconv.FormatInt(start, 10)
+}
+
+// TrendingHashtagsKey - kết quả trending đã tính (ZUNIONSTORE các bucket) của 1 window, cache ngắn
+func TrendingHashtagsKey(window time.Duration) string {
+	return "hashtags:trending:" + window.String()
+}
+
+// ---- Post visibility (posts.visibility) ----
+const (
+	VisibilityPublic       = "public"
+	VisibilityFollowers    = "followers"
+	VisibilityOnlyMe       = "only_me"
+	VisibilityCloseFriends = "close_friends" // danh sách close friends của author
+)
+
+var Visibilities = []string{VisibilityPublic, VisibilityFollowers, VisibilityOnlyMe, VisibilityCloseFriends}
+
+func IsValidVisibility(v string) bool {
+	for _, allowed := range Visibilities {
+		if v == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ---- Post events (stream post:events) ----
+const (
+	PostEventStream       = "post:events"
+	PostCreated           = "PostCreated"
+	PostDeleted           = "PostDeleted"
+	PostMentioned         = "PostMentioned"
+	PostVisibilityChanged = "PostVisibilityChanged"
+)
+
+// NewPostEvent - Visibility rỗng (event cũ trước khi có visibility) coi như public
 type NewPostEvent struct {
-	PostID string
-	UserID string
+	PostID     string    `json:"post_id"`
+	UserID     string    `json:"user_id"`
+	Visibility string    `json:"visibility,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PostVisibilityChangedEvent - author đổi audience của post, fan-out gỡ post khỏi feed cũ rồi push lại theo audience mới
+type PostVisibilityChangedEvent struct {
+	PostID     string    `json:"post_id"`
+	UserID     string    `json:"user_id"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"` // created_at của post, giữ nguyên score trong feed
+}
+
+type PostDeletedEvent struct {
+	PostID    string    `json:"post_id"`
+	UserID    string    `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// PostMentionedEvent - user được @mention lần đầu trong post (lúc tạo hoặc sửa), notification-service báo cho họ
+type PostMentionedEvent struct {
+	PostID           string    `json:"post_id"`
+	UserID           string    `json:"user_id"` // author
+	MentionedUserIDs []string  `json:"mentioned_user_ids"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+// ---- Follow events (published by follow-service on stream follow:events) ----
+const (
+	FollowCreated = "FollowCreated"
+	FollowDeleted = "FollowDeleted"
+)
+
+type FollowEvent struct {
+	Type       string    `json:"type"`
+	FollowerID string    `json:"follower_id"`
+	FolloweeID string    `json:"followee_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// OutboxEvent - 1 row của bảng outbox
+type OutboxEvent struct {
+	ID          int64
+	AggregateID string
+	EventType   string
+	Payload     []byte
+	CreatedAt   time.Time
 }
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"followservice/internal/app"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Follow Service app
+	apiApp := app.NewFollowServiceApp()
+
+	// 2. Start the app (starts HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Follow Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Follow Service...")
+	apiApp.Stop()
+}
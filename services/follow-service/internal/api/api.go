@@ -4,26 +4,54 @@ import (
 	"encoding/json"
 	"followservice/model"
 	"followservice/utils"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
 type FollowStore interface {
-	Follow(follower_id string, followee_id string) (model.Follow, error)
-	Unfollow(follower_id string, followee_id string) error
+	Follow(follower_id string, followee_id string) (model.Follow, bool, error)
+	Unfollow(follower_id string, followee_id string) (bool, error)
 	GetFollowers(userID string) ([]model.Follow, error)
+	CountFollowers(userID string) (int64, error)
 	GetFollowees(userID string) ([]model.Follow, error)
+	GetMutuals(viewerID string, targetID string) ([]string, error)
+	Block(blockerID string, blockedID string) ([]model.Follow, error)
+	Unblock(blockerID string, blockedID string) error
+	IsBlocked(userA string, userB string) (bool, error)
+}
+
+type SuggestionManager interface {
+	GetSuggestions(userID string, limit int) ([]model.Suggestion, error)
+	Invalidate(userID string, candidateID string)
+}
+
+type UserServiceClient interface {
+	UserExists(userID string) (bool, error)
+}
+
+type EventPublisher interface {
+	Publish(event model.FollowEvent) error
 }
 
 type FollowAPI struct {
-	followStore FollowStore
+	followStore       FollowStore
+	userServiceClient UserServiceClient
+	eventPublisher    EventPublisher
+	suggestionManager SuggestionManager
 }
 
-func NewFollowAPI(followStore_ FollowStore) *FollowAPI {
+func NewFollowAPI(followStore_ FollowStore, userServiceClient_ UserServiceClient, eventPublisher_ EventPublisher,
+	suggestionManager_ SuggestionManager) *FollowAPI {
 	return &FollowAPI{
-		followStore: followStore_,
+		followStore:       followStore_,
+		userServiceClient: userServiceClient_,
+		eventPublisher:    eventPublisher_,
+		suggestionManager: suggestionManager_,
 	}
 }
 
@@ -31,32 +59,77 @@ func (api *FollowAPI) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/follows", api.handleFollow).Methods("POST")
 	r.HandleFunc("/follows", api.handleUnFollow).Methods("DELETE")
 	r.HandleFunc("/follows/{user_id}/followers", api.handleGetListFollowers).Methods("GET")
+	r.HandleFunc("/follows/{user_id}/followers/count", api.handleCountFollowers).Methods("GET")
 	r.HandleFunc("/follows/{user_id}/followees", api.handleGetListFollowees).Methods("GET")
+	r.HandleFunc("/users/{user_id}/mutuals", api.handleGetMutuals).Methods("GET")
+	r.HandleFunc("/me/suggestions", api.handleGetSuggestions).Methods("GET")
+	r.HandleFunc("/blocks", api.handleBlock).Methods("POST")
+	r.HandleFunc("/blocks", api.handleUnblock).Methods("DELETE")
 }
 
+// follower luôn là user đang đăng nhập (X-User-ID do gateway set), body chỉ chứa followee
 type followRequest struct {
-	FollowerID string `json:"follower_id"`
 	FolloweeID string `json:"followee_id"`
 }
 
 func (api *FollowAPI) handleFollow(w http.ResponseWriter, r *http.Request) {
-	var req followRequest
+	followerID := r.Header.Get("X-User-ID")
+	if followerID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
 
+	var req followRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	if req.FollowerID == "" || req.FolloweeID == "" {
-		utils.WriteError(w, http.StatusBadRequest, "follower_id and followee_id are required")
+	if req.FolloweeID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "followee_id is required")
+		return
+	}
+	if !isUUID(req.FolloweeID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid followee_id")
+		return
+	}
+
+	if req.FolloweeID == followerID {
+		utils.WriteError(w, http.StatusBadRequest, "cannot follow yourself")
 		return
 	}
 
-	follow, err := api.followStore.Follow(req.FollowerID, req.FolloweeID)
+	exists, err := api.userServiceClient.UserExists(req.FolloweeID)
+	if err != nil {
+		log.Printf("[FollowAPI] failed to check user %s: %v", req.FolloweeID, err)
+		utils.WriteError(w, http.StatusBadGateway, "failed to verify followee")
+		return
+	}
+	if !exists {
+		utils.WriteError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	blocked, err := api.followStore.IsBlocked(followerID, req.FolloweeID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if blocked {
+		utils.WriteError(w, http.StatusForbidden, "cannot follow this user")
+		return
+	}
+
+	follow, created, err := api.followStore.Follow(followerID, req.FolloweeID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if created {
+		api.publish(model.FollowCreated, follow.FollowerID, follow.FolloweeID)
+		api.suggestionManager.Invalidate(follow.FollowerID, follow.FolloweeID)
+	}
 
 	resp := map[string]interface{}{
 		"status":      "success",
@@ -69,35 +142,59 @@ func (api *FollowAPI) handleFollow(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *FollowAPI) handleUnFollow(w http.ResponseWriter, r *http.Request) {
-	type request struct {
-		FollowerID string `json:"follower_id"`
-		FolloweeID string `json:"followee_id"`
+	followerID := r.Header.Get("X-User-ID")
+	if followerID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
 	}
 
-	var req request
+	var req followRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	if req.FollowerID == "" || req.FolloweeID == "" {
-		utils.WriteError(w, http.StatusBadRequest, "follower_id and followee_id are required")
+	if req.FolloweeID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "followee_id is required")
+		return
+	}
+	if !isUUID(req.FolloweeID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid followee_id")
 		return
 	}
 
-	err := api.followStore.Unfollow(req.FollowerID, req.FolloweeID)
+	deleted, err := api.followStore.Unfollow(followerID, req.FolloweeID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "failed to unfollow: "+err.Error())
 		return
 	}
 
+	message := "not following"
+	if deleted {
+		message = "unfollowed"
+		api.publish(model.FollowDeleted, followerID, req.FolloweeID)
+	}
+
 	resp := model.UnfollowResponse{
 		Status:  "success",
-		Message: "unfollowed",
+		Message: message,
 	}
 	utils.WriteJSON(w, http.StatusOK, resp)
 }
 
+// publish chỉ log lỗi: quan hệ follow đã được ghi vào DB, không rollback vì event
+func (api *FollowAPI) publish(eventType, followerID, followeeID string) {
+	event := model.FollowEvent{
+		Type:       eventType,
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		OccurredAt: time.Now(),
+	}
+	if err := api.eventPublisher.Publish(event); err != nil {
+		log.Printf("[FollowAPI] %v", err)
+	}
+}
+
 func (api *FollowAPI) handleGetListFollowers(w http.ResponseWriter, r *http.Request) {
 	userID := mux.Vars(r)["user_id"]
 	if userID == "" {
@@ -118,6 +215,20 @@ func (api *FollowAPI) handleGetListFollowers(w http.ResponseWriter, r *http.Requ
 	utils.WriteJSON(w, http.StatusOK, resp)
 }
 
+// handleCountFollowers - feed-service dùng để check celebrity khi fan-out mà không tải cả danh sách follower
+func (api *FollowAPI) handleCountFollowers(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["user_id"]
+	count, err := api.followStore.CountFollowers(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to count followers: "+err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":   userID,
+		"followers": count,
+	})
+}
+
 func (api *FollowAPI) handleGetListFollowees(w http.ResponseWriter, r *http.Request) {
 	userID := mux.Vars(r)["user_id"]
 	if userID == "" {
@@ -137,3 +248,149 @@ func (api *FollowAPI) handleGetListFollowees(w http.ResponseWriter, r *http.Requ
 	}
 	utils.WriteJSON(w, http.StatusOK, resp)
 }
+
+// GET /users/{user_id}/mutuals - những người mà cả viewer và user_id cùng follow
+func (api *FollowAPI) handleGetMutuals(w http.ResponseWriter, r *http.Request) {
+	viewerID := r.Header.Get("X-User-ID")
+	if viewerID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	targetID := mux.Vars(r)["user_id"]
+	if targetID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	mutuals, err := api.followStore.GetMutuals(viewerID, targetID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to fetch mutuals: "+err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"user_id": targetID,
+		"mutuals": mutuals,
+		"total":   len(mutuals),
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// GET /me/suggestions?limit=
+func (api *FollowAPI) handleGetSuggestions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 100 {
+			utils.WriteError(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+
+	suggestions, err := api.suggestionManager.GetSuggestions(userID, limit)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to fetch suggestions: "+err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"user_id":     userID,
+		"suggestions": suggestions,
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// isUUID - id không phải UUID sẽ làm Postgres/user-service lỗi cast, trả 400 trước khi gọi xuống
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+type blockRequest struct {
+	BlockedID string `json:"blocked_id"`
+}
+
+// POST /blocks - block user, đồng thời xoá follow 2 chiều
+func (api *FollowAPI) handleBlock(w http.ResponseWriter, r *http.Request) {
+	blockerID := r.Header.Get("X-User-ID")
+	if blockerID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req blockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.BlockedID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "blocked_id is required")
+		return
+	}
+	if !isUUID(req.BlockedID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid blocked_id")
+		return
+	}
+	if req.BlockedID == blockerID {
+		utils.WriteError(w, http.StatusBadRequest, "cannot block yourself")
+		return
+	}
+
+	removed, err := api.followStore.Block(blockerID, req.BlockedID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to block: "+err.Error())
+		return
+	}
+
+	for _, follow := range removed {
+		api.publish(model.FollowDeleted, follow.FollowerID, follow.FolloweeID)
+	}
+	api.suggestionManager.Invalidate(blockerID, req.BlockedID)
+	api.suggestionManager.Invalidate(req.BlockedID, blockerID)
+
+	utils.WriteJSON(w, http.StatusOK, map[string]string{
+		"status":  "success",
+		"message": "blocked",
+	})
+}
+
+// DELETE /blocks
+func (api *FollowAPI) handleUnblock(w http.ResponseWriter, r *http.Request) {
+	blockerID := r.Header.Get("X-User-ID")
+	if blockerID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	var req blockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.BlockedID == "" {
+		utils.WriteError(w, http.StatusBadRequest, "blocked_id is required")
+		return
+	}
+	if !isUUID(req.BlockedID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid blocked_id")
+		return
+	}
+
+	if err := api.followStore.Unblock(blockerID, req.BlockedID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to unblock: "+err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]string{
+		"status":  "success",
+		"message": "unblocked",
+	})
+}
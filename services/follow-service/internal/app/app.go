@@ -0,0 +1,89 @@
+package app
+
+import (
+	"followservice/internal/api"
+	"followservice/internal/core/followevent"
+	"followservice/internal/core/http-server/server"
+	"followservice/internal/core/suggestionmanager"
+	"followservice/internal/core/userserviceclient"
+	"followservice/internal/infra/redisclient"
+	"followservice/internal/infra/store"
+	"log"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver        *server.HttpServer
+	followapi         *api.FollowAPI
+	suggestionmanager *suggestionmanager.SuggestionManager
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DBNumber int
+}
+
+func NewFollowServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.suggestionmanager.Start(); err != nil {
+		log.Fatalf("❌ Failed to start suggestion job: %v", err)
+	}
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+func (a *App) Stop() {
+	if err := a.suggestionmanager.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping suggestion job: %v", err)
+	}
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	dbcfg := &store.PostGresConfig{
+		Host:     "localhost", // IP
+		Port:     "5432",      // Port
+		User:     "taopq",     // user_name
+		Password: "123456a@",  // password
+		DBname:   "mydb",      // db
+	}
+
+	redisstorecfg := &RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	followstore := store.NewFollowStore(dbcfg)
+	a.suggestionmanager = suggestionmanager.NewSuggestionManager(followstore, rc, suggestionmanager.SuggestionConfig{
+		Interval: 10 * time.Minute,
+		Limit:    50,
+		CacheTTL: 24 * time.Hour,
+	})
+
+	a.followapi = api.NewFollowAPI(
+		followstore,
+		userserviceclient.NewUserServiceClient("http://localhost:9001"),
+		followevent.NewPublisher(rc),
+		a.suggestionmanager,
+	)
+	router := mux.NewRouter()
+	a.followapi.RegisterRoutes(router)
+	a.httpserver = server.NewHttpServer("localhost:9002", router)
+}
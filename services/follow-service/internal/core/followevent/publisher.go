@@ -0,0 +1,49 @@
+package followevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"followservice/internal/infra/redisclient"
+	"followservice/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamKey - Redis Stream chứa toàn bộ follow events
+const StreamKey = "follow:events"
+
+// maxStreamLen giới hạn (xấp xỉ) số event giữ lại trong stream
+const maxStreamLen = 100000
+
+type Publisher struct {
+	redisclient *redisclient.RedisClient
+}
+
+func NewPublisher(redisclient_ *redisclient.RedisClient) *Publisher {
+	return &Publisher{
+		redisclient: redisclient_,
+	}
+}
+
+// Publish append event vào stream, consumer group của từng service tự đọc
+func (p *Publisher) Publish(event model.FollowEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("[FollowEventPublisher] failed to marshal event: %w", err)
+	}
+
+	err = p.redisclient.GetClient().XAdd(context.Background(), &redis.XAddArgs{
+		Stream: StreamKey,
+		MaxLen: maxStreamLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":    event.Type,
+			"payload": payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("[FollowEventPublisher] failed to publish %s: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package suggestionmanager
+
+import (
+	"context"
+	"fmt"
+	"followservice/internal/infra/redisclient"
+	"followservice/model"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type SuggestionStore interface {
+	ComputeSuggestions(userID string, limit int) ([]model.Suggestion, error)
+	GetActiveFollowerIDs() ([]string, error)
+}
+
+type SuggestionConfig struct {
+	Interval time.Duration // chu kỳ chạy background job
+	Limit    int           // số suggestion tối đa lưu cho mỗi user
+	CacheTTL time.Duration // TTL của cache trong Redis
+}
+
+// SuggestionManager precompute "people you may know" định kỳ và cache vào Redis:
+// user:{id}:suggestions (ZSET, score = số mutual connections)
+type SuggestionManager struct {
+	store       SuggestionStore
+	redisclient *redisclient.RedisClient
+	cfg         SuggestionConfig
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func NewSuggestionManager(store_ SuggestionStore, redisclient_ *redisclient.RedisClient, cfg SuggestionConfig) *SuggestionManager {
+	return &SuggestionManager{
+		store:       store_,
+		redisclient: redisclient_,
+		cfg:         cfg,
+		stop:        make(chan struct{}),
+	}
+}
+
+// emptyMarker - ZSET rỗng không lưu được, user không có suggestion nào được cache bằng
+// 1 member score -inf để GetSuggestions không phải tính lại mỗi lần đọc
+var emptyMarker = redis.Z{Score: math.Inf(-1), Member: "empty"}
+
+func suggestionsKey(userID string) string {
+	return fmt.Sprintf("user:%s:suggestions", userID)
+}
+
+func (m *SuggestionManager) Start() error {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		m.refreshAll()
+		for {
+			select {
+			case <-ticker.C:
+				m.refreshAll()
+			case <-m.stop:
+				log.Println("[SuggestionManager] stopped")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *SuggestionManager) Stop() error {
+	close(m.stop)
+	m.wg.Wait()
+	return nil
+}
+
+// refreshAll tính lại suggestion cho mọi user đang follow ít nhất 1 người
+func (m *SuggestionManager) refreshAll() {
+	start := time.Now()
+	userIDs, err := m.store.GetActiveFollowerIDs()
+	if err != nil {
+		log.Printf("[SuggestionManager] failed to list active users: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		select {
+		case <-m.stop:
+			return
+		default:
+		}
+		if _, err := m.Refresh(userID); err != nil {
+			log.Printf("[SuggestionManager] %v", err)
+		}
+	}
+	log.Printf("[SuggestionManager] refreshed suggestions for %d users in %s", len(userIDs), time.Since(start))
+}
+
+// Refresh tính lại suggestion của 1 user và ghi đè cache
+func (m *SuggestionManager) Refresh(userID string) ([]model.Suggestion, error) {
+	suggestions, err := m.store.ComputeSuggestions(userID, m.cfg.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute suggestions for user %s: %w", userID, err)
+	}
+
+	ctx := context.Background()
+	key := suggestionsKey(userID)
+	_, err = m.redisclient.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(suggestions) == 0 {
+			pipe.ZAdd(ctx, key, emptyMarker)
+			pipe.Expire(ctx, key, m.cfg.CacheTTL)
+			return nil
+		}
+		members := make([]redis.Z, 0, len(suggestions))
+		for _, s := range suggestions {
+			members = append(members, redis.Z{Score: float64(s.MutualCount), Member: s.UserID})
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, m.cfg.CacheTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache suggestions for user %s: %w", userID, err)
+	}
+	return suggestions, nil
+}
+
+// GetSuggestions đọc từ cache, cache miss thì tính ngay (user mới chưa được job xử lý).
+// Kết quả rỗng cũng được cache (emptyMarker) nên chỉ key không tồn tại mới là miss.
+func (m *SuggestionManager) GetSuggestions(userID string, limit int) ([]model.Suggestion, error) {
+	ctx := context.Background()
+	key := suggestionsKey(userID)
+
+	var exists *redis.IntCmd
+	var cached *redis.ZSliceCmd
+	_, err := m.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = pipe.Exists(ctx, key)
+		cached = pipe.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: "(-inf", Max: "+inf", Count: int64(limit)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suggestions for user %s: %w", userID, err)
+	}
+	results := cached.Val()
+
+	if exists.Val() == 0 {
+		suggestions, err := m.Refresh(userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(suggestions) > limit {
+			suggestions = suggestions[:limit]
+		}
+		return suggestions, nil
+	}
+
+	suggestions := make([]model.Suggestion, 0, len(results))
+	for _, z := range results {
+		suggestions = append(suggestions, model.Suggestion{
+			UserID:      z.Member.(string),
+			MutualCount: int64(z.Score),
+		})
+	}
+	return suggestions, nil
+}
+
+// Invalidate bỏ candidateID khỏi suggestion đã cache của userID
+// (sau khi follow hoặc block thì không gợi ý lại nữa)
+func (m *SuggestionManager) Invalidate(userID string, candidateID string) {
+	if err := m.redisclient.GetClient().ZRem(context.Background(), suggestionsKey(userID), candidateID).Err(); err != nil {
+		log.Printf("[SuggestionManager] failed to remove %s from suggestions of %s: %v", candidateID, userID, err)
+	}
+}
@@ -0,0 +1,48 @@
+package userserviceclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type UserService struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewUserServiceClient(baseURL string) *UserService {
+	return &UserService{
+		BaseURL: baseURL,
+		Client:  &http.Client{},
+	}
+}
+
+// UserExists calls UserService API to check if a (non deleted) user_id exists
+func (u *UserService) UserExists(userID string) (bool, error) {
+	url := fmt.Sprintf("%s/users/%s", u.BaseURL, userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("[UserServiceClient] failed to build get request: %w", err)
+	}
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("[UserServiceClient] failed to call user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("[UserServiceClient] unexpected status code: %d", resp.StatusCode)
+	}
+}
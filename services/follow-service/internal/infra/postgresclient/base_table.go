@@ -11,9 +11,10 @@ type BaseTable struct {
 	TableName   string
 	Columns     map[string]string // column_name -> type (VD: "id": "SERIAL PRIMARY KEY")
 	Constraints []string          // danh sách constraint ở mức table (FOREIGN KEY, UNIQUE, CHECK, ...)
+	Indexes     []string          // CREATE [UNIQUE] INDEX IF NOT EXISTS ..., không nằm được trong CREATE TABLE nên chạy riêng
 }
 
-// CreateTable tạo bảng dựa trên metadata
+// CreateTable tạo bảng dựa trên metadata, sau đó tạo index
 func (bt *BaseTable) CreateTable() {
 	var cols []string
 	for col, typ := range bt.Columns {
@@ -34,9 +35,19 @@ func (bt *BaseTable) CreateTable() {
 	if err != nil {
 		log.Fatalf("❌ Lỗi tạo bảng %s: %v", bt.TableName, err)
 	}
+	bt.CreateIndexes()
 	log.Printf("✅ Bảng %s sẵn sàng.", bt.TableName)
 }
 
+// CreateIndexes tạo các index còn thiếu (IF NOT EXISTS), chạy được cả với bảng đã tồn tại
+func (bt *BaseTable) CreateIndexes() {
+	for _, index := range bt.Indexes {
+		if _, err := bt.Client.DB.Exec(index); err != nil {
+			log.Fatalf("❌ Lỗi tạo index cho bảng %s: %v", bt.TableName, err)
+		}
+	}
+}
+
 // Insert thêm dữ liệu vào bảng
 func (bt *BaseTable) Insert(values map[string]interface{}) {
 	cols := []string{}
@@ -1,9 +1,9 @@
 package main
 
 import (
-	dbclient "authservice/internal/infra/postgresclient"
-	"authservice/internal/infra/postgresclient/tables"
 	"fmt"
+	dbclient "followservice/internal/infra/postgresclient"
+	"followservice/internal/infra/postgresclient/tables"
 	"log"
 )
 
@@ -17,18 +17,19 @@ func main() {
 	)
 	defer client.Close()
 
-	// Tạo bảng rules
-	cedentialsTable := tables.NewCredentialsTable(client)
+	// Tạo bảng follows
+	followsTable := tables.NewFollowsTable(client)
 
-	if !client.SearchTable(cedentialsTable.TableName) {
-		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", cedentialsTable.TableName)
-		cedentialsTable.CreateTable()
+	if !client.SearchTable(followsTable.TableName) {
+		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", followsTable.TableName)
+		followsTable.CreateTable()
 	} else {
-		fmt.Printf("%s EXISTED\n", cedentialsTable.TableName)
+		fmt.Printf("%s EXISTED\n", followsTable.TableName)
+		followsTable.CreateIndexes()
 	}
 
-	// Lấy tất cả rules
-	rows, err := cedentialsTable.GetAll()
+	// Lấy tất cả follows
+	rows, err := followsTable.GetAll()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,21 +37,13 @@ func main() {
 		fmt.Println(row)
 	}
 
-	sessionsTable := tables.NewSessionsTable(client)
+	blocksTable := tables.NewBlocksTable(client)
 
-	if !client.SearchTable(sessionsTable.TableName) {
-		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", sessionsTable.TableName)
-		sessionsTable.CreateTable()
+	if !client.SearchTable(blocksTable.TableName) {
+		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", blocksTable.TableName)
+		blocksTable.CreateTable()
 	} else {
-		fmt.Printf("%s EXISTED\n", sessionsTable.TableName)
-	}
-
-	// Lấy tất cả rules
-	rows, err = sessionsTable.GetAll()
-	if err != nil {
-		log.Fatal(err)
-	}
-	for _, row := range rows {
-		fmt.Println(row)
+		fmt.Printf("%s EXISTED\n", blocksTable.TableName)
+		blocksTable.CreateIndexes()
 	}
 }
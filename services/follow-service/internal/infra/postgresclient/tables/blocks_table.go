@@ -0,0 +1,27 @@
+package tables
+
+import dbclient "followservice/internal/infra/postgresclient"
+
+// BlocksTable kế thừa BaseTable
+type BlocksTable struct {
+	dbclient.BaseTable
+}
+
+// NewBlocksTable khởi tạo table blocks
+func NewBlocksTable(client *dbclient.PostgresClient) *BlocksTable {
+	return &BlocksTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "blocks",
+			Columns: map[string]string{
+				"blocker_id": "UUID NOT NULL",
+				"blocked_id": "UUID NOT NULL",
+				"created_at": "TIMESTAMP DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (blocker_id, blocked_id)",
+				"CHECK (blocker_id <> blocked_id)",
+			},
+		},
+	}
+}
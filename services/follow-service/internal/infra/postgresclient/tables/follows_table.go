@@ -20,8 +20,10 @@ func NewFollowsTable(client *dbclient.PostgresClient) *FollowsTable {
 			},
 			Constraints: []string{
 				"PRIMARY KEY (follower_id, followee_id)",
-				"CREATE INDEX follower_id_idx ON follows(follower_id)",
-				"CREATE INDEX followee_id_idx ON follows(followee_id)",
+			},
+			Indexes: []string{
+				"CREATE INDEX IF NOT EXISTS follower_id_idx ON follows(follower_id)",
+				"CREATE INDEX IF NOT EXISTS followee_id_idx ON follows(followee_id)",
 			},
 		},
 	}
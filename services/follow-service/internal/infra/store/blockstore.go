@@ -0,0 +1,81 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"followservice/model"
+	"time"
+)
+
+// Block chặn blocked_id và xoá quan hệ follow theo cả 2 chiều trong cùng transaction.
+// Trả về các follow đã bị xoá để caller phát FollowDeleted events.
+func (f *FollowStore) Block(blockerID string, blockedID string) ([]model.Follow, error) {
+	tx, err := f.DBClient.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO blocks (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`, blockerID, blockedID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert block: %w", err)
+	}
+
+	rows, err := tx.Query(`
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND followee_id = $2)
+		   OR (follower_id = $2 AND followee_id = $1)
+		RETURNING follower_id, followee_id, created_at
+	`, blockerID, blockedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete follows: %w", err)
+	}
+	defer rows.Close()
+
+	var removed []model.Follow
+	for rows.Next() {
+		var follow model.Follow
+		if err := rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+		removed = append(removed, follow)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit block: %w", err)
+	}
+	return removed, nil
+}
+
+// Unblock xoá block, không khôi phục lại follow cũ
+func (f *FollowStore) Unblock(blockerID string, blockedID string) error {
+	query := `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	_, err := f.DBClient.DB.Exec(query, blockerID, blockedID)
+	return err
+}
+
+// IsBlocked kiểm tra 2 user có block nhau (bất kể chiều nào)
+func (f *FollowStore) IsBlocked(userA string, userB string) (bool, error) {
+	query := `
+		SELECT 1 FROM blocks
+		WHERE (blocker_id = $1 AND blocked_id = $2)
+		   OR (blocker_id = $2 AND blocked_id = $1)
+		LIMIT 1
+	`
+	var exists int
+	err := f.DBClient.DB.QueryRow(query, userA, userB).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
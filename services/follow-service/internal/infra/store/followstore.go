@@ -1,6 +1,7 @@
 package store
 
 import (
+	"database/sql"
 	"followservice/model"
 	"time"
 
@@ -31,28 +32,48 @@ func NewFollowStore(postgrescfg *PostGresConfig) *FollowStore {
 	}
 }
 
-// Follow inserts a new follow relationship
-func (f *FollowStore) Follow(follower_id string, followee_id string) (model.Follow, error) {
+// Follow inserts a new follow relationship.
+// Idempotent: nếu đã follow rồi thì trả về bản ghi cũ với created = false
+func (f *FollowStore) Follow(follower_id string, followee_id string) (model.Follow, bool, error) {
 	query := `
 		INSERT INTO follows (follower_id, followee_id, created_at)
 		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
 		RETURNING follower_id, followee_id, created_at
 	`
 	row := f.DBClient.DB.QueryRow(query, follower_id, followee_id, time.Now())
 
 	var follow model.Follow
-	if err := row.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.CreatedAt); err != nil {
-		return model.Follow{}, err
+	err := row.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.CreatedAt)
+	if err == nil {
+		return follow, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return model.Follow{}, false, err
 	}
 
-	return follow, nil
+	// đã tồn tại -> lấy bản ghi hiện có
+	query = `SELECT follower_id, followee_id, created_at FROM follows WHERE follower_id = $1 AND followee_id = $2`
+	row = f.DBClient.DB.QueryRow(query, follower_id, followee_id)
+	if err := row.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.CreatedAt); err != nil {
+		return model.Follow{}, false, err
+	}
+	return follow, false, nil
 }
 
-// Unfollow deletes an existing follow relationship
-func (f *FollowStore) Unfollow(follower_id string, followee_id string) error {
+// Unfollow deletes an existing follow relationship.
+// deleted = false nếu trước đó không follow
+func (f *FollowStore) Unfollow(follower_id string, followee_id string) (bool, error) {
 	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
-	_, err := f.DBClient.DB.Exec(query, follower_id, followee_id)
-	return err
+	result, err := f.DBClient.DB.Exec(query, follower_id, followee_id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
 }
 
 // GetFollowers fetches all followers for a given user (followee_id)
@@ -79,6 +100,16 @@ func (f *FollowStore) GetFollowers(userID string) ([]model.Follow, error) {
 	return followers, nil
 }
 
+// CountFollowers đếm follower của user, không đọc cả danh sách
+func (f *FollowStore) CountFollowers(userID string) (int64, error) {
+	var count int64
+	err := f.DBClient.DB.QueryRow(`SELECT COUNT(*) FROM follows WHERE followee_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // GetFollowees fetches all followers for a given user (followee_id)
 func (f *FollowStore) GetFollowees(userID string) ([]model.Follow, error) {
 	query := `SELECT follower_id, followee_id, created_at FROM follows WHERE follower_id = $1`
@@ -102,3 +133,99 @@ func (f *FollowStore) GetFollowees(userID string) ([]model.Follow, error) {
 
 	return followees, nil
 }
+
+// GetMutuals trả về các user mà cả viewer và target cùng follow
+func (f *FollowStore) GetMutuals(viewerID string, targetID string) ([]string, error) {
+	query := `
+		SELECT a.followee_id
+		FROM follows a
+		JOIN follows b ON b.followee_id = a.followee_id
+		WHERE a.follower_id = $1 AND b.follower_id = $2
+		ORDER BY a.followee_id
+	`
+	rows, err := f.DBClient.DB.Query(query, viewerID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutuals := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		mutuals = append(mutuals, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mutuals, nil
+}
+
+// ComputeSuggestions tính friends-of-friends của userID, xếp theo số mutual connections,
+// bỏ qua user đã follow và user có block với userID
+func (f *FollowStore) ComputeSuggestions(userID string, limit int) ([]model.Suggestion, error) {
+	query := `
+		SELECT f2.followee_id, COUNT(*) AS mutual_count
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.followee_id
+		WHERE f1.follower_id = $1
+		  AND f2.followee_id <> $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM follows f3
+			WHERE f3.follower_id = $1 AND f3.followee_id = f2.followee_id
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM blocks b
+			WHERE (b.blocker_id = $1 AND b.blocked_id = f2.followee_id)
+			   OR (b.blocker_id = f2.followee_id AND b.blocked_id = $1)
+		  )
+		GROUP BY f2.followee_id
+		ORDER BY mutual_count DESC, f2.followee_id
+		LIMIT $2
+	`
+	rows, err := f.DBClient.DB.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []model.Suggestion{}
+	for rows.Next() {
+		var s model.Suggestion
+		if err := rows.Scan(&s.UserID, &s.MutualCount); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// GetActiveFollowerIDs - các user đang follow ít nhất 1 người (đầu vào cho suggestion job)
+func (f *FollowStore) GetActiveFollowerIDs() ([]string, error) {
+	rows, err := f.DBClient.DB.Query(`SELECT DISTINCT follower_id FROM follows`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
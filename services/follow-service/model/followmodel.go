@@ -13,3 +13,24 @@ type UnfollowResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
+
+// ---- Events ----
+const (
+	FollowCreated = "FollowCreated"
+	FollowDeleted = "FollowDeleted"
+)
+
+// FollowEvent được publish mỗi khi quan hệ follow thực sự thay đổi
+// (notification-service, feed-service backfill sẽ consume)
+type FollowEvent struct {
+	Type       string    `json:"type"`
+	FollowerID string    `json:"follower_id"`
+	FolloweeID string    `json:"followee_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Suggestion - "people you may know", xếp hạng theo số mutual connections
+type Suggestion struct {
+	UserID      string `json:"user_id"`
+	MutualCount int64  `json:"mutual_count"`
+}
@@ -8,7 +8,8 @@ type Endpoint struct {
 	Method      string
 	Path        string
 	RequireAuth bool
-	RateLimit   int // per second
+	RateLimit   int  // per second
+	Streaming   bool // SSE/WebSocket: proxy thẳng, không qua pipeline buffer + timeout
 }
 
 // ===== Struct cho Group Endpoint / Internal Service =====
@@ -113,6 +114,27 @@ var PostsService = ServiceGroup{
 			RequireAuth: true,
 			RateLimit:   5,
 		},
+		{
+			Name:        "GetCloseFriends",
+			Method:      http.MethodGet,
+			Path:        "/me/close-friends",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "AddCloseFriend",
+			Method:      http.MethodPut,
+			Path:        "/me/close-friends/{user_id}",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "RemoveCloseFriend",
+			Method:      http.MethodDelete,
+			Path:        "/me/close-friends/{user_id}",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
 		{
 			Name:        "CreatePost",
 			Method:      http.MethodPost,
@@ -120,6 +142,13 @@ var PostsService = ServiceGroup{
 			RequireAuth: true,
 			RateLimit:   1,
 		},
+		{
+			Name:        "SharePost",
+			Method:      http.MethodPost,
+			Path:        "/posts/{post_id}/share",
+			RequireAuth: true,
+			RateLimit:   1,
+		},
 		{
 			Name:        "UpdatePost",
 			Method:      http.MethodPatch,
@@ -134,6 +163,69 @@ var PostsService = ServiceGroup{
 			RequireAuth: true,
 			RateLimit:   1,
 		},
+		{
+			Name:        "GetPostRevisions",
+			Method:      http.MethodGet,
+			Path:        "/posts/{post_id}/revisions",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "CreateMedia",
+			Method:      http.MethodPost,
+			Path:        "/media",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "GetMediaUploadParts",
+			Method:      http.MethodGet,
+			Path:        "/media/{media_id}/parts",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "CompleteMediaUpload",
+			Method:      http.MethodPost,
+			Path:        "/media/{media_id}/complete",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "AbortMediaUpload",
+			Method:      http.MethodPost,
+			Path:        "/media/{media_id}/abort",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "GetFeed",
+			Method:      http.MethodGet,
+			Path:        "/feeds",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "CountNewFeedPosts",
+			Method:      http.MethodGet,
+			Path:        "/feeds/new",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "GetHashtagPosts",
+			Method:      http.MethodGet,
+			Path:        "/hashtags/{tag}/posts",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "GetTrendingHashtags",
+			Method:      http.MethodGet,
+			Path:        "/hashtags/trending",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
 	},
 }
 
@@ -166,3 +258,123 @@ var ReactionsService = ServiceGroup{
 		},
 	},
 }
+
+// ===== Comments Service =====
+var CommentsService = ServiceGroup{
+	Name: "CommentsService",
+	IP:   "localhost",
+	Port: 9094,
+	Endpoints: []Endpoint{
+		{
+			Name:        "GetComments",
+			Method:      http.MethodGet,
+			Path:        "/posts/{post_id}/comments",
+			RequireAuth: true, // tùy chọn
+			RateLimit:   5,
+		},
+		{
+			Name:        "CreateComment",
+			Method:      http.MethodPost,
+			Path:        "/posts/{post_id}/comments",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "GetReplies",
+			Method:      http.MethodGet,
+			Path:        "/comments/{comment_id}/replies",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "UpdateComment",
+			Method:      http.MethodPatch,
+			Path:        "/comments/{comment_id}",
+			RequireAuth: true,
+			RateLimit:   1,
+		},
+		{
+			Name:        "DeleteComment",
+			Method:      http.MethodDelete,
+			Path:        "/comments/{comment_id}",
+			RequireAuth: true,
+			RateLimit:   1,
+		},
+	},
+}
+
+// ===== Notifications Service =====
+var NotificationsService = ServiceGroup{
+	Name: "NotificationsService",
+	IP:   "localhost",
+	Port: 9095,
+	Endpoints: []Endpoint{
+		{
+			Name:        "GetNotifications",
+			Method:      http.MethodGet,
+			Path:        "/notifications",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "CountUnreadNotifications",
+			Method:      http.MethodGet,
+			Path:        "/notifications/unread-count",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "MarkAllNotificationsRead",
+			Method:      http.MethodPost,
+			Path:        "/notifications/read-all",
+			RequireAuth: true,
+			RateLimit:   1,
+		},
+		{
+			Name:        "MarkNotificationRead",
+			Method:      http.MethodPatch,
+			Path:        "/notifications/{notification_id}",
+			RequireAuth: true,
+			RateLimit:   5,
+		},
+		{
+			Name:        "GetNotificationPreferences",
+			Method:      http.MethodGet,
+			Path:        "/me/notification-preferences",
+			RequireAuth: true,
+			RateLimit:   2,
+		},
+		{
+			Name:        "UpdateNotificationPreferences",
+			Method:      http.MethodPatch,
+			Path:        "/me/notification-preferences",
+			RequireAuth: true,
+			RateLimit:   1,
+		},
+	},
+}
+
+// ===== Realtime Service =====
+var RealtimeService = ServiceGroup{
+	Name: "RealtimeService",
+	IP:   "localhost",
+	Port: 9096,
+	Endpoints: []Endpoint{
+		{
+			Name:        "EventStream",
+			Method:      http.MethodGet,
+			Path:        "/realtime/sse",
+			RequireAuth: true,
+			RateLimit:   1, // mở connection, không phải mỗi event
+			Streaming:   true,
+		},
+		{
+			Name:        "EventSocket",
+			Method:      http.MethodGet,
+			Path:        "/realtime/ws",
+			RequireAuth: true,
+			RateLimit:   1,
+			Streaming:   true,
+		},
+	},
+}
@@ -71,6 +71,11 @@ func (a *App) init() {
 	for _, sg := range a.gmodel.ServiceGroups {
 		for _, ep := range sg.Endpoints {
 			topic := sg.Name + "/" + ep.Name
+			if ep.Streaming {
+				router.HandleFunc(ep.Path, a.makeStreamHandler(sg, ep, topic)).Methods(ep.Method)
+				log.Printf("Registered stream route: %s %s -> topic %s", ep.Method, ep.Path, topic)
+				continue
+			}
 			router.HandleFunc(ep.Path, a.makeHandler(topic)).Methods(ep.Method)
 			log.Printf("Registered route: %s %s -> topic %s", ep.Method, ep.Path, topic)
 		}
@@ -109,6 +114,7 @@ func (a *App) makeHandler(topic string) http.HandlerFunc {
 			Ctx:     r.Context(),
 			Method:  r.Method,
 			Path:    pathWithParams,
+			Query:   r.URL.RawQuery,
 			Header:  r.Header.Clone(),
 			Body:    body,
 			IP:      ip,
@@ -307,7 +313,11 @@ func (a *App) getTargetURL(req model.RawRequestData) string {
 		for _, ep := range sg.Endpoints {
 			topic := sg.Name + "/" + ep.Name
 			if req.Topic == topic {
-				return "http://" + sg.IP + ":" + strconv.Itoa(sg.Port) + req.Path
+				target := "http://" + sg.IP + ":" + strconv.Itoa(sg.Port) + req.Path
+				if req.Query != "" {
+					target += "?" + req.Query
+				}
+				return target
 			}
 		}
 	}
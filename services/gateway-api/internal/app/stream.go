@@ -0,0 +1,101 @@
+package app
+
+import (
+	apis "gatewayapi/internal/api"
+	"gatewayapi/model"
+	"gatewayapi/utils"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// makeStreamHandler cho endpoint SSE/WebSocket: kiểm tra rate limit + JWT giống pipeline
+// lúc mở connection, sau đó proxy thẳng tới service (không buffer response, không timeout 3s)
+func (a *App) makeStreamHandler(sg apis.ServiceGroup, ep apis.Endpoint, topic string) http.HandlerFunc {
+	target := &url.URL{Scheme: "http", Host: sg.IP + ":" + strconv.Itoa(sg.Port)}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&reqCount, 1)
+		start := time.Now()
+		requestID := utils.NewRequestID()
+
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			// RemoteAddr do net/http set từ connection, parse lỗi là lỗi phía server
+			log.Printf("[Stream] %s: failed to parse RemoteAddr %q: %v", topic, r.RemoteAddr, err)
+			writeResult(w, a.normalizedError(requestID, http.StatusInternalServerError, "INTERNAL_ERROR", "Cannot determine client address", time.Since(start)))
+			return
+		}
+
+		if !a.ratelimiter.MaxReqLimiter.Allow("Max-Request-Per-Second") {
+			writeResult(w, a.normalizedError(requestID, http.StatusTooManyRequests, "RATE_LIMIT_MAX_REQUEST", "Too Many Requests", time.Since(start)))
+			return
+		}
+		if !a.ratelimiter.IPLimiter.Allow(ip) {
+			writeResult(w, a.normalizedError(requestID, http.StatusTooManyRequests, "RATE_LIMIT_IP", "Too Many Requests (IP)", time.Since(start)))
+			return
+		}
+
+		userID := "anonymous"
+		if ep.RequireAuth {
+			// EventSource/WebSocket trên browser không set được header, cho phép ?access_token=
+			token := r.Header.Get("Authorization")
+			if token == "" && r.URL.Query().Get("access_token") != "" {
+				token = "Bearer " + r.URL.Query().Get("access_token")
+			}
+			claims, ok := a.jwtchecker.TokenCheck(token)
+			if !ok {
+				writeResult(w, a.normalizedError(requestID, http.StatusUnauthorized, "UNAUTHENTICATED", "Unauthorized (JWT)", time.Since(start)))
+				return
+			}
+			userID = claims.UserID
+		}
+
+		if !a.ratelimiter.FeatureLimiter.Allow(userID + ":" + topic) {
+			writeResult(w, a.normalizedError(requestID, http.StatusTooManyRequests, "RATE_LIMIT_FEATURE", "Too Many Requests (Feature)", time.Since(start)))
+			return
+		}
+
+		// connection sống lâu hơn ReadTimeout/WriteTimeout của server, service tự gửi heartbeat
+		rc := http.NewResponseController(w)
+		_ = rc.SetReadDeadline(time.Time{})
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.SetXForwarded()
+
+				q := pr.Out.URL.Query()
+				q.Del("access_token")
+				pr.Out.URL.RawQuery = q.Encode()
+
+				pr.Out.Header.Del("Authorization")
+				pr.Out.Header.Set("X-Request-ID", requestID)
+				pr.Out.Header.Set("X-Trace-ID", utils.NewRequestID())
+				pr.Out.Header.Set("X-User-ID", userID)
+			},
+			FlushInterval: -1, // ghi từng event ngay
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				log.Printf("[Stream] %s: %v", topic, err)
+				writeResult(w, a.normalizedError(requestID, http.StatusBadGateway, "BAD_GATEWAY", "Internal service unreachable: "+err.Error(), time.Since(start)))
+			},
+		}
+		proxy.ServeHTTP(w, r)
+	}
+}
+
+func writeResult(w http.ResponseWriter, res model.GatewayResult) {
+	for k, vs := range res.Headers {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(res.StatusCode)
+	_, _ = w.Write(res.Body)
+}
@@ -11,6 +11,7 @@ type RawRequestData struct {
 	Ctx     context.Context
 	Method  string
 	Path    string
+	Query   string // raw query string (?before=&limit=...)
 	Header  http.Header
 	Body    []byte
 	IP      string
@@ -39,6 +40,9 @@ func NewGatewayModel() *GatewayModel {
 		apis.UserService,
 		apis.PostsService,
 		apis.ReactionsService,
+		apis.CommentsService,
+		apis.NotificationsService,
+		apis.RealtimeService,
 	}
 	gateway.TopicAuthMap = make(map[string]bool)
 	gateway.RateLimitMap = make(map[string]int)
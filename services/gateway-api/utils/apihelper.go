@@ -21,6 +21,12 @@ func CopySafeHeaders(src, dst http.Header) {
 	if acc := src.Get("Accept"); acc != "" {
 		dst.Set("Accept", acc)
 	}
+	// Client Hints để feed-service chọn cỡ ảnh phù hợp màn hình
+	for _, h := range []string{"Sec-CH-Viewport-Width", "Viewport-Width", "Sec-CH-DPR", "DPR"} {
+		if v := src.Get(h); v != "" {
+			dst.Set(h, v)
+		}
+	}
 }
 
 func WritePlainError(w http.ResponseWriter, code int, msg string) {
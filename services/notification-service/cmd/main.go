@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+	"notificationservice/internal/app"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Notification Service app
+	apiApp := app.NewNotificationServiceApp()
+
+	// 2. Start the app (starts HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Notification Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Notification Service...")
+	apiApp.Stop()
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"notificationservice/internal/infra/store"
+	"notificationservice/model"
+	"notificationservice/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type Inbox interface {
+	List(userID string, beforeTime *time.Time, beforeID int64, limit int) ([]model.Notification, error)
+	UnreadCount(userID string) (int64, error)
+	MarkRead(userID string, notificationID int64) error
+	MarkAllRead(userID string) (int64, error)
+	GetPreferences(userID string) (model.Preferences, error)
+	UpdatePreferences(userID string, patch model.PreferencesPatch) (model.Preferences, error)
+}
+
+type NotificationAPI struct {
+	InboxInterface Inbox
+}
+
+func NewNotificationAPI(inboxInterface Inbox) *NotificationAPI {
+	return &NotificationAPI{InboxInterface: inboxInterface}
+}
+
+func (api *NotificationAPI) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/notifications", api.handleList).Methods("GET")
+	r.HandleFunc("/notifications/unread-count", api.handleUnreadCount).Methods("GET")
+	r.HandleFunc("/notifications/read-all", api.handleMarkAllRead).Methods("POST")
+	r.HandleFunc("/notifications/{notification_id:[0-9]+}", api.handleMarkRead).Methods("PATCH")
+	r.HandleFunc("/me/notification-preferences", api.handleGetPreferences).Methods("GET")
+	r.HandleFunc("/me/notification-preferences", api.handleUpdatePreferences).Methods("PATCH")
+}
+
+// handleList - GET /notifications?before=&limit=
+func (api *NotificationAPI) handleList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultPageSize
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(n, maxPageSize)
+	}
+
+	var beforeTime *time.Time
+	var beforeID int64
+	if raw := r.URL.Query().Get("before"); raw != "" {
+		t, id, ok := decodeCursor(raw)
+		if !ok {
+			utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		beforeTime, beforeID = &t, id
+	}
+
+	notifications, err := api.InboxInterface.List(userID, beforeTime, beforeID, limit)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to list notifications: "+err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{"notifications": notifications}
+	if len(notifications) == limit {
+		last := notifications[len(notifications)-1]
+		resp["next_cursor"] = encodeCursor(last.UpdatedAt, last.NotificationID)
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (api *NotificationAPI) handleUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	count, err := api.InboxInterface.UnreadCount(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]int64{"count": count})
+}
+
+// handleMarkRead - PATCH /notifications/{id}, body {read: true} (tùy chọn).
+// Notification đã đọc không chuyển lại thành chưa đọc được.
+func (api *NotificationAPI) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Read *bool `json:"read"`
+	}
+
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	notificationID, err := strconv.ParseInt(mux.Vars(r)["notification_id"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Read != nil && !*req.Read {
+		utils.WriteError(w, http.StatusBadRequest, "notifications cannot be marked as unread")
+		return
+	}
+
+	err = api.InboxInterface.MarkRead(userID, notificationID)
+	switch {
+	case errors.Is(err, store.ErrNotificationNotFound):
+		utils.WriteError(w, http.StatusNotFound, "notification not found")
+	case errors.Is(err, store.ErrNotRecipient):
+		utils.WriteError(w, http.StatusForbidden, "Unauthorized")
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+	default:
+		utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "Notification marked as read"})
+	}
+}
+
+func (api *NotificationAPI) handleMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	n, err := api.InboxInterface.MarkAllRead(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]int64{"marked": n})
+}
+
+func (api *NotificationAPI) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	prefs, err := api.InboxInterface.GetPreferences(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, prefs)
+}
+
+func (api *NotificationAPI) handleUpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	var patch model.PreferencesPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	prefs, err := api.InboxInterface.UpdatePreferences(userID, patch)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, prefs)
+}
+
+func requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return "", false
+	}
+	return userID, true
+}
+
+// cursor = base64("<updated_at RFC3339Nano>|<notification_id>")
+func encodeCursor(t time.Time, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(s string) (time.Time, int64, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	ts, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return t, id, true
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"log"
+	"notificationservice/internal/api"
+	"notificationservice/internal/core/followserviceclient"
+	"notificationservice/internal/core/http-server/server"
+	"notificationservice/internal/core/inbox"
+	"notificationservice/internal/core/notificationmanager"
+	"notificationservice/internal/core/notifier"
+	"notificationservice/internal/core/userserviceclient"
+	"notificationservice/internal/infra/eventstream"
+	"notificationservice/internal/infra/realtime"
+	"notificationservice/internal/infra/redisclient"
+	"notificationservice/internal/infra/store"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver          *server.HttpServer
+	notificationapi     *api.NotificationAPI
+	notificationmanager *notificationmanager.NotificationManager
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DBNumber int
+}
+
+func NewNotificationServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.notificationmanager.Start(); err != nil {
+		log.Fatalf("❌ Failed to start notification workers: %v", err)
+	}
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+func (a *App) Stop() {
+	if err := a.notificationmanager.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping notification workers: %v", err)
+	}
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	dbcfg := &store.PostGresConfig{
+		Host:     "localhost", // IP
+		Port:     "5432",      // Port
+		User:     "taopq",     // user_name
+		Password: "123456a@",  // password
+		DBname:   "mydb",      // db
+	}
+
+	redisstorecfg := &RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	notificationstore := store.NewNotificationStore(dbcfg)
+
+	a.notificationmanager = notificationmanager.NewNotificationManager(notificationmanager.NotificationConfig{
+		WorkersPerStream: 2,
+		Retry: eventstream.RetryPolicy{
+			MaxRetries:  5,
+			BaseBackoff: 2 * time.Second,
+			MaxBackoff:  time.Minute,
+		},
+	}, rc, notifier.NewNotifier(
+		notificationstore,
+		followserviceclient.NewFollowServiceClient("http://localhost:9002"),
+		realtime.NewPublisher(rc, realtime.Config{
+			LogMaxLen: 200,
+			LogTTL:    24 * time.Hour,
+			ChunkSize: 500,
+		}),
+		5000, // author nhiều follower hơn thì không báo post mới
+	))
+
+	a.notificationapi = api.NewNotificationAPI(inbox.NewInbox(
+		notificationstore,
+		userserviceclient.NewUserServiceClient("http://localhost:9001"),
+	))
+	router := mux.NewRouter()
+	a.notificationapi.RegisterRoutes(router)
+	a.httpserver = server.NewHttpServer("localhost:9095", router)
+}
@@ -0,0 +1,146 @@
+package inbox
+
+import (
+	"fmt"
+	"log"
+	"notificationservice/internal/core/userserviceclient"
+	"notificationservice/internal/infra/store"
+	"notificationservice/model"
+	"sync"
+	"time"
+)
+
+// số request song song tối đa tới user-service khi lấy profile actor
+const actorLookupConcurrency = 8
+
+// Inbox - phía đọc của notification: list (kèm profile actor + message), unread, mark read, preferences
+type Inbox struct {
+	NotificationStore *store.NotificationStore
+	userserviceclient *userserviceclient.UserService
+}
+
+func NewInbox(notificationStore *store.NotificationStore, userserviceclient_ *userserviceclient.UserService) *Inbox {
+	return &Inbox{
+		NotificationStore: notificationStore,
+		userserviceclient: userserviceclient_,
+	}
+}
+
+func (i *Inbox) List(userID string, beforeTime *time.Time, beforeID int64, limit int) ([]model.Notification, error) {
+	notifications, err := i.NotificationStore.List(userID, beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var actorIDs []string
+	seen := map[string]struct{}{}
+	for _, n := range notifications {
+		for _, a := range n.Actors {
+			if _, ok := seen[a.UserID]; !ok {
+				seen[a.UserID] = struct{}{}
+				actorIDs = append(actorIDs, a.UserID)
+			}
+		}
+	}
+	profiles := i.fetchActors(actorIDs)
+
+	for idx := range notifications {
+		n := &notifications[idx]
+		for j, a := range n.Actors {
+			if p, ok := profiles[a.UserID]; ok {
+				n.Actors[j] = p
+			}
+		}
+		n.Message = message(n)
+	}
+	return notifications, nil
+}
+
+func (i *Inbox) UnreadCount(userID string) (int64, error) {
+	return i.NotificationStore.UnreadCount(userID)
+}
+
+func (i *Inbox) MarkRead(userID string, notificationID int64) error {
+	return i.NotificationStore.MarkRead(userID, notificationID)
+}
+
+func (i *Inbox) MarkAllRead(userID string) (int64, error) {
+	return i.NotificationStore.MarkAllRead(userID)
+}
+
+func (i *Inbox) GetPreferences(userID string) (model.Preferences, error) {
+	return i.NotificationStore.GetPreferences(userID)
+}
+
+func (i *Inbox) UpdatePreferences(userID string, patch model.PreferencesPatch) (model.Preferences, error) {
+	return i.NotificationStore.UpdatePreferences(userID, patch)
+}
+
+// fetchActors gọi user-service song song, actor lấy lỗi chỉ có user_id
+func (i *Inbox) fetchActors(userIDs []string) map[string]model.Actor {
+	actors := make(map[string]model.Actor, len(userIDs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, actorLookupConcurrency)
+
+	for _, userID := range userIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(userID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			profile, err := i.userserviceclient.GetUserProfile(userID)
+			if err != nil {
+				log.Printf("[Inbox] failed to fetch actor %s: %v", userID, err)
+				return
+			}
+			mu.Lock()
+			actors[userID] = model.Actor{UserID: userID, Username: profile.Username, Avatar: profile.AvatarURL}
+			mu.Unlock()
+		}(userID)
+	}
+	wg.Wait()
+	return actors
+}
+
+// message render câu hiển thị, VD "An and 5 others reacted to your post"
+func message(n *model.Notification) string {
+	var who string
+	switch {
+	case len(n.Actors) == 0:
+		who = "Someone"
+	case n.ActorCount == 1:
+		who = actorName(n.Actors[0])
+	case n.ActorCount == 2 && len(n.Actors) >= 2:
+		who = actorName(n.Actors[0]) + " and " + actorName(n.Actors[1])
+	case n.ActorCount == 2:
+		who = actorName(n.Actors[0]) + " and 1 other"
+	default:
+		who = fmt.Sprintf("%s and %d others", actorName(n.Actors[0]), n.ActorCount-1)
+	}
+
+	switch n.Type {
+	case model.TypeFollow:
+		return who + " started following you"
+	case model.TypeReaction:
+		return who + " reacted to your post"
+	case model.TypeComment:
+		return who + " commented on your post"
+	case model.TypeReply:
+		return who + " replied to your comment"
+	case model.TypeNewPost:
+		return who + " shared a new post"
+	case model.TypeMention:
+		return who + " mentioned you in a post"
+	default:
+		return who + " interacted with you"
+	}
+}
+
+func actorName(a model.Actor) string {
+	if a.Username != "" {
+		return a.Username
+	}
+	return "Someone"
+}
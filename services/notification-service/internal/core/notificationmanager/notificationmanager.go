@@ -0,0 +1,62 @@
+package notificationmanager
+
+import (
+	"fmt"
+	"notificationservice/internal/core/notificationmanager/workerpocessor"
+	"notificationservice/internal/core/notifier"
+	"notificationservice/internal/infra/eventstream"
+	"notificationservice/internal/infra/redisclient"
+	"notificationservice/model"
+)
+
+const consumerGroup = "notification-service"
+
+type NotificationConfig struct {
+	WorkersPerStream int
+	Retry            eventstream.RetryPolicy
+}
+
+// NotificationManager chạy worker cho từng stream event mà notification-service consume
+type NotificationManager struct {
+	workers []*workerpocessor.EventWorker
+}
+
+func NewNotificationManager(cfg NotificationConfig, redisclient_ *redisclient.RedisClient, notifier_ *notifier.Notifier) *NotificationManager {
+	handlers := map[string]workerpocessor.EventHandler{
+		model.FollowEventStream:   notifier_.HandleFollowEvent,
+		model.ReactionEventStream: notifier_.HandleReactionEvent,
+		model.CommentEventStream:  notifier_.HandleCommentEvent,
+		model.PostEventStream:     notifier_.HandlePostEvent,
+	}
+
+	m := NotificationManager{}
+	for stream, handler := range handlers {
+		for i := 0; i < cfg.WorkersPerStream; i++ {
+			name := fmt.Sprintf("%s-worker-%d", stream, i)
+			consumer := eventstream.NewConsumer(redisclient_, stream, consumerGroup, name, cfg.Retry)
+			m.workers = append(m.workers, workerpocessor.NewEventWorker(name, consumer, handler))
+		}
+	}
+	return &m
+}
+
+func (m *NotificationManager) Start() error {
+	for _, e := range m.workers {
+		err := e.Start()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop dừng toàn bộ worker sau khi batch đang xử lý được Ack
+func (m *NotificationManager) Stop() error {
+	for _, e := range m.workers {
+		err := e.Stop()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
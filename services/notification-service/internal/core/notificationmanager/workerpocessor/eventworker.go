@@ -0,0 +1,103 @@
+package workerpocessor
+
+import (
+	"fmt"
+	"log"
+	"notificationservice/internal/infra/eventstream"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EventHandler xử lý 1 event (type + payload JSON), lỗi thì message được retry
+type EventHandler func(eventType string, payload []byte) error
+
+// EventWorker đọc 1 stream theo consumer group và chuyển từng event cho handler
+type EventWorker struct {
+	BaseWorkerProcessor
+	name     string
+	consumer *eventstream.Consumer
+	handler  EventHandler
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewEventWorker(name string, consumer_ *eventstream.Consumer, handler_ EventHandler) *EventWorker {
+	s := &EventWorker{
+		name:     name,
+		consumer: consumer_,
+		handler:  handler_,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	s.Init(s)
+	return s
+}
+
+func (s *EventWorker) RunningTask() error {
+	defer close(s.done)
+	if s.consumer == nil {
+		return fmt.Errorf("[%s] event consumer is nil", s.name)
+	}
+	if err := s.consumer.EnsureGroup(); err != nil {
+		return err
+	}
+
+	reclaimTicker := time.NewTicker(time.Second)
+	defer reclaimTicker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-reclaimTicker.C:
+			// retry event lỗi (hoặc của worker đã chết) khi đến hạn backoff
+			messages, err := s.consumer.Reclaim(10)
+			if err != nil {
+				log.Printf("[%s] %v", s.name, err)
+			}
+			s.process(messages)
+		default:
+		}
+
+		messages, err := s.consumer.Read(10, time.Second)
+		if err != nil {
+			log.Printf("[%s] %v", s.name, err)
+			time.Sleep(time.Second)
+			continue
+		}
+		s.process(messages)
+	}
+}
+
+// Stop chờ batch đang xử lý xong; event chưa đọc vẫn nằm trong stream
+func (s *EventWorker) Stop() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// process chỉ Ack sau khi handler xử lý xong, lỗi thì để lại pending cho lần retry sau
+func (s *EventWorker) process(messages []redis.XMessage) {
+	for _, msg := range messages {
+		eventType, _ := msg.Values["type"].(string)
+		payload, ok := msg.Values["payload"].(string)
+		if !ok {
+			log.Printf("[%s] drop malformed message %s", s.name, msg.ID)
+			s.ack(msg.ID)
+			continue
+		}
+		if err := s.handler(eventType, []byte(payload)); err != nil {
+			log.Printf("[%s] failed to handle message %s: %v", s.name, msg.ID, err)
+			s.consumer.Fail(msg.ID, err)
+			continue
+		}
+		s.ack(msg.ID)
+	}
+}
+
+func (s *EventWorker) ack(id string) {
+	if err := s.consumer.Ack(id); err != nil {
+		log.Printf("[%s] %v", s.name, err)
+	}
+}
@@ -0,0 +1,8 @@
+package workerpocessor
+
+type WorkerProcessor interface {
+	Start() error
+	Stop() error
+	Restart() error
+	RunningTask() error
+}
@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"notificationservice/internal/core/followserviceclient"
+	"notificationservice/internal/infra/realtime"
+	"notificationservice/internal/infra/store"
+	"notificationservice/model"
+)
+
+// Notifier chuyển event của các service khác thành notification trong inbox
+type Notifier struct {
+	NotificationStore   *store.NotificationStore
+	followserviceclient *followserviceclient.FollowServiceClient
+	realtime            *realtime.Publisher
+	// author có nhiều follower hơn ngưỡng thì không gửi notification post mới
+	maxNewPostRecipients int
+}
+
+func NewNotifier(notificationStore *store.NotificationStore, followserviceclient_ *followserviceclient.FollowServiceClient,
+	realtime_ *realtime.Publisher, maxNewPostRecipients int) *Notifier {
+	return &Notifier{
+		NotificationStore:    notificationStore,
+		followserviceclient:  followserviceclient_,
+		realtime:             realtime_,
+		maxNewPostRecipients: maxNewPostRecipients,
+	}
+}
+
+// HandleFollowEvent - stream follow:events
+func (n *Notifier) HandleFollowEvent(eventType string, payload []byte) error {
+	if eventType != model.FollowCreated {
+		return nil
+	}
+	var event model.FollowEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
+	// "A and 3 others started following you"
+	return n.notify([]string{event.FolloweeID}, store.NotificationGroup{
+		Type:     model.TypeFollow,
+		GroupKey: model.TypeFollow,
+	}, event.FollowerID)
+}
+
+// HandleReactionEvent - stream reaction:events
+func (n *Notifier) HandleReactionEvent(eventType string, payload []byte) error {
+	if eventType != model.ReactionCreated {
+		return nil
+	}
+	var event model.ReactionEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
+	return n.notify([]string{event.PostAuthorID}, store.NotificationGroup{
+		Type:     model.TypeReaction,
+		GroupKey: model.TypeReaction + ":" + event.PostID,
+		PostID:   event.PostID,
+	}, event.UserID)
+}
+
+// HandleCommentEvent - stream comment:events.
+// Reply báo cho author của comment gốc, chủ post vẫn nhận notification comment như thường.
+func (n *Notifier) HandleCommentEvent(eventType string, payload []byte) error {
+	if eventType != model.CommentCreated {
+		return nil
+	}
+	var event model.CommentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", eventType, err)
+	}
+
+	if event.ParentAuthorID != "" {
+		err := n.notify([]string{event.ParentAuthorID}, store.NotificationGroup{
+			Type:     model.TypeReply,
+			GroupKey: model.TypeReply + ":" + event.ParentID,
+			PostID:   event.PostID,
+			TargetID: event.ParentID,
+		}, event.UserID)
+		if err != nil {
+			return err
+		}
+		if event.ParentAuthorID == event.PostAuthorID {
+			return nil
+		}
+	}
+	return n.notify([]string{event.PostAuthorID}, store.NotificationGroup{
+		Type:     model.TypeComment,
+		GroupKey: model.TypeComment + ":" + event.PostID,
+		PostID:   event.PostID,
+	}, event.UserID)
+}
+
+// HandlePostEvent - stream post:events: post mới (public/followers) báo cho follower của author,
+// user được @mention nhận notification mention (feed-service đã lọc theo visibility),
+// post bị xoá thì xoá các notification liên quan
+func (n *Notifier) HandlePostEvent(eventType string, payload []byte) error {
+	switch eventType {
+	case model.PostCreated:
+		var event model.NewPostEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		switch event.Visibility {
+		case "", model.VisibilityPublic, model.VisibilityFollowers:
+		default:
+			// only_me/close_friends: không phải follower nào cũng được xem, feed-service đã push tới đúng audience
+			return nil
+		}
+		followers, err := n.followserviceclient.GetFollowers(event.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get followers of %s: %w", event.UserID, err)
+		}
+		if len(followers) > n.maxNewPostRecipients {
+			log.Printf("[Notifier] skip new post notifications of %s (%d followers)", event.UserID, len(followers))
+			return nil
+		}
+		return n.notify(followers, store.NotificationGroup{
+			Type:     model.TypeNewPost,
+			GroupKey: model.TypeNewPost + ":" + event.PostID,
+			PostID:   event.PostID,
+		}, event.UserID)
+	case model.PostMentioned:
+		var event model.PostMentionedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		return n.notify(event.MentionedUserIDs, store.NotificationGroup{
+			Type:     model.TypeMention,
+			GroupKey: model.TypeMention + ":" + event.PostID,
+			PostID:   event.PostID,
+		}, event.UserID)
+	case model.PostDeleted:
+		var event model.PostDeletedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", eventType, err)
+		}
+		return n.NotificationStore.DeleteByPost(event.PostID)
+	default:
+		return nil
+	}
+}
+
+// notify bỏ người nhận là chính actor và người đã tắt loại notification này,
+// người nhận có notification thay đổi được báo realtime để client cập nhật badge/inbox
+func (n *Notifier) notify(recipients []string, group store.NotificationGroup, actorID string) error {
+	filtered := make([]string, 0, len(recipients))
+	for _, userID := range recipients {
+		if userID != "" && userID != actorID {
+			filtered = append(filtered, userID)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	enabled, err := n.NotificationStore.FilterEnabled(filtered, group.Type)
+	if err != nil {
+		return err
+	}
+	notified, err := n.NotificationStore.Notify(enabled, group, actorID)
+	if err != nil {
+		return err
+	}
+
+	// notification đã lưu, lỗi realtime không retry event (client vẫn thấy khi mở inbox)
+	if err := n.realtime.Publish(context.Background(), notified, realtime.NotificationCreated, map[string]interface{}{
+		"type":     group.Type,
+		"post_id":  group.PostID,
+		"actor_id": actorID,
+	}); err != nil {
+		log.Printf("[Notifier] %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package eventstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var testPolicy = RetryPolicy{
+	MaxRetries:  5,
+	BaseBackoff: time.Second,
+	MaxBackoff:  10 * time.Second,
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		deliveries int64
+		want       time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second}, // 16s bị chặn ở MaxBackoff
+		{100, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := testPolicy.backoff(tt.deliveries); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.deliveries, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffBaseAboveMax(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 3, BaseBackoff: time.Minute, MaxBackoff: time.Second}
+	if got := p.backoff(1); got != time.Second {
+		t.Errorf("backoff(1) = %v, want %v", got, time.Second)
+	}
+}
+
+func TestRetryPolicyAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		deliveries int64
+		idle       time.Duration
+		want       pendingAction
+	}{
+		{"first delivery still idle", 1, 500 * time.Millisecond, pendingWait},
+		{"first delivery due", 1, time.Second, pendingClaim},
+		{"backoff grows with deliveries", 3, 3 * time.Second, pendingWait},
+		{"third delivery due", 3, 4 * time.Second, pendingClaim},
+		{"last retry due", 5, 10 * time.Second, pendingClaim},
+		{"last retry waits for max backoff", 5, 9 * time.Second, pendingWait},
+		{"over max retries", 6, 0, pendingDeadLetter},
+		{"over max retries ignores backoff", 6, time.Hour, pendingDeadLetter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testPolicy.action(tt.deliveries, tt.idle); got != tt.want {
+				t.Errorf("action(%d, %v) = %v, want %v", tt.deliveries, tt.idle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadLetterValues(t *testing.T) {
+	c := &Consumer{stream: "notifications:events", group: "notifier"}
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"type": "NewPost", "data": `{"post_id":"p1"}`}}
+
+	got := c.deadLetterValues(msg, 6, "boom")
+	want := map[string]interface{}{
+		"source_stream": "notifications:events",
+		"source_id":     "1-0",
+		"group":         "notifier",
+		"deliveries":    int64(6),
+		"last_error":    "boom",
+		"field:type":    "NewPost",
+		"field:data":    `{"post_id":"p1"}`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deadLetterValues() = %v, want %v", got, want)
+	}
+}
+
+func TestDeadLetterValuesKeepsMetadata(t *testing.T) {
+	c := &Consumer{stream: "s", group: "g"}
+	// field gốc trùng tên metadata không ghi đè metadata
+	msg := redis.XMessage{ID: "2-0", Values: map[string]interface{}{"source_id": "spoofed"}}
+
+	got := c.deadLetterValues(msg, 1, "")
+	if got["source_id"] != "2-0" || got["field:source_id"] != "spoofed" {
+		t.Errorf("deadLetterValues() = %v", got)
+	}
+}
+
+func TestSkipsMessagesReplayedForOtherGroup(t *testing.T) {
+	// post events được 2 group đọc: fan-out (feed-service) và notification-service
+	notification := &Consumer{stream: "post:events", group: "notification-service"}
+	if DeadLetterKey(notification.stream, notification.group) == DeadLetterKey(notification.stream, "feed-service-fanout") {
+		t.Fatalf("groups share dead-letter stream")
+	}
+	if errorsKey(notification.stream, notification.group) == errorsKey(notification.stream, "feed-service-fanout") {
+		t.Fatalf("groups share errors hash")
+	}
+
+	msg := redis.XMessage{ID: "1-0", Values: map[string]interface{}{"type": "NewPost", "data": `{"post_id":"p1"}`}}
+	if notification.replayedForOtherGroup(msg) {
+		t.Errorf("normal message skipped")
+	}
+
+	// fan-out replay dead letter của nó vào post events: notification đã xử lý message này rồi
+	msg.Values[ReplayGroupField] = "feed-service-fanout"
+	if !notification.replayedForOtherGroup(msg) {
+		t.Errorf("message replayed for fan-out not skipped")
+	}
+	msg.Values[ReplayGroupField] = notification.group
+	if notification.replayedForOtherGroup(msg) {
+		t.Errorf("message replayed for notification skipped")
+	}
+}
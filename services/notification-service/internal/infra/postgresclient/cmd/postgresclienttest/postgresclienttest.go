@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	dbclient "notificationservice/internal/infra/postgresclient"
+	"notificationservice/internal/infra/postgresclient/tables"
+)
+
+type table interface {
+	CreateTable()
+	Migrate()
+	GetAll() ([]map[string]interface{}, error)
+}
+
+func main() {
+	client := dbclient.NewPostgresClient(
+		"localhost", // IP
+		"5432",      // Port
+		"taopq",     // user_name
+		"123456a@",  // password
+		"mydb",      // db
+	)
+	defer client.Close()
+
+	// Thứ tự tạo bảng theo foreign key: notifications -> notification_actors
+	notificationsTable := tables.NewNotificationsTable(client)
+	actorsTable := tables.NewNotificationActorsTable(client)
+	preferencesTable := tables.NewNotificationPreferencesTable(client)
+
+	for _, tb := range []struct {
+		name string
+		t    table
+	}{
+		{notificationsTable.TableName, notificationsTable},
+		{actorsTable.TableName, actorsTable},
+		{preferencesTable.TableName, preferencesTable},
+	} {
+		if !client.SearchTable(tb.name) {
+			fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", tb.name)
+			tb.t.CreateTable()
+		} else {
+			fmt.Printf("%s EXISTED - MIGRATING\n", tb.name)
+			tb.t.Migrate()
+		}
+
+		rows, err := tb.t.GetAll()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, row := range rows {
+			fmt.Println(row)
+		}
+	}
+}
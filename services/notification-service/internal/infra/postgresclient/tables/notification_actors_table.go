@@ -0,0 +1,28 @@
+package tables
+
+import dbclient "notificationservice/internal/infra/postgresclient"
+
+// NotificationActorsTable kế thừa BaseTable
+type NotificationActorsTable struct {
+	dbclient.BaseTable
+}
+
+// NewNotificationActorsTable - các user đã gây ra 1 notification (gộp),
+// PK đảm bảo 1 actor chỉ được đếm 1 lần kể cả khi event bị giao lại
+func NewNotificationActorsTable(client *dbclient.PostgresClient) *NotificationActorsTable {
+	return &NotificationActorsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "notification_actors",
+			Columns: map[string]string{
+				"notification_id": "BIGINT NOT NULL",
+				"actor_id":        "UUID NOT NULL",
+				"created_at":      "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (notification_id, actor_id)",
+				"FOREIGN KEY (notification_id) REFERENCES notifications(notification_id) ON DELETE CASCADE",
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package tables
+
+import dbclient "notificationservice/internal/infra/postgresclient"
+
+// NotificationPreferencesTable kế thừa BaseTable
+type NotificationPreferencesTable struct {
+	dbclient.BaseTable
+}
+
+// NewNotificationPreferencesTable - user chưa có row thì nhận mọi loại notification
+func NewNotificationPreferencesTable(client *dbclient.PostgresClient) *NotificationPreferencesTable {
+	return &NotificationPreferencesTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "notification_preferences",
+			Columns: map[string]string{
+				"user_id":    "UUID PRIMARY KEY",
+				"follow":     "BOOLEAN NOT NULL DEFAULT TRUE",
+				"reaction":   "BOOLEAN NOT NULL DEFAULT TRUE",
+				"comment":    "BOOLEAN NOT NULL DEFAULT TRUE",
+				"reply":      "BOOLEAN NOT NULL DEFAULT TRUE",
+				"new_post":   "BOOLEAN NOT NULL DEFAULT TRUE",
+				"mention":    "BOOLEAN NOT NULL DEFAULT TRUE",
+				"updated_at": "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			AddedColumns: []string{"mention"},
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package tables
+
+import dbclient "notificationservice/internal/infra/postgresclient"
+
+// NotificationsTable kế thừa BaseTable
+type NotificationsTable struct {
+	dbclient.BaseTable
+}
+
+// NewNotificationsTable khởi tạo table notifications. Mỗi user chỉ có tối đa
+// 1 notification chưa đọc cho mỗi group_key, event mới cùng group được gộp vào đó.
+func NewNotificationsTable(client *dbclient.PostgresClient) *NotificationsTable {
+	return &NotificationsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "notifications",
+			Columns: map[string]string{
+				"notification_id": "BIGSERIAL PRIMARY KEY",
+				"user_id":         "UUID NOT NULL", // người nhận
+				"type":            "VARCHAR(20) NOT NULL",
+				"group_key":       "TEXT NOT NULL",
+				"post_id":         "UUID",
+				"target_id":       "TEXT NOT NULL DEFAULT ''",
+				"actor_count":     "INT NOT NULL DEFAULT 0",
+				"is_read":         "BOOLEAN NOT NULL DEFAULT FALSE",
+				"created_at":      "TIMESTAMP NOT NULL DEFAULT now()",
+				"updated_at":      "TIMESTAMP NOT NULL DEFAULT now()", // lần cuối có actor mới
+				"read_at":         "TIMESTAMP",
+			},
+			Indexes: []string{
+				// NotificationStore.Notify: ON CONFLICT (user_id, group_key) WHERE is_read = FALSE
+				"CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_unread_group ON notifications(user_id, group_key) WHERE is_read = FALSE",
+				"CREATE INDEX IF NOT EXISTS idx_notifications_user_updated ON notifications(user_id, updated_at DESC)",
+				"CREATE INDEX IF NOT EXISTS idx_notifications_post ON notifications(post_id)",
+			},
+		},
+	}
+}
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"notificationservice/model"
+	"time"
+
+	dbclient "notificationservice/internal/infra/postgresclient"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotificationNotFound - notification không tồn tại
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotRecipient - notification thuộc về user khác
+	ErrNotRecipient = errors.New("notification belongs to another user")
+)
+
+// recentActors - số actor gần nhất trả về kèm mỗi notification
+const recentActors = 3
+
+type NotificationStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+type PostGresConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBname   string
+}
+
+func NewNotificationStore(postgrescfg *PostGresConfig) *NotificationStore {
+	return &NotificationStore{
+		DBClient: dbclient.NewPostgresClient(
+			postgrescfg.Host,
+			postgrescfg.Port,
+			postgrescfg.User,
+			postgrescfg.Password,
+			postgrescfg.DBname,
+		),
+	}
+}
+
+// NotificationGroup - các event cùng group được gộp vào 1 notification chưa đọc của mỗi người nhận
+type NotificationGroup struct {
+	Type     string
+	GroupKey string
+	PostID   string // "" nếu không gắn với post (follow)
+	TargetID string
+}
+
+// Notify gộp actor vào notification chưa đọc của group cho từng người nhận
+// (chưa có thì tạo mới). Actor đã có trong notification thì không đếm lại nên
+// event bị giao lại (at-least-once) không làm tăng actor_count.
+// Trả về những người nhận có actor mới (cần báo realtime).
+func (s *NotificationStore) Notify(recipients []string, group NotificationGroup, actorID string) ([]string, error) {
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.DBClient.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var postID interface{}
+	if group.PostID != "" {
+		postID = group.PostID
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO notifications (user_id, type, group_key, post_id, target_id)
+		SELECT unnest($1::uuid[]), $2, $3, $4, $5
+		ON CONFLICT (user_id, group_key) WHERE is_read = FALSE DO NOTHING`,
+		pq.Array(recipients), group.Type, group.GroupKey, postID, group.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s notifications: %w", group.GroupKey, err)
+	}
+
+	rows, err := tx.Query(`
+		WITH added AS (
+			INSERT INTO notification_actors (notification_id, actor_id)
+			SELECT notification_id, $3 FROM notifications
+			WHERE user_id = ANY($1) AND group_key = $2 AND is_read = FALSE
+			ON CONFLICT DO NOTHING
+			RETURNING notification_id
+		)
+		UPDATE notifications n SET actor_count = n.actor_count + 1, updated_at = now()
+		FROM added WHERE n.notification_id = added.notification_id
+		RETURNING n.user_id::text`,
+		pq.Array(recipients), group.GroupKey, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add actor to %s notifications: %w", group.GroupKey, err)
+	}
+	var notified []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan recipient: %w", err)
+		}
+		notified = append(notified, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit notifications: %w", err)
+	}
+	return notified, nil
+}
+
+// List inbox của user, notification có hoạt động gần nhất trước.
+// Keyset theo (updated_at, notification_id) của phần tử cuối trang trước.
+// Actors chỉ có user_id, caller tự hydrate profile.
+func (s *NotificationStore) List(userID string, beforeTime *time.Time, beforeID int64, limit int) ([]model.Notification, error) {
+	query := `
+		SELECT n.notification_id, n.type, n.post_id, n.target_id, n.actor_count, n.is_read,
+		       n.created_at, n.updated_at, n.read_at,
+		       ARRAY(SELECT a.actor_id::text FROM notification_actors a
+		             WHERE a.notification_id = n.notification_id
+		             ORDER BY a.created_at DESC LIMIT $5)
+		FROM notifications n
+		WHERE n.user_id = $1 AND n.actor_count > 0
+		  AND ($2::timestamp IS NULL OR (n.updated_at, n.notification_id) < ($2, $3))
+		ORDER BY n.updated_at DESC, n.notification_id DESC
+		LIMIT $4`
+
+	var before interface{}
+	if beforeTime != nil {
+		before = *beforeTime
+	}
+	rows, err := s.DBClient.DB.Query(query, userID, before, beforeID, limit, recentActors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications of %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		var postID sql.NullString
+		var readAt sql.NullTime
+		var actorIDs []string
+		if err := rows.Scan(&n.NotificationID, &n.Type, &postID, &n.TargetID, &n.ActorCount, &n.IsRead,
+			&n.CreatedAt, &n.UpdatedAt, &readAt, pq.Array(&actorIDs)); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if postID.Valid {
+			n.PostID = &postID.String
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		n.Actors = make([]model.Actor, len(actorIDs))
+		for i, id := range actorIDs {
+			n.Actors[i] = model.Actor{UserID: id}
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return notifications, nil
+}
+
+func (s *NotificationStore) UnreadCount(userID string) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE AND actor_count > 0`
+	if err := s.DBClient.DB.QueryRow(query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications of %s: %w", userID, err)
+	}
+	return count, nil
+}
+
+// MarkRead đánh dấu đã đọc, event sau cùng group sẽ tạo notification mới
+func (s *NotificationStore) MarkRead(userID string, notificationID int64) error {
+	res, err := s.DBClient.DB.Exec(`
+		UPDATE notifications SET is_read = TRUE, read_at = COALESCE(read_at, now())
+		WHERE notification_id = $1 AND user_id = $2`, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification %d as read: %w", notificationID, err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	// không update được: không tồn tại hoặc của user khác
+	var exists bool
+	err = s.DBClient.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM notifications WHERE notification_id = $1)`, notificationID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to look up notification %d: %w", notificationID, err)
+	}
+	if !exists {
+		return ErrNotificationNotFound
+	}
+	return ErrNotRecipient
+}
+
+func (s *NotificationStore) MarkAllRead(userID string) (int64, error) {
+	res, err := s.DBClient.DB.Exec(`
+		UPDATE notifications SET is_read = TRUE, read_at = now()
+		WHERE user_id = $1 AND is_read = FALSE`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications of %s as read: %w", userID, err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// DeleteByPost xoá mọi notification gắn với post đã bị xoá
+func (s *NotificationStore) DeleteByPost(postID string) error {
+	if _, err := s.DBClient.DB.Exec(`DELETE FROM notifications WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("failed to delete notifications of post %s: %w", postID, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"notificationservice/model"
+
+	"github.com/lib/pq"
+)
+
+// preferenceColumns - notification type -> cột trong notification_preferences
+var preferenceColumns = map[string]string{
+	model.TypeFollow:   "follow",
+	model.TypeReaction: "reaction",
+	model.TypeComment:  "comment",
+	model.TypeReply:    "reply",
+	model.TypeNewPost:  "new_post",
+	model.TypeMention:  "mention",
+}
+
+// GetPreferences - user chưa từng đổi thì trả về mặc định (bật hết)
+func (s *NotificationStore) GetPreferences(userID string) (model.Preferences, error) {
+	var p model.Preferences
+	err := s.DBClient.DB.QueryRow(`
+		SELECT follow, reaction, comment, reply, new_post, mention
+		FROM notification_preferences WHERE user_id = $1`, userID).
+		Scan(&p.Follow, &p.Reaction, &p.Comment, &p.Reply, &p.NewPost, &p.Mention)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.DefaultPreferences(), nil
+	}
+	if err != nil {
+		return model.Preferences{}, fmt.Errorf("failed to get preferences of %s: %w", userID, err)
+	}
+	return p, nil
+}
+
+// UpdatePreferences chỉ ghi các field có trong patch, field còn lại giữ nguyên (hoặc mặc định)
+func (s *NotificationStore) UpdatePreferences(userID string, patch model.PreferencesPatch) (model.Preferences, error) {
+	var p model.Preferences
+	err := s.DBClient.DB.QueryRow(`
+		INSERT INTO notification_preferences (user_id, follow, reaction, comment, reply, new_post, mention, updated_at)
+		VALUES ($1, COALESCE($2, TRUE), COALESCE($3, TRUE), COALESCE($4, TRUE), COALESCE($5, TRUE), COALESCE($6, TRUE), COALESCE($7, TRUE), now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			follow     = COALESCE($2, notification_preferences.follow),
+			reaction   = COALESCE($3, notification_preferences.reaction),
+			comment    = COALESCE($4, notification_preferences.comment),
+			reply      = COALESCE($5, notification_preferences.reply),
+			new_post   = COALESCE($6, notification_preferences.new_post),
+			mention    = COALESCE($7, notification_preferences.mention),
+			updated_at = now()
+		RETURNING follow, reaction, comment, reply, new_post, mention`,
+		userID, patch.Follow, patch.Reaction, patch.Comment, patch.Reply, patch.NewPost, patch.Mention).
+		Scan(&p.Follow, &p.Reaction, &p.Comment, &p.Reply, &p.NewPost, &p.Mention)
+	if err != nil {
+		return model.Preferences{}, fmt.Errorf("failed to update preferences of %s: %w", userID, err)
+	}
+	return p, nil
+}
+
+// FilterEnabled trả về các user trong userIDs không tắt loại notification notifType
+func (s *NotificationStore) FilterEnabled(userIDs []string, notifType string) ([]string, error) {
+	column, ok := preferenceColumns[notifType]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification type %q", notifType)
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.DBClient.DB.Query(`
+		SELECT user_id::text FROM notification_preferences
+		WHERE user_id = ANY($1) AND `+column+` = FALSE`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	defer rows.Close()
+
+	disabled := map[string]struct{}{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan preference: %w", err)
+		}
+		disabled[userID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	enabled := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, off := disabled[userID]; !off {
+			enabled = append(enabled, userID)
+		}
+	}
+	return enabled, nil
+}
@@ -0,0 +1,130 @@
+package model
+
+import "time"
+
+// ---- Notification types ----
+const (
+	TypeFollow   = "follow"
+	TypeReaction = "reaction"
+	TypeComment  = "comment"
+	TypeReply    = "reply"
+	TypeNewPost  = "new_post"
+	TypeMention  = "mention"
+)
+
+var NotificationTypes = []string{TypeFollow, TypeReaction, TypeComment, TypeReply, TypeNewPost, TypeMention}
+
+// Notification - 1 dòng trong inbox. Các event cùng GroupKey khi notification
+// còn chưa đọc được gộp lại ("A and 5 others liked your post").
+type Notification struct {
+	NotificationID int64      `json:"notification_id"`
+	Type           string     `json:"type"`
+	PostID         *string    `json:"post_id,omitempty"`
+	TargetID       string     `json:"target_id,omitempty"` // comment_id với reply
+	Actors         []Actor    `json:"actors"`              // tối đa vài actor gần nhất
+	ActorCount     int        `json:"actor_count"`
+	Message        string     `json:"message"`
+	IsRead         bool       `json:"is_read"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+}
+
+type Actor struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"name,omitempty"`
+	Avatar   string `json:"avatar_url,omitempty"`
+}
+
+// Preferences - user tắt loại notification nào thì event loại đó bị bỏ qua
+type Preferences struct {
+	Follow   bool `json:"follow"`
+	Reaction bool `json:"reaction"`
+	Comment  bool `json:"comment"`
+	Reply    bool `json:"reply"`
+	NewPost  bool `json:"new_post"`
+	Mention  bool `json:"mention"`
+}
+
+func DefaultPreferences() Preferences {
+	return Preferences{Follow: true, Reaction: true, Comment: true, Reply: true, NewPost: true, Mention: true}
+}
+
+// PreferencesPatch - PATCH chỉ đổi các field được gửi lên
+type PreferencesPatch struct {
+	Follow   *bool `json:"follow"`
+	Reaction *bool `json:"reaction"`
+	Comment  *bool `json:"comment"`
+	Reply    *bool `json:"reply"`
+	NewPost  *bool `json:"new_post"`
+	Mention  *bool `json:"mention"`
+}
+
+// ---- Events consume từ các service khác ----
+const (
+	FollowEventStream   = "follow:events"
+	ReactionEventStream = "reaction:events"
+	CommentEventStream  = "comment:events"
+	PostEventStream     = "post:events"
+
+	FollowCreated   = "FollowCreated"
+	ReactionCreated = "ReactionCreated"
+	CommentCreated  = "CommentCreated"
+	PostCreated     = "PostCreated"
+	PostDeleted     = "PostDeleted"
+	PostMentioned   = "PostMentioned"
+)
+
+// post visibility của feed-service; new_post chỉ gửi cho post mà mọi follower được xem
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+)
+
+type FollowEvent struct {
+	Type       string    `json:"type"`
+	FollowerID string    `json:"follower_id"`
+	FolloweeID string    `json:"followee_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type ReactionEvent struct {
+	Type         string    `json:"type"`
+	PostID       string    `json:"post_id"`
+	PostAuthorID string    `json:"post_author_id"`
+	UserID       string    `json:"user_id"`
+	Reaction     string    `json:"reaction"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+type CommentEvent struct {
+	Type           string    `json:"type"`
+	CommentID      string    `json:"comment_id"`
+	PostID         string    `json:"post_id"`
+	PostAuthorID   string    `json:"post_author_id"`
+	ParentID       string    `json:"parent_id,omitempty"`
+	ParentAuthorID string    `json:"parent_author_id,omitempty"`
+	UserID         string    `json:"user_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+type NewPostEvent struct {
+	PostID     string    `json:"post_id"`
+	UserID     string    `json:"user_id"`
+	Visibility string    `json:"visibility,omitempty"` // rỗng = public (event cũ)
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PostDeletedEvent struct {
+	PostID    string    `json:"post_id"`
+	UserID    string    `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// PostMentionedEvent - feed-service báo user được @mention lần đầu trong post (tạo hoặc sửa)
+type PostMentionedEvent struct {
+	PostID           string    `json:"post_id"`
+	UserID           string    `json:"user_id"` // author
+	MentionedUserIDs []string  `json:"mentioned_user_ids"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"reactionservice/internal/app"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Reaction Service app
+	apiApp := app.NewReactionServiceApp()
+
+	// 2. Start the app (starts HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Reaction Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Reaction Service...")
+	apiApp.Stop()
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reactionservice/internal/core/feedserviceclient"
+	"reactionservice/model"
+	"reactionservice/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxBatchSize    = 100
+)
+
+type ReactionStore interface {
+	React(postID, userID, reactionType string) (string, error)
+	Unreact(postID, userID string) (string, error)
+	GetViewerReactions(viewerID string, postIDs []string) (map[string]string, error)
+	ListReactions(postID, reactionType string, beforeTime *time.Time, beforeUserID string, limit int) ([]model.Reaction, error)
+}
+
+type ReactionCounter interface {
+	Apply(postID, previous, current string)
+	GetCounts(postIDs []string) (map[string]map[string]int64, error)
+}
+
+type FeedServiceClient interface {
+	GetPost(viewerID, postID string) (feedserviceclient.Post, error)
+}
+
+type EventPublisher interface {
+	Publish(event model.ReactionEvent) error
+}
+
+type ReactionAPI struct {
+	reactionStore     ReactionStore
+	reactionCounter   ReactionCounter
+	feedServiceClient FeedServiceClient
+	eventPublisher    EventPublisher
+}
+
+func NewReactionAPI(reactionStore_ ReactionStore, reactionCounter_ ReactionCounter, feedServiceClient_ FeedServiceClient,
+	eventPublisher_ EventPublisher) *ReactionAPI {
+	return &ReactionAPI{
+		reactionStore:     reactionStore_,
+		reactionCounter:   reactionCounter_,
+		feedServiceClient: feedServiceClient_,
+		eventPublisher:    eventPublisher_,
+	}
+}
+
+func (api *ReactionAPI) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/posts/{post_id}/reactions", api.handleReact).Methods("POST")
+	r.HandleFunc("/posts/{post_id}/reactions", api.handleUnreact).Methods("DELETE")
+	r.HandleFunc("/posts/{post_id}/reactions", api.handleGetReactions).Methods("GET")
+	// internal: feed-service lấy counter + reaction của viewer cho 1 trang feed
+	r.HandleFunc("/reactions/summary", api.handleGetSummaries).Methods("GET")
+}
+
+func (api *ReactionAPI) handleReact(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		Type string `json:"type"`
+	}
+
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	postID := mux.Vars(r)["post_id"]
+	if !isUUID(postID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid post_id")
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if !model.IsValidReaction(req.Type) {
+		utils.WriteError(w, http.StatusBadRequest, "type must be one of "+strings.Join(model.ReactionTypes, ", "))
+		return
+	}
+
+	post, err := api.feedServiceClient.GetPost(userID, postID)
+	if err != nil {
+		if errors.Is(err, feedserviceclient.ErrPostNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		utils.WriteError(w, http.StatusBadGateway, "failed to verify post: "+err.Error())
+		return
+	}
+
+	previous, err := api.reactionStore.React(postID, userID, req.Type)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to react: "+err.Error())
+		return
+	}
+	api.reactionCounter.Apply(postID, previous, req.Type)
+
+	if previous == "" {
+		// reaction đã lưu, publish lỗi chỉ mất notification
+		if err := api.eventPublisher.Publish(model.ReactionEvent{
+			Type:         model.ReactionCreated,
+			PostID:       postID,
+			PostAuthorID: post.Author.UserID,
+			UserID:       userID,
+			Reaction:     req.Type,
+			OccurredAt:   time.Now().UTC(),
+		}); err != nil {
+			log.Printf("[ReactionAPI] %v", err)
+		}
+	}
+
+	resp := map[string]interface{}{
+		"post_id": postID,
+		"type":    req.Type,
+	}
+	if previous != "" {
+		resp["previous"] = previous
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+func (api *ReactionAPI) handleUnreact(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return
+	}
+
+	postID := mux.Vars(r)["post_id"]
+	if !isUUID(postID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid post_id")
+		return
+	}
+	previous, err := api.reactionStore.Unreact(postID, userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to remove reaction: "+err.Error())
+		return
+	}
+	if previous == "" {
+		utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "No reaction to remove"})
+		return
+	}
+	api.reactionCounter.Apply(postID, previous, "")
+
+	utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "Reaction removed"})
+}
+
+// handleGetReactions trả về counter, reaction của viewer và danh sách ai đã react
+//
+//	GET /posts/{post_id}/reactions?type=&before=&limit=
+func (api *ReactionAPI) handleGetReactions(w http.ResponseWriter, r *http.Request) {
+	postID := mux.Vars(r)["post_id"]
+	if !isUUID(postID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid post_id")
+		return
+	}
+	viewerID := r.Header.Get("X-User-ID")
+	q := r.URL.Query()
+
+	reactionType := q.Get("type")
+	if reactionType != "" && !model.IsValidReaction(reactionType) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid reaction type")
+		return
+	}
+
+	limit := defaultPageSize
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		if n > maxPageSize {
+			n = maxPageSize
+		}
+		limit = n
+	}
+
+	var beforeTime *time.Time
+	var beforeUserID string
+	if raw := q.Get("before"); raw != "" {
+		t, userID, ok := decodeCursor(raw)
+		if !ok {
+			utils.WriteError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		beforeTime, beforeUserID = &t, userID
+	}
+
+	// viewer không được xem post thì cũng không được xem reaction của post
+	if _, err := api.feedServiceClient.GetPost(viewerID, postID); err != nil {
+		if errors.Is(err, feedserviceclient.ErrPostNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		utils.WriteError(w, http.StatusBadGateway, "failed to verify post: "+err.Error())
+		return
+	}
+
+	summaries, err := api.summaries(viewerID, []string{postID})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	reactions, err := api.reactionStore.ListReactions(postID, reactionType, beforeTime, beforeUserID, limit)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"post_id":   postID,
+		"summary":   summaries[postID],
+		"reactions": reactions,
+	}
+	if len(reactions) == limit {
+		last := reactions[len(reactions)-1]
+		resp["next_cursor"] = encodeCursor(last.CreatedAt, last.UserID)
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// handleGetSummaries - GET /reactions/summary?post_ids=a,b,c (viewer = X-User-ID)
+func (api *ReactionAPI) handleGetSummaries(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("post_ids")
+	if raw == "" {
+		utils.WriteError(w, http.StatusBadRequest, "post_ids is required")
+		return
+	}
+	postIDs := strings.Split(raw, ",")
+	if len(postIDs) > maxBatchSize {
+		utils.WriteError(w, http.StatusBadRequest, "too many post_ids (max "+strconv.Itoa(maxBatchSize)+")")
+		return
+	}
+	for _, postID := range postIDs {
+		if !isUUID(postID) {
+			utils.WriteError(w, http.StatusBadRequest, "invalid post_id "+strconv.Quote(postID))
+			return
+		}
+	}
+
+	summaries, err := api.summaries(r.Header.Get("X-User-ID"), postIDs)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, map[string]interface{}{"summaries": summaries})
+}
+
+// isUUID - post_id không phải UUID thì Postgres trả lỗi cast (500), chặn trước bằng 400
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+func (api *ReactionAPI) summaries(viewerID string, postIDs []string) (map[string]model.Summary, error) {
+	counts, err := api.reactionCounter.GetCounts(postIDs)
+	if err != nil {
+		return nil, err
+	}
+	viewerReactions, err := api.reactionStore.GetViewerReactions(viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]model.Summary, len(postIDs))
+	for _, postID := range postIDs {
+		summary := model.Summary{Counts: counts[postID]}
+		if summary.Counts == nil {
+			summary.Counts = map[string]int64{}
+		}
+		for _, n := range summary.Counts {
+			summary.Total += n
+		}
+		if t, ok := viewerReactions[postID]; ok {
+			summary.ViewerReaction = &t
+		}
+		summaries[postID] = summary
+	}
+	return summaries, nil
+}
+
+// cursor = base64("<created_at RFC3339Nano>|<user_id>")
+func encodeCursor(t time.Time, userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano) + "|" + userID))
+}
+
+func decodeCursor(s string) (time.Time, string, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	ts, userID, ok := strings.Cut(string(raw), "|")
+	if !ok || userID == "" {
+		return time.Time{}, "", false
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return t, userID, true
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"log"
+	"reactionservice/internal/api"
+	"reactionservice/internal/core/feedserviceclient"
+	"reactionservice/internal/core/http-server/server"
+	"reactionservice/internal/core/reactioncounter"
+	"reactionservice/internal/core/reactionevent"
+	"reactionservice/internal/infra/redisclient"
+	"reactionservice/internal/infra/store"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver  *server.HttpServer
+	reactionapi *api.ReactionAPI
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DBNumber int
+}
+
+func NewReactionServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+func (a *App) Stop() {
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	dbcfg := &store.PostGresConfig{
+		Host:     "localhost", // IP
+		Port:     "5432",      // Port
+		User:     "taopq",     // user_name
+		Password: "123456a@",  // password
+		DBname:   "mydb",      // db
+	}
+
+	redisstorecfg := &RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	reactionstore := store.NewReactionStore(dbcfg)
+
+	a.reactionapi = api.NewReactionAPI(
+		reactionstore,
+		reactioncounter.NewReactionCounter(reactionstore, rc, 24*time.Hour),
+		feedserviceclient.NewFeedServiceClient("http://localhost:9092"),
+		reactionevent.NewPublisher(rc),
+	)
+	router := mux.NewRouter()
+	a.reactionapi.RegisterRoutes(router)
+	a.httpserver = server.NewHttpServer("localhost:9093", router)
+}
@@ -0,0 +1,139 @@
+package reactioncounter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reactionservice/internal/infra/redisclient"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CountStore interface {
+	CountByType(postIDs []string) (map[string]map[string]int64, error)
+}
+
+// applyScript chỉ HINCRBY khi counter đang có trong cache, cache miss thì
+// lần đọc sau tự load lại từ Postgres nên không bị lệch.
+//
+//	KEYS[1] = post:{id}:reactions, ARGV[1] = type cũ ("" = không có), ARGV[2] = type mới ("" = xoá)
+var applyScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if ARGV[1] ~= '' then
+	redis.call('HINCRBY', KEYS[1], ARGV[1], -1)
+end
+if ARGV[2] ~= '' then
+	redis.call('HINCRBY', KEYS[1], ARGV[2], 1)
+end
+return 1
+`)
+
+// ReactionCounter giữ số reaction theo type của mỗi post trong Redis hash
+// post:{id}:reactions (field = type). Counter có TTL nên nếu lệch sẽ tự sửa sau khi expire.
+type ReactionCounter struct {
+	store       CountStore
+	redisclient *redisclient.RedisClient
+	ttl         time.Duration
+}
+
+func NewReactionCounter(store_ CountStore, redisclient_ *redisclient.RedisClient, ttl time.Duration) *ReactionCounter {
+	return &ReactionCounter{
+		store:       store_,
+		redisclient: redisclient_,
+		ttl:         ttl,
+	}
+}
+
+// emptyMarker - field giữ cho hash tồn tại khi post chưa có reaction nào
+const emptyMarker = "_"
+
+func countersKey(postID string) string {
+	return fmt.Sprintf("post:%s:reactions", postID)
+}
+
+// Apply cập nhật counter sau khi reaction của 1 user đổi từ previous sang current
+func (c *ReactionCounter) Apply(postID, previous, current string) {
+	if previous == current {
+		return
+	}
+	err := applyScript.Run(context.Background(), c.redisclient.GetClient(), []string{countersKey(postID)}, previous, current).Err()
+	if err != nil {
+		// counter sẽ được load lại từ Postgres khi expire
+		log.Printf("[ReactionCounter] failed to update counters of post %s: %v", postID, err)
+	}
+}
+
+// GetCounts đọc counter của nhiều post bằng 1 pipeline, post chưa có cache thì đếm từ Postgres
+func (c *ReactionCounter) GetCounts(postIDs []string) (map[string]map[string]int64, error) {
+	ctx := context.Background()
+	result := make(map[string]map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(postIDs))
+	_, err := c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, postID := range postIDs {
+			cmds[i] = pipe.HGetAll(ctx, countersKey(postID))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ReactionCounter] failed to read counters: %v", err)
+	}
+
+	var misses []string
+	for i, postID := range postIDs {
+		fields, err := cmds[i].Result()
+		if err != nil || len(fields) == 0 {
+			misses = append(misses, postID)
+			continue
+		}
+		counts := map[string]int64{}
+		for reactionType, raw := range fields {
+			n, _ := strconv.ParseInt(raw, 10, 64)
+			if n > 0 && reactionType != emptyMarker {
+				counts[reactionType] = n
+			}
+		}
+		result[postID] = counts
+	}
+
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	loaded, err := c.store.CountByType(misses)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, postID := range misses {
+			key := countersKey(postID)
+			// post chưa có reaction vẫn cache 1 field marker để không đếm lại mỗi lần đọc
+			fields := map[string]interface{}{emptyMarker: 0}
+			for reactionType, n := range loaded[postID] {
+				fields[reactionType] = n
+			}
+			pipe.HSet(ctx, key, fields)
+			pipe.Expire(ctx, key, c.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ReactionCounter] failed to cache counters: %v", err)
+	}
+
+	for _, postID := range misses {
+		counts := loaded[postID]
+		if counts == nil {
+			counts = map[string]int64{}
+		}
+		result[postID] = counts
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package reactionevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reactionservice/internal/infra/redisclient"
+	"reactionservice/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamKey - Redis Stream chứa reaction events
+const StreamKey = "reaction:events"
+
+// maxStreamLen giới hạn (xấp xỉ) số event giữ lại trong stream
+const maxStreamLen = 100000
+
+type Publisher struct {
+	redisclient *redisclient.RedisClient
+}
+
+func NewPublisher(redisclient_ *redisclient.RedisClient) *Publisher {
+	return &Publisher{
+		redisclient: redisclient_,
+	}
+}
+
+// Publish append event vào stream, consumer group của từng service tự đọc
+func (p *Publisher) Publish(event model.ReactionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("[ReactionEventPublisher] failed to marshal event: %w", err)
+	}
+
+	err = p.redisclient.GetClient().XAdd(context.Background(), &redis.XAddArgs{
+		Stream: StreamKey,
+		MaxLen: maxStreamLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"type":    event.Type,
+			"payload": payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("[ReactionEventPublisher] failed to publish %s: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	dbclient "reactionservice/internal/infra/postgresclient"
+	"reactionservice/internal/infra/postgresclient/tables"
+)
+
+func main() {
+	client := dbclient.NewPostgresClient(
+		"localhost", // IP
+		"5432",      // Port
+		"taopq",     // user_name
+		"123456a@",  // password
+		"mydb",      // db
+	)
+	defer client.Close()
+
+	// Tạo bảng reactions
+	reactionsTable := tables.NewReactionsTable(client)
+
+	if !client.SearchTable(reactionsTable.TableName) {
+		fmt.Printf("%s NOT EXIST - CREATION PROCESS STARTING\n", reactionsTable.TableName)
+		reactionsTable.CreateTable()
+	} else {
+		fmt.Printf("%s EXISTED\n", reactionsTable.TableName)
+	}
+
+	// Lấy tất cả reactions
+	rows, err := reactionsTable.GetAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Println(row)
+	}
+}
@@ -0,0 +1,27 @@
+package tables
+
+import dbclient "reactionservice/internal/infra/postgresclient"
+
+// ReactionsTable kế thừa BaseTable
+type ReactionsTable struct {
+	dbclient.BaseTable
+}
+
+// NewReactionsTable khởi tạo table reactions, mỗi user tối đa 1 reaction trên 1 post
+func NewReactionsTable(client *dbclient.PostgresClient) *ReactionsTable {
+	return &ReactionsTable{
+		BaseTable: dbclient.BaseTable{
+			Client:    client,
+			TableName: "reactions",
+			Columns: map[string]string{
+				"post_id":    "UUID NOT NULL",
+				"user_id":    "UUID NOT NULL",
+				"type":       "VARCHAR(10) NOT NULL CHECK (type IN ('like','love','haha','wow','sad','angry'))",
+				"created_at": "TIMESTAMP NOT NULL DEFAULT now()",
+			},
+			Constraints: []string{
+				"PRIMARY KEY (post_id, user_id)",
+			},
+		},
+	}
+}
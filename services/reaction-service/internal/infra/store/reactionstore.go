@@ -0,0 +1,185 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reactionservice/model"
+	"time"
+
+	dbclient "reactionservice/internal/infra/postgresclient"
+
+	"github.com/lib/pq"
+)
+
+type ReactionStore struct {
+	DBClient *dbclient.PostgresClient
+}
+
+type PostGresConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBname   string
+}
+
+func NewReactionStore(postgrescfg *PostGresConfig) *ReactionStore {
+	return &ReactionStore{
+		DBClient: dbclient.NewPostgresClient(
+			postgrescfg.Host,
+			postgrescfg.Port,
+			postgrescfg.User,
+			postgrescfg.Password,
+			postgrescfg.DBname,
+		),
+	}
+}
+
+// React tạo hoặc đổi reaction của user trên post.
+// Trả về type trước đó ("" nếu trước đó chưa react) để cập nhật counter.
+func (s *ReactionStore) React(postID, userID, reactionType string) (string, error) {
+	tx, err := s.DBClient.DB.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRow(`SELECT type FROM reactions WHERE post_id = $1 AND user_id = $2 FOR UPDATE`, postID, userID).Scan(&previous)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// ON CONFLICT: 2 request đồng thời cùng insert thì request sau thành update
+		_, err = tx.Exec(`
+			INSERT INTO reactions (post_id, user_id, type, created_at)
+			VALUES ($1, $2, $3, now())
+			ON CONFLICT (post_id, user_id) DO UPDATE SET type = EXCLUDED.type, created_at = now()`,
+			postID, userID, reactionType)
+	case err != nil:
+		return "", fmt.Errorf("failed to read reaction of %s on %s: %w", userID, postID, err)
+	case previous == reactionType:
+		return previous, nil
+	default:
+		_, err = tx.Exec(`UPDATE reactions SET type = $3, created_at = now() WHERE post_id = $1 AND user_id = $2`,
+			postID, userID, reactionType)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to save reaction of %s on %s: %w", userID, postID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit reaction: %w", err)
+	}
+	return previous, nil
+}
+
+// Unreact xoá reaction, trả về type đã xoá ("" nếu chưa từng react)
+func (s *ReactionStore) Unreact(postID, userID string) (string, error) {
+	var previous string
+	err := s.DBClient.DB.QueryRow(`DELETE FROM reactions WHERE post_id = $1 AND user_id = $2 RETURNING type`,
+		postID, userID).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to delete reaction of %s on %s: %w", userID, postID, err)
+	}
+	return previous, nil
+}
+
+// CountByType đếm reaction theo type cho nhiều post (dùng khi cache counter miss)
+func (s *ReactionStore) CountByType(postIDs []string) (map[string]map[string]int64, error) {
+	result := make(map[string]map[string]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.DBClient.DB.Query(`
+		SELECT post_id, type, COUNT(*)
+		FROM reactions
+		WHERE post_id = ANY($1)
+		GROUP BY post_id, type`, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, reactionType string
+		var count int64
+		if err := rows.Scan(&postID, &reactionType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction count: %w", err)
+		}
+		if result[postID] == nil {
+			result[postID] = map[string]int64{}
+		}
+		result[postID][reactionType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
+
+// GetViewerReactions trả về reaction của viewer trên các post (post chưa react thì không có trong map)
+func (s *ReactionStore) GetViewerReactions(viewerID string, postIDs []string) (map[string]string, error) {
+	result := make(map[string]string, len(postIDs))
+	if len(postIDs) == 0 || viewerID == "" {
+		return result, nil
+	}
+
+	rows, err := s.DBClient.DB.Query(`SELECT post_id, type FROM reactions WHERE user_id = $1 AND post_id = ANY($2)`,
+		viewerID, pq.Array(postIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reactions of %s: %w", viewerID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, reactionType string
+		if err := rows.Scan(&postID, &reactionType); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		result[postID] = reactionType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return result, nil
+}
+
+// ListReactions liệt kê ai đã react post (mới nhất trước), lọc theo type nếu có.
+// Phân trang keyset theo (created_at, user_id) của phần tử cuối trang trước.
+func (s *ReactionStore) ListReactions(postID, reactionType string, beforeTime *time.Time, beforeUserID string, limit int) ([]model.Reaction, error) {
+	query := `
+		SELECT post_id, user_id, type, created_at
+		FROM reactions
+		WHERE post_id = $1
+		  AND ($2 = '' OR type = $2)
+		  AND ($3::timestamp IS NULL OR (created_at, user_id::text) < ($3, $4))
+		ORDER BY created_at DESC, user_id::text DESC
+		LIMIT $5`
+
+	var before interface{}
+	if beforeTime != nil {
+		before = *beforeTime
+	}
+	rows, err := s.DBClient.DB.Query(query, postID, reactionType, before, beforeUserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reactions of %s: %w", postID, err)
+	}
+	defer rows.Close()
+
+	reactions := []model.Reaction{}
+	for rows.Next() {
+		var r model.Reaction
+		if err := rows.Scan(&r.PostID, &r.UserID, &r.Type, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reaction: %w", err)
+		}
+		reactions = append(reactions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return reactions, nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// ---- Reaction types ----
+const (
+	ReactionLike  = "like"
+	ReactionLove  = "love"
+	ReactionHaha  = "haha"
+	ReactionWow   = "wow"
+	ReactionSad   = "sad"
+	ReactionAngry = "angry"
+)
+
+var ReactionTypes = []string{ReactionLike, ReactionLove, ReactionHaha, ReactionWow, ReactionSad, ReactionAngry}
+
+func IsValidReaction(t string) bool {
+	for _, v := range ReactionTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+type Reaction struct {
+	PostID    string    `json:"post_id"`
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Summary - số reaction theo type của 1 post và reaction của viewer (nếu có)
+type Summary struct {
+	Counts         map[string]int64 `json:"counts"`
+	Total          int64            `json:"total"`
+	ViewerReaction *string          `json:"viewer_reaction,omitempty"`
+}
+
+// ---- Events ----
+const ReactionCreated = "ReactionCreated"
+
+// ReactionEvent được publish khi user react 1 post lần đầu
+// (đổi type hay bỏ reaction không publish, notification-service consume)
+type ReactionEvent struct {
+	Type         string    `json:"type"`
+	PostID       string    `json:"post_id"`
+	PostAuthorID string    `json:"post_author_id"`
+	UserID       string    `json:"user_id"`
+	Reaction     string    `json:"reaction"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"realtimeservice/internal/app"
+	"syscall"
+)
+
+func main() {
+	// 1. Create the Realtime Service app
+	apiApp := app.NewRealtimeServiceApp()
+
+	// 2. Start the app (starts HTTP server)
+	go apiApp.Start()
+	log.Println("🚀 Realtime Service is running...")
+
+	// 3. Graceful shutdown on SIGINT/SIGTERM
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	<-stop
+	log.Println("⚠️ Shutting down Realtime Service...")
+	apiApp.Stop()
+}
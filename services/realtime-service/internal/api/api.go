@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"realtimeservice/internal/core/hub"
+	"realtimeservice/model"
+	"realtimeservice/utils"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	heartbeatInterval = 25 * time.Second // < idle timeout của proxy/load balancer
+	writeTimeout      = 10 * time.Second // client không nhận được trong khoảng này thì đóng
+	pongWait          = 2 * heartbeatInterval
+	sseRetry          = 3 * time.Second // EventSource chờ trước khi reconnect
+)
+
+type Hub interface {
+	Register(userID string) (*hub.Client, error)
+	Unregister(c *hub.Client)
+	Replay(ctx context.Context, userID, lastID string) ([]model.Event, error)
+}
+
+type RealtimeAPI struct {
+	hub      Hub
+	upgrader websocket.Upgrader
+}
+
+func NewRealtimeAPI(hub_ Hub) *RealtimeAPI {
+	return &RealtimeAPI{
+		hub: hub_,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			// chỉ nhận connection qua gateway, origin đã được gateway kiểm tra
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (api *RealtimeAPI) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/realtime/sse", api.handleSSE).Methods("GET")
+	r.HandleFunc("/realtime/ws", api.handleWebSocket).Methods("GET")
+}
+
+// open đăng ký connection và lấy các event client bị lỡ, ghi lỗi HTTP nếu thất bại
+func (api *RealtimeAPI) open(w http.ResponseWriter, r *http.Request, lastID string) (*hub.Client, []model.Event, bool) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, "user not authenticated")
+		return nil, nil, false
+	}
+	if lastID != "" && !model.ValidID(lastID) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid last event id")
+		return nil, nil, false
+	}
+
+	client, err := api.hub.Register(userID)
+	if errors.Is(err, hub.ErrTooManyConnections) {
+		utils.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return nil, nil, false
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, nil, false
+	}
+
+	backlog, err := api.hub.Replay(r.Context(), userID, lastID)
+	if err != nil {
+		api.hub.Unregister(client)
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, nil, false
+	}
+	return client, backlog, true
+}
+
+// isNewer - event live có thể trùng với phần đã replay, chỉ gửi event sau lastID
+func isNewer(id, lastID string) bool {
+	cmp, ok := model.CompareIDs(id, lastID)
+	return !ok || cmp > 0
+}
+
+// handleSSE - GET /realtime/sse, resume bằng header Last-Event-ID hoặc ?last_event_id=
+func (api *RealtimeAPI) handleSSE(w http.ResponseWriter, r *http.Request) {
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+
+	client, backlog, ok := api.open(w, r, lastID)
+	if !ok {
+		return
+	}
+	defer api.hub.Unregister(client)
+
+	// ReadTimeout của server cũng áp lên connection, hết hạn sẽ cancel request
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		log.Printf("[RealtimeAPI] failed to clear read deadline: %v", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// mỗi lần ghi có deadline riêng thay cho WriteTimeout của server
+	write := func(format string, args ...interface{}) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	send := func(event model.Event) error {
+		data := event.Data
+		if data == nil {
+			data = []byte("{}")
+		}
+		// id rỗng (resync khi log trống) reset Last-Event-ID của EventSource
+		return write("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	}
+
+	if err := write("retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+		lastID = event.ID
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client.Done():
+			log.Printf("[RealtimeAPI] closing sse of %s: %s", client.UserID, client.Reason())
+			return
+		case <-ticker.C:
+			if err := write(": ping\n\n"); err != nil {
+				return
+			}
+		case event := <-client.Events():
+			if !isNewer(event.ID, lastID) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+		}
+	}
+}
+
+// handleWebSocket - GET /realtime/ws?last_event_id=, server chỉ gửi {id, type, data},
+// message client gửi lên bị bỏ qua
+func (api *RealtimeAPI) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	lastID := r.URL.Query().Get("last_event_id")
+
+	client, backlog, ok := api.open(w, r, lastID)
+	if !ok {
+		return
+	}
+	defer api.hub.Unregister(client)
+
+	conn, err := api.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade đã tự ghi lỗi HTTP
+		log.Printf("[RealtimeAPI] websocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// đọc để xử lý pong/close, connection chết thì báo cho vòng ghi
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event model.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return conn.WriteJSON(event)
+	}
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+		lastID = event.ID
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-client.Done():
+			reason := client.Reason()
+			log.Printf("[RealtimeAPI] closing websocket of %s: %s", client.UserID, reason)
+			code := websocket.CloseTryAgainLater
+			if reason == hub.ReasonShutdown {
+				code = websocket.CloseGoingAway
+			}
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+				time.Now().Add(writeTimeout))
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case event := <-client.Events():
+			if !isNewer(event.ID, lastID) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+		}
+	}
+}
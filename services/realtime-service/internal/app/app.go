@@ -0,0 +1,75 @@
+package app
+
+import (
+	"log"
+	"realtimeservice/internal/api"
+	"realtimeservice/internal/core/http-server/server"
+	"realtimeservice/internal/core/hub"
+	"realtimeservice/internal/infra/redisclient"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type App struct {
+	httpserver  *server.HttpServer
+	realtimeapi *api.RealtimeAPI
+	hub         *hub.Hub
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DBNumber int
+}
+
+func NewRealtimeServiceApp() *App {
+	var app = &App{}
+	app.init()
+	return app
+}
+
+func (a *App) Start() {
+	if err := a.hub.Start(); err != nil {
+		log.Fatalf("❌ Failed to start hub: %v", err)
+	}
+	if err := a.httpserver.Start(); err != nil {
+		log.Fatalf("❌ Failed to start: %v", err)
+	}
+}
+
+func (a *App) Stop() {
+	// đóng connection trước để http server không phải chờ các stream
+	if err := a.hub.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping hub: %v", err)
+	}
+	if err := a.httpserver.Stop(); err != nil {
+		log.Printf("⚠️ Error stopping server: %v", err)
+	}
+	log.Println("✅ Server stopped gracefully")
+}
+
+// ///////////////////////////////////////////////////////////////////////////////////////
+func (a *App) init() {
+	redisstorecfg := &RedisConfig{
+		Host:     "localhost",
+		Port:     "6379",
+		Password: "",
+		DBNumber: 0,
+	}
+	rc := redisclient.InitSingleton(redisstorecfg.Host+":"+redisstorecfg.Port, redisstorecfg.Password, redisstorecfg.DBNumber)
+
+	a.hub = hub.NewHub(rc, hub.Config{
+		SendBuffer:       64,
+		MaxConnsPerUser:  5,
+		PresenceTTL:      10 * time.Minute,
+		PresenceInterval: 3 * time.Minute,
+		ReplayLimit:      100,
+	})
+
+	a.realtimeapi = api.NewRealtimeAPI(a.hub)
+	router := mux.NewRouter()
+	a.realtimeapi.RegisterRoutes(router)
+	a.httpserver = server.NewHttpServer("localhost:9096", router)
+}
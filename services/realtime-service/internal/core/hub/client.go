@@ -0,0 +1,72 @@
+package hub
+
+import (
+	"realtimeservice/model"
+	"sync"
+)
+
+// Lý do hub đóng connection
+const (
+	ReasonSlowConsumer = "slow consumer"
+	ReasonShutdown     = "server shutting down"
+	reasonClosed       = "closed"
+)
+
+// Client là 1 connection SSE/WebSocket của user. Hub đẩy event vào buffer send,
+// handler của connection đọc Events() và ghi ra network.
+type Client struct {
+	UserID string
+
+	send      chan model.Event
+	done      chan struct{}
+	closeOnce sync.Once
+	reason    string
+}
+
+func newClient(userID string, bufferSize int) *Client {
+	return &Client{
+		UserID: userID,
+		send:   make(chan model.Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Events - event live của user theo thứ tự publish
+func (c *Client) Events() <-chan model.Event {
+	return c.send
+}
+
+// Done đóng khi hub bỏ connection (slow consumer, hub dừng), handler phải đóng connection
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Reason - lý do hub bỏ connection, chỉ có giá trị sau khi Done() đóng
+func (c *Client) Reason() string {
+	<-c.done
+	return c.reason
+}
+
+// deliver không bao giờ block dispatcher: buffer đầy nghĩa là client đọc chậm,
+// đóng connection để client reconnect và resume từ Last-Event-ID
+func (c *Client) deliver(event model.Event) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- event:
+		return true
+	default:
+		c.close(ReasonSlowConsumer)
+		return false
+	}
+}
+
+func (c *Client) close(reason string) {
+	c.closeOnce.Do(func() {
+		c.reason = reason
+		close(c.done)
+	})
+}
@@ -0,0 +1,285 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"realtimeservice/internal/infra/redisclient"
+	"realtimeservice/model"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrTooManyConnections = errors.New("too many realtime connections")
+
+type Config struct {
+	SendBuffer       int           // số event chờ ghi tối đa của 1 connection
+	MaxConnsPerUser  int           // số tab/thiết bị mở cùng lúc
+	PresenceTTL      time.Duration // sau khi ngắt kết nối, publisher còn ghi log trong khoảng này để resume
+	PresenceInterval time.Duration // chu kỳ gia hạn presence của user đang kết nối, < PresenceTTL
+	ReplayLimit      int64         // thiếu nhiều event hơn thì gửi resync thay vì replay
+}
+
+// Hub giữ 1 subscription Redis cho cả instance: channel user:{id}:events được
+// SUBSCRIBE khi user có connection đầu tiên và UNSUBSCRIBE khi connection cuối đóng.
+type Hub struct {
+	redisclient *redisclient.RedisClient
+	cfg         Config
+
+	mu          sync.Mutex
+	clients     map[string]map[*Client]struct{}
+	subscribing map[string]*subscription // user đang SUBSCRIBE (ngoài mu) cho connection đầu tiên
+	pubsub      *redis.PubSub
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// subscription - kết quả SUBSCRIBE channel của 1 user, done đóng khi xong
+type subscription struct {
+	done chan struct{}
+	err  error
+}
+
+func NewHub(redisclient_ *redisclient.RedisClient, cfg Config) *Hub {
+	return &Hub{
+		redisclient: redisclient_,
+		cfg:         cfg,
+		clients:     make(map[string]map[*Client]struct{}),
+		subscribing: make(map[string]*subscription),
+		stop:        make(chan struct{}),
+	}
+}
+
+func (h *Hub) Start() error {
+	h.pubsub = h.redisclient.GetClient().Subscribe(context.Background())
+
+	h.wg.Add(2)
+	go h.dispatch()
+	go h.refreshPresence()
+	log.Printf("✅ [Hub] started")
+	return nil
+}
+
+// Stop đóng subscription và mọi connection đang mở
+func (h *Hub) Stop() error {
+	close(h.stop)
+	err := h.pubsub.Close()
+
+	h.mu.Lock()
+	for _, clients := range h.clients {
+		for c := range clients {
+			c.close(ReasonShutdown)
+		}
+	}
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	return err
+}
+
+// Register thêm connection của user, subscribe channel nếu là connection đầu tiên.
+// Gọi Register trước Replay để không lỡ event publish trong lúc đọc log.
+// SUBSCRIBE chạy ngoài h.mu để dispatch và Register của user khác không phải chờ Redis;
+// connection được thêm vào trước nên Unregister của connection khác không unsubscribe giữa chừng,
+// Register khác của cùng user thì chờ subscription đó xong.
+func (h *Hub) Register(userID string) (*Client, error) {
+	c := newClient(userID, h.cfg.SendBuffer)
+
+	h.mu.Lock()
+	clients := h.clients[userID]
+	if h.cfg.MaxConnsPerUser > 0 && len(clients) >= h.cfg.MaxConnsPerUser {
+		h.mu.Unlock()
+		return nil, ErrTooManyConnections
+	}
+	first := len(clients) == 0
+	if first {
+		clients = make(map[*Client]struct{})
+		h.clients[userID] = clients
+		h.subscribing[userID] = &subscription{done: make(chan struct{})}
+	}
+	clients[c] = struct{}{}
+	sub := h.subscribing[userID]
+	h.mu.Unlock()
+
+	if first {
+		err := h.pubsub.Subscribe(context.Background(), model.ChannelKey(userID))
+
+		h.mu.Lock()
+		delete(h.subscribing, userID)
+		if err != nil {
+			// mọi connection hiện có của user đều đang chờ subscription này, bỏ hết;
+			// Register sau đó sẽ là connection đầu tiên và subscribe lại
+			delete(h.clients, userID)
+		}
+		h.mu.Unlock()
+
+		sub.err = err
+		close(sub.done)
+	} else if sub != nil {
+		<-sub.done
+	}
+	if sub != nil && sub.err != nil {
+		return nil, fmt.Errorf("[Hub] failed to subscribe %s: %w", userID, sub.err)
+	}
+
+	// presence phải có trước khi đọc log, nếu không publisher có thể bỏ qua event
+	if err := h.redisclient.SetKey(model.PresenceKey(userID), 1, h.cfg.PresenceTTL); err != nil {
+		log.Printf("[Hub] failed to set presence of %s: %v", userID, err)
+	}
+	return c, nil
+}
+
+// Unregister bỏ connection, unsubscribe khi user không còn connection nào.
+// Presence giữ nguyên tới hết TTL để client reconnect resume được.
+func (h *Hub) Unregister(c *Client) {
+	c.close(reasonClosed)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.clients[c.UserID]
+	if _, ok := clients[c]; !ok {
+		return
+	}
+	delete(clients, c)
+	if len(clients) > 0 {
+		return
+	}
+	delete(h.clients, c.UserID)
+	if err := h.pubsub.Unsubscribe(context.Background(), model.ChannelKey(c.UserID)); err != nil {
+		log.Printf("[Hub] failed to unsubscribe %s: %v", c.UserID, err)
+	}
+}
+
+// Replay trả về các event sau lastID trong log của user.
+// lastID không còn trong log (đã trim/expire) hoặc thiếu quá ReplayLimit event thì
+// trả về 1 event Resync mang ID mới nhất để client load lại và tiếp tục từ đó.
+func (h *Hub) Replay(ctx context.Context, userID, lastID string) ([]model.Event, error) {
+	if lastID == "" {
+		return nil, nil
+	}
+	rdb := h.redisclient.GetClient()
+	logKey := model.LogKey(userID)
+
+	found, err := rdb.XRange(ctx, logKey, lastID, lastID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("[Hub] failed to read event log of %s: %w", userID, err)
+	}
+	if len(found) == 0 {
+		return h.resync(ctx, logKey)
+	}
+
+	entries, err := rdb.XRangeN(ctx, logKey, "("+lastID, "+", h.cfg.ReplayLimit+1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("[Hub] failed to read event log of %s: %w", userID, err)
+	}
+	if int64(len(entries)) > h.cfg.ReplayLimit {
+		return h.resync(ctx, logKey)
+	}
+
+	events := make([]model.Event, 0, len(entries))
+	for _, entry := range entries {
+		eventType, _ := entry.Values["type"].(string)
+		data, _ := entry.Values["data"].(string)
+		events = append(events, model.Event{ID: entry.ID, Type: eventType, Data: rawData(data)})
+	}
+	return events, nil
+}
+
+func (h *Hub) resync(ctx context.Context, logKey string) ([]model.Event, error) {
+	latest, err := h.redisclient.GetClient().XRevRangeN(ctx, logKey, "+", "-", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("[Hub] failed to read latest event: %w", err)
+	}
+	event := model.Event{Type: model.Resync}
+	if len(latest) > 0 {
+		event.ID = latest[0].ID
+	}
+	return []model.Event{event}, nil
+}
+
+// dispatch chuyển message pub/sub tới các connection của user, không block khi client chậm
+func (h *Hub) dispatch() {
+	defer h.wg.Done()
+
+	type message struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data string `json:"data"`
+	}
+
+	for msg := range h.pubsub.Channel() {
+		userID, ok := model.UserIDFromChannel(msg.Channel)
+		if !ok {
+			continue
+		}
+		var m message
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			log.Printf("[Hub] invalid message on %s: %v", msg.Channel, err)
+			continue
+		}
+		event := model.Event{ID: m.ID, Type: m.Type, Data: rawData(m.Data)}
+
+		h.mu.Lock()
+		for c := range h.clients[userID] {
+			if !c.deliver(event) {
+				log.Printf("[Hub] dropped slow connection of %s", userID)
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+// refreshPresence gia hạn presence của mọi user đang kết nối trong 1 pipeline
+func (h *Hub) refreshPresence() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.cfg.PresenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+
+		h.mu.Lock()
+		userIDs := make([]string, 0, len(h.clients))
+		for userID := range h.clients {
+			userIDs = append(userIDs, userID)
+		}
+		h.mu.Unlock()
+		if len(userIDs) == 0 {
+			continue
+		}
+
+		ctx := context.Background()
+		_, err := h.redisclient.GetClient().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, userID := range userIDs {
+				pipe.Set(ctx, model.PresenceKey(userID), 1, h.cfg.PresenceTTL)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("[Hub] failed to refresh presence of %d users: %v", len(userIDs), err)
+		}
+	}
+}
+
+// rawData - data publisher gửi là JSON đã encode, giữ nguyên; không hợp lệ thì gửi dạng string
+func rawData(data string) json.RawMessage {
+	if data == "" {
+		return nil
+	}
+	if json.Valid([]byte(data)) {
+		return json.RawMessage(data)
+	}
+	quoted, _ := json.Marshal(data)
+	return quoted
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Event types do realtime-service tự sinh, các type còn lại (feed.new_post,
+// notification.new, ...) do service khác publish và được chuyển nguyên cho client
+const (
+	// Resync báo client không resume được (log đã bị trim/expire), cần load lại feed/inbox
+	Resync = "resync"
+)
+
+// Event là 1 entry trong user:{id}:events:log, ID là stream ID dùng làm Last-Event-ID
+type Event struct {
+	ID   string          `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// ChannelKey - pub/sub channel của 1 user, publisher PUBLISH {id, type, data}
+func ChannelKey(userID string) string {
+	return "user:" + userID + ":events"
+}
+
+// UserIDFromChannel tách user_id từ tên channel, false nếu không đúng format
+func UserIDFromChannel(channel string) (string, bool) {
+	s, ok := strings.CutPrefix(channel, "user:")
+	if !ok {
+		return "", false
+	}
+	s, ok = strings.CutSuffix(s, ":events")
+	return s, ok && s != ""
+}
+
+// LogKey - stream giữ các event gần nhất để resume
+func LogKey(userID string) string {
+	return "user:" + userID + ":events:log"
+}
+
+// PresenceKey - còn key này thì publisher mới ghi event cho user
+func PresenceKey(userID string) string {
+	return "user:" + userID + ":events:presence"
+}
+
+// CompareIDs so sánh 2 stream ID "<ms>-<seq>", ID rỗng nhỏ hơn mọi ID.
+// ok = false nếu ID không hợp lệ.
+func CompareIDs(a, b string) (cmp int, ok bool) {
+	am, as, ok := parseID(a)
+	if !ok {
+		return 0, false
+	}
+	bm, bs, ok := parseID(b)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case am != bm:
+		if am < bm {
+			return -1, true
+		}
+		return 1, true
+	case as != bs:
+		if as < bs {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, true
+}
+
+// ValidID kiểm tra Last-Event-ID client gửi lên
+func ValidID(id string) bool {
+	_, _, ok := parseID(id)
+	return ok && id != ""
+}
+
+func parseID(id string) (uint64, uint64, bool) {
+	if id == "" {
+		return 0, 0, true
+	}
+	msRaw, seqRaw, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msRaw, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(seqRaw, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}